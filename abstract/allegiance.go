@@ -0,0 +1,25 @@
+package abstract
+
+// CharacterPets Pet types that were linked to a character, like "Wolf" for "Wolf #1423"
+type CharacterPets struct {
+	Character string
+	Pets      []string
+}
+
+func NewLearnedPets() *LearnedPets {
+	return &LearnedPets{}
+}
+
+type LearnedPets struct {
+	Characters []CharacterPets
+}
+
+func (l *LearnedPets) PetsOf(character string) []string {
+	for _, characterPets := range l.Characters {
+		if characterPets.Character == character {
+			return characterPets.Pets
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package abstract
+
+import (
+	_ "embed"
+	"encoding/json"
+)
+
+const OtherCategory = "Other"
+
+//go:embed categories/default.json
+var defaultCategories []byte
+
+// DefaultSkillCategories Categories that come with the tracker, they are written to a file so the user can change them
+func DefaultSkillCategories() *SkillCategories {
+	categories := &SkillCategories{}
+
+	err := json.Unmarshal(defaultCategories, categories)
+	if err != nil {
+		panic(err)
+	}
+
+	return categories
+}
+
+// SkillCategories Maps abilities to skills they belong to, like "Fire Bolt" to "Fire Magic", and skills to categories, like "Fire Magic" to "Combat"
+type SkillCategories struct {
+	Skills    map[string]string
+	Abilities map[string]string
+}
+
+// SkillOf Skill the ability belongs to, skill names are returned as they are
+func (c *SkillCategories) SkillOf(name string) string {
+	if skill, ok := c.Abilities[name]; ok {
+		return skill
+	}
+
+	if _, ok := c.Skills[name]; ok {
+		return name
+	}
+
+	return OtherCategory
+}
+
+// CategoryOf Category of the ability or skill
+func (c *SkillCategories) CategoryOf(name string) string {
+	if category, ok := c.Skills[c.SkillOf(name)]; ok {
+		return category
+	}
+
+	return OtherCategory
+}
@@ -0,0 +1,43 @@
+package abstract
+
+import (
+	"PGCombatTracker/utils"
+	"fmt"
+	"time"
+)
+
+// CharacterSession Period of time during which a character was logged in
+type CharacterSession struct {
+	Name string
+	From time.Time
+	To   time.Time
+}
+
+func (s CharacterSession) Duration() time.Duration {
+	return s.To.Sub(s.From)
+}
+
+func (s CharacterSession) String() string {
+	return fmt.Sprintf("'%v' from '%v' to '%v'", s.Name, s.From.Format(time.DateTime), s.To.Format(time.DateTime))
+}
+
+// CharacterSummary Short overview of what a character did in a log file
+type CharacterSummary struct {
+	Name        string
+	Sessions    int
+	PlayTime    time.Duration
+	DamageDealt int
+	XPGained    int
+	Deaths      int
+}
+
+func (s CharacterSummary) String() string {
+	return fmt.Sprintf(
+		"%v: %v online, %v damage, %v XP, %v deaths",
+		s.Name,
+		s.PlayTime.Truncate(time.Minute),
+		utils.FormatNumber(s.DamageDealt),
+		utils.FormatNumber(s.XPGained),
+		s.Deaths,
+	)
+}
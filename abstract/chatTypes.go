@@ -20,6 +20,10 @@ type Login struct {
 	Name string
 }
 
+type AreaChange struct {
+	Area string
+}
+
 type SkillUse struct {
 	Subject  string
 	Skill    string
@@ -83,6 +87,11 @@ func (event *Login) String() string {
 	return fmt.Sprintf("**** Logged in as '%v' ****", event.Name)
 }
 
+func (event *AreaChange) ImplementsChatContent() {}
+func (event *AreaChange) String() string {
+	return fmt.Sprintf("**** Entering area '%v' ****", event.Area)
+}
+
 func (event *SkillUse) ImplementsChatContent() {}
 func (event *SkillUse) String() string {
 	var fatality string
@@ -14,3 +14,13 @@ type Collector interface {
 	UI(state LayeredState) (layout.Widget, []layout.Widget)
 	Export(state LayeredState) image.Image
 }
+
+// SnapshotCollector Collector whose data can be served as JSON by the local API, called while holding the read lock
+type SnapshotCollector interface {
+	Snapshot() any
+}
+
+// FinishingCollector Collector that keeps data until time moves past it, finished once a file that isn't watched was read to the end
+type FinishingCollector interface {
+	Finish(info StatisticsInformation)
+}
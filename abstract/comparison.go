@@ -0,0 +1,22 @@
+package abstract
+
+import "PGCombatTracker/ui/components"
+
+// ComparisonRow Single value of a tab, rows with the same metric and name are compared between two sessions
+type ComparisonRow struct {
+	Metric string
+	Name   string
+	Value  float64
+	// LowerIsBetter Decides which way the difference gets colored, like for damage taken
+	LowerIsBetter bool
+}
+
+// ComparableCollector Collector that can be shown as a list of differences on the comparison page
+type ComparableCollector interface {
+	ComparisonRows() []ComparisonRow
+}
+
+// FightSource Collector that can split the session into fights, every fight is a list of DPS points
+type FightSource interface {
+	FightDPS() [][]components.TimePoint
+}
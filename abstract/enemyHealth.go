@@ -0,0 +1,120 @@
+package abstract
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// EnemyKill Health and armor damage an enemy took before it died
+type EnemyKill struct {
+	EnemyType string
+	// Instance Name with the id, like "Goblin #1423"
+	Instance string
+	Pool     int
+	At       time.Time
+}
+
+// key Instance can only die once at a time, so together with the time it tells kills apart within a file
+func (k EnemyKill) key() string {
+	return fmt.Sprintf("%v@%d", k.Instance, k.At.Unix())
+}
+
+// EnemyHealthEstimate Running mean and variance of health pools seen for an enemy type
+type EnemyHealthEstimate struct {
+	EnemyType  string
+	Samples    int
+	Mean       float64
+	M2         float64
+	LastSample time.Time
+}
+
+// Add Welford's online algorithm, so we don't need to keep every sample around
+func (e EnemyHealthEstimate) Add(pool int, at time.Time) EnemyHealthEstimate {
+	e.Samples++
+	delta := float64(pool) - e.Mean
+	e.Mean += delta / float64(e.Samples)
+	e.M2 += delta * (float64(pool) - e.Mean)
+	e.LastSample = at
+	return e
+}
+
+func (e EnemyHealthEstimate) Variance() float64 {
+	if e.Samples < 2 {
+		return 0
+	}
+
+	return e.M2 / float64(e.Samples-1)
+}
+
+func (e EnemyHealthEstimate) StdDev() float64 {
+	return math.Sqrt(e.Variance())
+}
+
+func (e EnemyHealthEstimate) String() string {
+	return fmt.Sprintf("%.0f ± %.0f HP from %v kills", e.Mean, e.StdDev(), e.Samples)
+}
+
+func NewEnemyHealthDatabase() *EnemyHealthDatabase {
+	return &EnemyHealthDatabase{}
+}
+
+type EnemyHealthDatabase struct {
+	Enemies []EnemyHealthEstimate
+	// Learned Kills that were already counted by log file, so reading a file again doesn't count them twice
+	Learned map[string][]string
+
+	learnedIndex map[string]map[string]bool
+}
+
+func (d *EnemyHealthDatabase) learned(path string) map[string]bool {
+	if d.learnedIndex == nil {
+		d.learnedIndex = make(map[string]map[string]bool)
+	}
+
+	index, ok := d.learnedIndex[path]
+	if !ok {
+		index = make(map[string]bool)
+		for _, key := range d.Learned[path] {
+			index[key] = true
+		}
+		d.learnedIndex[path] = index
+	}
+
+	return index
+}
+
+func (d *EnemyHealthDatabase) Find(enemyType string) (EnemyHealthEstimate, bool) {
+	for _, estimate := range d.Enemies {
+		if estimate.EnemyType == enemyType {
+			return estimate, true
+		}
+	}
+
+	return EnemyHealthEstimate{}, false
+}
+
+// Learn Adds the kill to the estimate, kills that were already learned from the same file are ignored
+func (d *EnemyHealthDatabase) Learn(path string, kill EnemyKill) bool {
+	index := d.learned(path)
+	key := kill.key()
+	if index[key] {
+		return false
+	}
+
+	index[key] = true
+	if d.Learned == nil {
+		d.Learned = make(map[string][]string)
+	}
+	d.Learned[path] = append(d.Learned[path], key)
+
+	for i, estimate := range d.Enemies {
+		if estimate.EnemyType == kill.EnemyType {
+			d.Enemies[i] = estimate.Add(kill.Pool, kill.At)
+			return true
+		}
+	}
+
+	d.Enemies = append(d.Enemies, EnemyHealthEstimate{EnemyType: kill.EnemyType}.Add(kill.Pool, kill.At))
+	return true
+}
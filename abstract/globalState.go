@@ -9,10 +9,20 @@ import (
 type GlobalState interface {
 	SettingsBearer
 	MarkersBearer
+	IdentityBearer
+	PetsBearer
+	EnemyHealthBearer
+	XPTableBearer
+	LibraryBearer
+	RecordBearer
+	ToastBearer
+	WebhookBearer
 	StatisticsBearer
 	PageSwitcher
 
 	GorgonFolder() string
+	// SkillCategories Loaded once at startup and only read afterwards
+	SkillCategories() *SkillCategories
 
 	Storage() map[string]any
 	Window() *app.Window
@@ -25,6 +35,8 @@ type GlobalState interface {
 
 type SettingsBearer interface {
 	Settings() *Settings
+	// SavedSettings Copy of the settings as they were last loaded or saved, for goroutines other than the UI one
+	SavedSettings() Settings
 	ReloadSettings()
 	SaveSettings()
 }
@@ -35,9 +47,70 @@ type MarkersBearer interface {
 	SaveMarker(path, name, user string)
 }
 
+type IdentityBearer interface {
+	FindIdentity(path string) (FileIdentity, bool)
+	SaveIdentity(identity FileIdentity)
+}
+
+// PetsBearer Can be used from any goroutine, pets are saved by type
+type PetsBearer interface {
+	KnownPets(character string) []string
+	LearnPet(character, pet string)
+}
+
+// EnemyHealthBearer Can be used from any goroutine
+type EnemyHealthBearer interface {
+	EnemyHealth(enemyType string) (EnemyHealthEstimate, bool)
+	// LearnEnemyHealth Kills are told apart by the log file they came from
+	LearnEnemyHealth(path string, kills []EnemyKill)
+}
+
+// XPTableBearer Can be used from any goroutine, table is nil if the user didn't supply one
+type XPTableBearer interface {
+	XPTable() *XPTable
+}
+
+// LibraryBearer Can be used from any goroutine
+type LibraryBearer interface {
+	// ScanLibrary Reads files of the logs folder that changed since the last scan in background
+	ScanLibrary()
+	LibraryScanning() bool
+	LibrarySessions(character string) []SessionSummary
+	// LibraryFileSessions Sessions of the file as of the last time it was scanned
+	LibraryFileSessions(path string) ([]SessionSummary, bool)
+	LibraryCharacters() []string
+}
+
+// RecordBearer Can be used from any goroutine
+type RecordBearer interface {
+	// OfferRecords Keeps records that are better than known ones, broken records are shown as toasts if announced
+	OfferRecords(records []PersonalRecord, announce bool)
+	Records(character string) []PersonalRecord
+	RecordCharacters() []string
+}
+
+// ToastBearer Can be used from any goroutine
+type ToastBearer interface {
+	ShowToast(text string)
+	// ShowAlert Banner for things that need attention right away
+	ShowAlert(text string)
+	Toaster() *components.Toaster
+}
+
+// WebhookBearer Can be used from any goroutine, except for SendQueuedReports
+type WebhookBearer interface {
+	// SendReport Queues the report, collectors are exported on the next frame and posted in background
+	SendReport(trigger ReportTrigger, character, path string, collectors []Collector)
+	// SendQueuedReports Exports queued reports, has to be called on the UI goroutine since UI changes collectors too
+	SendQueuedReports()
+	WebhookDeliveries() []WebhookDelivery
+}
+
 type StatisticsBearer interface {
 	StatisticsCollector() StatisticsCollector
 	OpenFile(path string, watch bool, timeFrames []MarkerTimeFrame) bool
+	// CreateStatisticsCollector Collector that doesn't replace the current one and doesn't watch the file, caller runs and closes it
+	CreateStatisticsCollector(path string, timeFrames []MarkerTimeFrame) (StatisticsCollector, error)
 }
 
 type PageSwitcher interface {
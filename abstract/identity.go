@@ -0,0 +1,71 @@
+package abstract
+
+import "fmt"
+
+type IdentitySource uint8
+
+const (
+	IdentityUnknown IdentitySource = iota
+	IdentityLogin
+	IdentityHeuristic
+	IdentityManual
+)
+
+func (s IdentitySource) String() string {
+	switch s {
+	case IdentityLogin:
+		return "login line"
+	case IdentityHeuristic:
+		return "guessed from combat"
+	case IdentityManual:
+		return "set manually"
+	}
+
+	return "not detected"
+}
+
+// Priority How much the source can be trusted, manually set names are always trusted the most
+func (s IdentitySource) Priority() int {
+	switch s {
+	case IdentityManual:
+		return 3
+	case IdentityLogin:
+		return 2
+	case IdentityHeuristic:
+		return 1
+	}
+
+	return 0
+}
+
+// FileIdentity Character name that was found for a log file, and how it was found
+type FileIdentity struct {
+	Path   string
+	Name   string
+	Source IdentitySource
+}
+
+func (i FileIdentity) String() string {
+	if i.Name == "" {
+		return "Character not detected"
+	}
+
+	return fmt.Sprintf("%v (%v)", i.Name, i.Source)
+}
+
+func NewIdentities() *Identities {
+	return &Identities{}
+}
+
+type Identities struct {
+	Files []FileIdentity
+}
+
+// BetterThan Checks if the identity should replace the other one
+func (i FileIdentity) BetterThan(other FileIdentity) bool {
+	if i.Name == "" {
+		return false
+	}
+
+	return other.Name == "" || i.Source.Priority() > other.Source.Priority()
+}
@@ -0,0 +1,153 @@
+package abstract
+
+import (
+	"slices"
+	"time"
+)
+
+// SessionSummary Results of one login of a character, kept in the library so trends can be shown later
+type SessionSummary struct {
+	Character string
+	From      time.Time
+	To        time.Time
+	// ActiveTime Time spent fighting, used for DPS
+	ActiveTime  time.Duration
+	DamageDealt int
+	DamageTaken int
+	Deaths      int
+	XPGained    int
+	Coins       int
+}
+
+func (s SessionSummary) Duration() time.Duration {
+	return s.To.Sub(s.From)
+}
+
+func perHour(value int, duration time.Duration) float64 {
+	return float64(value) / max(duration, time.Minute).Hours()
+}
+
+func (s SessionSummary) DPS() float64 {
+	if s.ActiveTime <= 0 {
+		return 0
+	}
+	return float64(s.DamageDealt) / s.ActiveTime.Seconds()
+}
+
+func (s SessionSummary) DamageTakenPerHour() float64 {
+	return perHour(s.DamageTaken, s.Duration())
+}
+
+func (s SessionSummary) XPPerHour() float64 {
+	return perHour(s.XPGained, s.Duration())
+}
+
+func (s SessionSummary) CoinsPerHour() float64 {
+	return perHour(s.Coins, s.Duration())
+}
+
+// Merge Adds up both sessions as if they were played back to back, so breaks between them don't lower the rates
+func (s SessionSummary) Merge(other SessionSummary) SessionSummary {
+	if s.Character != other.Character {
+		s.Character = ""
+	}
+
+	duration := s.Duration() + other.Duration()
+	if other.From.Before(s.From) {
+		s.From = other.From
+	}
+	s.To = s.From.Add(duration)
+
+	s.ActiveTime += other.ActiveTime
+	s.DamageDealt += other.DamageDealt
+	s.DamageTaken += other.DamageTaken
+	s.Deaths += other.Deaths
+	s.XPGained += other.XPGained
+	s.Coins += other.Coins
+
+	return s
+}
+
+// LibraryFile Sessions of a log file, size and modification time tell if the file has to be read again
+type LibraryFile struct {
+	Path     string
+	Size     int64
+	ModTime  time.Time
+	Sessions []SessionSummary
+}
+
+func NewSessionLibrary() *SessionLibrary {
+	return &SessionLibrary{}
+}
+
+// SessionLibrary Summaries of every log file that was scanned
+type SessionLibrary struct {
+	Files []LibraryFile
+}
+
+// IsCurrent Checks if the file is in the library and didn't change since
+func (l *SessionLibrary) IsCurrent(path string, size int64, modTime time.Time) bool {
+	for _, file := range l.Files {
+		if file.Path == path {
+			return file.Size == size && file.ModTime.Equal(modTime)
+		}
+	}
+
+	return false
+}
+
+func (l *SessionLibrary) Store(file LibraryFile) {
+	for i := range l.Files {
+		if l.Files[i].Path == file.Path {
+			l.Files[i] = file
+			return
+		}
+	}
+
+	l.Files = append(l.Files, file)
+}
+
+// Sessions Sessions of the character sorted by time, empty character means everyone
+func (l *SessionLibrary) Sessions(character string) []SessionSummary {
+	var sessions []SessionSummary
+
+	for _, file := range l.Files {
+		for _, session := range file.Sessions {
+			if character == "" || session.Character == character {
+				sessions = append(sessions, session)
+			}
+		}
+	}
+
+	slices.SortFunc(sessions, func(a, b SessionSummary) int {
+		return a.From.Compare(b.From)
+	})
+
+	return sessions
+}
+
+func (l *SessionLibrary) FileSessions(path string) ([]SessionSummary, bool) {
+	for _, file := range l.Files {
+		if file.Path == path {
+			return slices.Clone(file.Sessions), true
+		}
+	}
+
+	return nil, false
+}
+
+func (l *SessionLibrary) Characters() []string {
+	var characters []string
+
+	for _, file := range l.Files {
+		for _, session := range file.Sessions {
+			if !slices.Contains(characters, session.Character) {
+				characters = append(characters, session.Character)
+			}
+		}
+	}
+
+	slices.Sort(characters)
+
+	return characters
+}
@@ -9,7 +9,8 @@ type Marker struct {
 	Time        time.Time
 	Name        string
 	User        string
-	UserDefined bool `json:"-"`
+	Area        string `json:",omitempty"`
+	UserDefined bool   `json:"-"`
 }
 
 func (m Marker) AsUserDefined() Marker {
@@ -42,9 +43,14 @@ type MarkerTimeFrame struct {
 	User string
 	From time.Time
 	To   time.Time
+	Area string
 }
 
 func (t MarkerTimeFrame) String() string {
+	if t.Area != "" {
+		return fmt.Sprintf("From '%v' to '%v' as '%v' in '%v'", t.From.Format(time.DateTime), t.To.Format(time.DateTime), t.User, t.Area)
+	}
+
 	return fmt.Sprintf("From '%v' to '%v' as '%v'", t.From.Format(time.DateTime), t.To.Format(time.DateTime), t.User)
 }
 func (t MarkerTimeFrame) Within(time time.Time) bool {
@@ -59,3 +65,18 @@ func WithinTimeFrames(timeFrames []MarkerTimeFrame, time time.Time) (bool, strin
 
 	return false, ""
 }
+
+// WithinAreas Checks if any of the time frames allow data from the area, time frames without area allow everything
+func WithinAreas(timeFrames []MarkerTimeFrame, area string) bool {
+	if len(timeFrames) == 0 {
+		return true
+	}
+
+	for _, timeFrame := range timeFrames {
+		if timeFrame.Area == "" || timeFrame.Area == area {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,38 @@
+package abstract
+
+type MetricType uint8
+
+const (
+	MetricCounter MetricType = iota
+	MetricGauge
+)
+
+func (t MetricType) String() string {
+	switch t {
+	case MetricCounter:
+		return "counter"
+	case MetricGauge:
+		return "gauge"
+	}
+
+	return "unknown"
+}
+
+type MetricLabel struct {
+	Name  string
+	Value string
+}
+
+// Metric Single sample of a metric family, samples with the same name have to share the type and help
+type Metric struct {
+	Name   string
+	Help   string
+	Type   MetricType
+	Labels []MetricLabel
+	Value  float64
+}
+
+// MetricSource Collector that can turn data of a character into metrics, called while holding the read lock
+type MetricSource interface {
+	Metrics(character string) []Metric
+}
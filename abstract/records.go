@@ -0,0 +1,161 @@
+package abstract
+
+import (
+	"PGCombatTracker/utils"
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+type RecordKind uint8
+
+const (
+	RecordHighestHit RecordKind = iota
+	RecordBurstDPS
+	RecordFightDPS
+	RecordHourXP
+	RecordDeathless
+)
+
+// BurstWindow Length of the window burst DPS is measured over
+const BurstWindow = 10 * time.Second
+
+func (k RecordKind) String() string {
+	switch k {
+	case RecordHighestHit:
+		return "Highest hit"
+	case RecordBurstDPS:
+		return "Best 10s burst DPS"
+	case RecordFightDPS:
+		return "Best fight DPS"
+	case RecordHourXP:
+		return "Most XP in an hour"
+	case RecordDeathless:
+		return "Longest deathless streak"
+	}
+
+	return ""
+}
+
+// Format Deathless streaks are stored in seconds, everything else is a plain number
+func (k RecordKind) Format(value float64) string {
+	if k == RecordDeathless {
+		return time.Duration(value * float64(time.Second)).Round(time.Second).String()
+	}
+
+	return utils.FormatNumber(int(math.Round(value)))
+}
+
+// PersonalRecord Best value a character reached, higher is always better
+type PersonalRecord struct {
+	Character string
+	Kind      RecordKind
+	// Name Skill or enemy type the record is for, empty for kinds that aren't split
+	Name  string
+	Value float64
+	At    time.Time
+	// Path Log file the record was found in
+	Path string
+}
+
+func (r PersonalRecord) Title() string {
+	if r.Name == "" {
+		return r.Kind.String()
+	}
+
+	return fmt.Sprintf("%v: %v", r.Kind, r.Name)
+}
+
+func (r PersonalRecord) FormattedValue() string {
+	return r.Kind.Format(r.Value)
+}
+
+func (r PersonalRecord) sameAs(other PersonalRecord) bool {
+	return r.Character == other.Character && r.Kind == other.Kind && r.Name == other.Name
+}
+
+// BrokenRecord Record that beat an older one
+type BrokenRecord struct {
+	Record   PersonalRecord
+	Previous PersonalRecord
+}
+
+func (b BrokenRecord) String() string {
+	return fmt.Sprintf(
+		"New record for %v! %v: %v (was %v)",
+		b.Record.Character,
+		b.Record.Title(),
+		b.Record.FormattedValue(),
+		b.Previous.FormattedValue(),
+	)
+}
+
+// RecordSource Collector that can find personal records of a character in the data it gathered
+type RecordSource interface {
+	Records(character string) []PersonalRecord
+}
+
+func NewRecordBook() *RecordBook {
+	return &RecordBook{}
+}
+
+// RecordBook Best records of every character across all files that were analyzed
+type RecordBook struct {
+	Entries []PersonalRecord
+}
+
+// Offer Keeps the record if it's the first of its kind or beats the known one,
+// only beating a known record counts as broken
+func (b *RecordBook) Offer(record PersonalRecord) (previous PersonalRecord, stored, broken bool) {
+	for i, entry := range b.Entries {
+		if !entry.sameAs(record) {
+			continue
+		}
+
+		if record.Value <= entry.Value {
+			return entry, false, false
+		}
+
+		b.Entries[i] = record
+		return entry, true, true
+	}
+
+	b.Entries = append(b.Entries, record)
+	return PersonalRecord{}, true, false
+}
+
+// Records Records of the character ordered by kind and name
+func (b *RecordBook) Records(character string) []PersonalRecord {
+	var records []PersonalRecord
+
+	for _, entry := range b.Entries {
+		if entry.Character == character {
+			records = append(records, entry)
+		}
+	}
+
+	slices.SortFunc(records, func(a, b PersonalRecord) int {
+		if a.Kind != b.Kind {
+			return cmp.Compare(a.Kind, b.Kind)
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return records
+}
+
+func (b *RecordBook) Characters() []string {
+	var characters []string
+
+	for _, entry := range b.Entries {
+		if !slices.Contains(characters, entry.Character) {
+			characters = append(characters, entry.Character)
+		}
+	}
+
+	slices.Sort(characters)
+
+	return characters
+}
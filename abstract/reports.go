@@ -0,0 +1,51 @@
+package abstract
+
+import "time"
+
+type ReportTrigger uint8
+
+const (
+	ReportOnDemand ReportTrigger = iota
+	ReportSessionEnd
+	ReportEncounterEnd
+)
+
+func (t ReportTrigger) String() string {
+	switch t {
+	case ReportOnDemand:
+		return "Report"
+	case ReportSessionEnd:
+		return "Session ended"
+	case ReportEncounterEnd:
+		return "Encounter ended"
+	}
+
+	return ""
+}
+
+// SummaryCollector Collector that can sum up data of a character in a line of text, empty character means everyone,
+// called while holding the read lock
+type SummaryCollector interface {
+	Summary(character string) string
+}
+
+// EncounterSource Collector that knows if a fight is going on right now
+type EncounterSource interface {
+	InEncounter() bool
+}
+
+// WebhookDelivery Outcome of posting a report to a single webhook
+type WebhookDelivery struct {
+	At time.Time
+	// Webhook Address with the secret part left out, so it can be shown
+	Webhook  string
+	Trigger  ReportTrigger
+	Attempts int
+	// Status HTTP status of the last attempt, zero if the request didn't get an answer
+	Status int
+	Error  string
+}
+
+func (d WebhookDelivery) Succeeded() bool {
+	return d.Error == ""
+}
@@ -6,7 +6,38 @@ type Settings struct {
 	SecondsUntilDPSReset    int
 	RemoveLevelsFromSkills  bool
 	EntitiesThatCountAsPets []string
-	ProjectGorgonFolder     string
+	// FoldPetDamageIntoOwner Damage dealt by pets and summons is counted as damage of the character that summoned them
+	FoldPetDamageIntoOwner bool
+	PartyMembers           []string
+	// AllyRules Names of entities that count as allies, "prefix:" and "regex:" can be used in front to match differently
+	AllyRules []string
+	// HealingSkills Skills that heal, written as "Skill Name=effect" where effect is target, self or group
+	HealingSkills         []string
+	SecondsToMatchHealing int
+	// SecondsToCountAsIdle Pauses between skill uses in combat that are longer than this are shown as gaps in rotation
+	SecondsToCountAsIdle int
+	// XPTableFile CSV with "level,xp" or "skill,level,xp" rows, or JSON, with XP needed to get to the next level
+	XPTableFile string
+	// LevelGoals Written as "Skill Name=target", or "Skill Name=current>target" when the skill doesn't level up in the log
+	LevelGoals          []string
+	XPRateWindowMinutes int
+	// Goals Written as "XP:Skill Name=10000", "Coins=50000", "Kills:Enemy Type=30" or "DTPS<200",
+	// "@Area Name" at the end only counts progress in that area
+	Goals               []string
+	ProjectGorgonFolder string
+	// APIEnabled Serves collector data as JSON, live updates over a WebSocket and an overlay page at /overlay
+	APIEnabled bool
+	// MetricsEnabled Serves OpenMetrics text for scrapers at /metrics, on the same address as the API
+	MetricsEnabled bool
+	APIBindAddress string
+	APIPort        int
+	// Webhooks Written as "URL", or "URL | template" where the template can use {{.Trigger}}, {{.Character}},
+	// {{.File}} and {{.Summary}}
+	Webhooks []string
+	// WebhookTabs Tabs whose exported images are attached to webhook reports
+	WebhookTabs           []string
+	WebhookOnSessionEnd   bool
+	WebhookOnEncounterEnd bool
 }
 
 func NewSettings() *Settings {
@@ -31,5 +62,19 @@ func NewSettings() *Settings {
 			"Sandstorm",
 			"Doomstorm",
 		},
+		HealingSkills: []string{
+			"Healing Mist=group",
+			"Regrowth=target",
+			"Restorative Arrow=target",
+			"Pep Talk=target",
+			"Healing Injection=target",
+			"First Aid=self",
+		},
+		SecondsToMatchHealing: 2,
+		SecondsToCountAsIdle:  3,
+		XPRateWindowMinutes:   15,
+		APIBindAddress:        "127.0.0.1",
+		APIPort:               8765,
+		WebhookTabs:           []string{"Damage Dealt", "Damage Taken"},
 	}
 }
@@ -7,7 +7,16 @@ type StatisticsCollector interface {
 	Mutex() *sync.RWMutex
 	Reset()
 	Collectors() []Collector
+	Characters() []string
+	Sessions() []CharacterSession
+	// SessionSummaries Summary of every login, the last one is only added once the file was read to the end
+	SessionSummaries() []SessionSummary
+	SelectedCharacter() string
+	SelectCharacter(name string)
+	Identity() FileIdentity
 	Notify() chan bool
+	// Metrics Metrics of every character, labelled with the character and the collector they came from
+	Metrics() []Metric
 	Run()
 	IsAlive() bool
 	Close()
@@ -15,6 +24,18 @@ type StatisticsCollector interface {
 
 type StatisticsInformation interface {
 	CurrentUsername() string
+	CurrentArea() string
+	// IsAlly Checks if the entity is on current character's side, skill can be empty if it's not known
+	IsAlly(name, skill string) bool
+	IsPet(name string) bool
+	// OwnerOf Returns the character that summoned the entity, empty if it's nobody's pet or the owner isn't known
+	OwnerOf(name string) string
+	// EnemyHealth Health pool that was learned from previous kills of the enemy type
+	EnemyHealth(enemyType string) (EnemyHealthEstimate, bool)
+	// XPTable Table from the user's file, nil if there isn't one
+	XPTable() *XPTable
+	// Alert Shows a banner, only does anything while a watched file is followed live
+	Alert(text string)
 	Settings() *Settings
 }
 
@@ -0,0 +1,184 @@
+package abstract
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type TextFormat uint8
+
+const (
+	TextPlain TextFormat = iota
+	TextMarkdown
+	TextChatLine
+)
+
+func (f TextFormat) String() string {
+	switch f {
+	case TextPlain:
+		return "Plain text table"
+	case TextMarkdown:
+		return "Markdown table"
+	case TextChatLine:
+		return "One line for chat"
+	}
+
+	return ""
+}
+
+// TextRows How many rows of a table are written out
+const TextRows = 10
+
+// ChatLineLimit Longest message the in-game chat takes
+const ChatLineLimit = 200
+
+// TextCollector Collector that can write what its tab shows as text, called while holding the read lock
+type TextCollector interface {
+	TextTable(state LayeredState) TextTable
+	// ChatLine Short summary like "DPS: 1.2K | Top: Skill (40%)"
+	ChatLine(state LayeredState) string
+}
+
+// TextTable Text version of an exported tab, the first column is aligned left and the rest right
+type TextTable struct {
+	Title string
+	// Parameters Lines written under the title, same as the parameters of image exports
+	Parameters []string
+	// TimeFrame Written as a header above the table, empty if the tab doesn't have one
+	TimeFrame string
+	Columns   []string
+	Rows      [][]string
+}
+
+// ExportText Writes the collector in the format, false if the collector can't be written as text
+func ExportText(state LayeredState, collector Collector, format TextFormat) (string, bool) {
+	source, ok := collector.(TextCollector)
+	if !ok {
+		return "", false
+	}
+
+	switch format {
+	case TextMarkdown:
+		return source.TextTable(state).Top(TextRows).Markdown(), true
+	case TextChatLine:
+		return TruncateChatLine(source.ChatLine(state)), true
+	}
+
+	return source.TextTable(state).Top(TextRows).Plain(), true
+}
+
+func TruncateChatLine(line string) string {
+	if utf8.RuneCountInString(line) <= ChatLineLimit {
+		return line
+	}
+
+	return string([]rune(line)[:ChatLineLimit-3]) + "..."
+}
+
+// Top Table with only the first rows, the rest is counted in a last row
+func (t TextTable) Top(rows int) TextTable {
+	if len(t.Rows) <= rows {
+		return t
+	}
+
+	hidden := len(t.Rows) - rows
+	t.Rows = append(t.Rows[:rows:rows], []string{fmt.Sprintf("and %d more", hidden)})
+	return t
+}
+
+func (t TextTable) header() []string {
+	lines := []string{t.Title}
+	lines = append(lines, t.Parameters...)
+	if t.TimeFrame != "" {
+		lines = append(lines, t.TimeFrame)
+	}
+
+	return lines
+}
+
+func (t TextTable) cell(row []string, column int) string {
+	if column < len(row) {
+		return row[column]
+	}
+
+	return ""
+}
+
+func pad(text string, width int, right bool) string {
+	padding := strings.Repeat(" ", max(0, width-utf8.RuneCountInString(text)))
+	if right {
+		return padding + text
+	}
+
+	return text + padding
+}
+
+func (t TextTable) Plain() string {
+	widths := make([]int, len(t.Columns))
+	for i, column := range t.Columns {
+		widths[i] = utf8.RuneCountInString(column)
+		for _, row := range t.Rows {
+			widths[i] = max(widths[i], utf8.RuneCountInString(t.cell(row, i)))
+		}
+	}
+
+	line := func(cells func(column int) string) string {
+		parts := make([]string, len(t.Columns))
+		for i := range t.Columns {
+			parts[i] = pad(cells(i), widths[i], i > 0)
+		}
+		return strings.TrimRight(strings.Join(parts, "  "), " ")
+	}
+
+	lines := t.header()
+	lines = append(lines, "")
+	lines = append(lines, line(func(column int) string {
+		return t.Columns[column]
+	}))
+	lines = append(lines, line(func(column int) string {
+		return strings.Repeat("-", widths[column])
+	}))
+	for _, row := range t.Rows {
+		lines = append(lines, line(func(column int) string {
+			return t.cell(row, column)
+		}))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+var markdownEscaper = strings.NewReplacer(`|`, `\|`, "\n", " ")
+
+func (t TextTable) Markdown() string {
+	row := func(cells func(column int) string) string {
+		parts := make([]string, len(t.Columns))
+		for i := range t.Columns {
+			parts[i] = markdownEscaper.Replace(cells(i))
+		}
+		return "| " + strings.Join(parts, " | ") + " |"
+	}
+
+	lines := []string{"**" + t.Title + "**"}
+	for _, line := range t.header()[1:] {
+		lines = append(lines, "", line)
+	}
+	lines = append(lines, "")
+
+	lines = append(lines, row(func(column int) string {
+		return t.Columns[column]
+	}))
+	lines = append(lines, row(func(column int) string {
+		if column == 0 {
+			return "---"
+		}
+		return "---:"
+	}))
+	for _, cells := range t.Rows {
+		lines = append(lines, row(func(column int) string {
+			return t.cell(cells, column)
+		}))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,114 @@
+package abstract
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func NewXPTable() *XPTable {
+	return &XPTable{
+		Default: make(map[int]int),
+		Skills:  make(map[string]map[int]int),
+	}
+}
+
+// XPTable XP needed to get from a level to the next one, skills without their own table use the default one
+type XPTable struct {
+	Default map[int]int
+	Skills  map[string]map[int]int
+}
+
+// ToNextLevel Works on nil table too, so collectors don't have to care if the user has a table or not
+func (t *XPTable) ToNextLevel(skill string, level int) (int, bool) {
+	if t == nil {
+		return 0, false
+	}
+
+	if table, ok := t.Skills[skill]; ok {
+		xp, ok := table[level]
+		return xp, ok
+	}
+
+	xp, ok := t.Default[level]
+	return xp, ok
+}
+
+// ToLevel XP needed to get from one level to another
+func (t *XPTable) ToLevel(skill string, from, to int) (int, bool) {
+	var total int
+	for level := from; level < to; level++ {
+		xp, ok := t.ToNextLevel(skill, level)
+		if !ok {
+			return 0, false
+		}
+		total += xp
+	}
+	return total, true
+}
+
+func (t *XPTable) set(skill string, level, xp int) {
+	if skill == "" {
+		t.Default[level] = xp
+		return
+	}
+
+	if t.Skills[skill] == nil {
+		t.Skills[skill] = make(map[int]int)
+	}
+	t.Skills[skill][level] = xp
+}
+
+// ParseXPTableCSV Rows are "level,xp" for the default table or "skill,level,xp" for a skill, header row is skipped
+func ParseXPTableCSV(data []byte) (*XPTable, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	table := NewXPTable()
+
+	for i, record := range records {
+		var skill, level, xp string
+		switch len(record) {
+		case 2:
+			level, xp = record[0], record[1]
+		case 3:
+			skill, level, xp = strings.TrimSpace(record[0]), record[1], record[2]
+		default:
+			return nil, fmt.Errorf("row %v has %v columns, expected 2 or 3", i+1, len(record))
+		}
+
+		levelValue, levelErr := strconv.Atoi(strings.TrimSpace(level))
+		xpValue, xpErr := strconv.Atoi(strings.TrimSpace(xp))
+		if levelErr != nil || xpErr != nil {
+			if i == 0 {
+				continue
+			}
+			return nil, fmt.Errorf("row %v doesn't have numbers for level and xp", i+1)
+		}
+
+		table.set(skill, levelValue, xpValue)
+	}
+
+	return table, nil
+}
+
+// ParseXPTableJSON Expects {"Default": {"level": xp}, "Skills": {"Skill Name": {"level": xp}}}
+func ParseXPTableJSON(data []byte) (*XPTable, error) {
+	table := NewXPTable()
+
+	err := json.Unmarshal(data, table)
+	if err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
@@ -0,0 +1,368 @@
+package collectors
+
+import (
+	"PGCombatTracker/abstract"
+	"PGCombatTracker/ui/components"
+	"PGCombatTracker/utils"
+	"PGCombatTracker/utils/drawing"
+	"cmp"
+	"fmt"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/widget"
+	"image"
+	"slices"
+	"time"
+)
+
+const shownFights = 10
+
+type activityShare float64
+
+func (a activityShare) StringCL(long bool) string {
+	if long {
+		return fmt.Sprintf("%.2f%% active", float64(a)*100)
+	}
+	return fmt.Sprintf("%.0f%% active", float64(a)*100)
+}
+
+type activityFight struct {
+	span   components.TimeFrame
+	damage abstract.Vitals
+}
+
+func (f activityFight) Length() time.Duration {
+	return f.span.To.Sub(f.span.From)
+}
+
+func (f activityFight) StringCL(long bool) string {
+	return fmt.Sprintf("%v in %v", f.damage.StringCL(long), ttkValue(f.Length()).StringCL(long))
+}
+
+func dps(damage int, length time.Duration) float64 {
+	if length <= 0 {
+		return 0
+	}
+	return float64(damage) / length.Seconds()
+}
+
+func NewActivityCollector() *ActivityCollector {
+	return &ActivityCollector{
+		timeController: components.NewTimeControllerOrCrash(components.NewTimeBasedChart("Damage")),
+		longFormatBool: &widget.Bool{},
+	}
+}
+
+// ActivityCollector Splits the session into fights, a fight ends once the character did nothing for as long as it takes DPS to reset
+type ActivityCollector struct {
+	session components.TimeFrame
+	fights  []activityFight
+	spans   []components.TimeFrame
+	damage  abstract.Vitals
+
+	timeController *components.TimeController
+	longFormatBool *widget.Bool
+}
+
+func (a *ActivityCollector) Reset(info abstract.StatisticsInformation) {
+	a.session = components.TimeFrame{}
+	a.fights = nil
+	a.spans = nil
+	a.damage = abstract.Vitals{}
+	a.timeController = components.NewTimeControllerOrCrash(components.NewTimeBasedChart("Damage"))
+}
+
+func (a *ActivityCollector) Tick(info abstract.StatisticsInformation, at time.Time) {
+
+}
+
+// markActive Extends the last fight, or starts a new one if the last one is already over
+func (a *ActivityCollector) markActive(info abstract.StatisticsInformation, at time.Time) {
+	combatWindow := time.Duration(info.Settings().SecondsUntilDPSReset) * time.Second
+
+	spans, started := markActive(a.spans, at, combatWindow)
+	if started {
+		a.fights = append(a.fights, activityFight{})
+	}
+
+	a.spans = spans
+	a.fights[len(a.fights)-1].span = spans[len(spans)-1]
+	a.timeController.Activity = a.spans
+}
+
+func (a *ActivityCollector) Collect(info abstract.StatisticsInformation, event *abstract.ChatEvent) error {
+	if a.session.From.IsZero() {
+		a.session = components.TimeFrame{From: event.Time, To: event.Time}
+	} else {
+		a.session = a.session.Expand(event.Time)
+	}
+
+	skillUse, ok := event.Contents.(*abstract.SkillUse)
+	if !ok {
+		return nil
+	}
+
+	username := info.CurrentUsername()
+	byCharacter := skillUse.Subject == username || info.OwnerOf(skillUse.Subject) == username
+	if !byCharacter && skillUse.Victim != username {
+		return nil
+	}
+
+	a.markActive(info, event.Time)
+
+	if byCharacter && skillUse.Damage != nil && !info.IsAlly(skillUse.Victim, "") {
+		a.damage = a.damage.Add(*skillUse.Damage)
+		fight := &a.fights[len(a.fights)-1]
+		fight.damage = fight.damage.Add(*skillUse.Damage)
+	}
+
+	a.timeController.Add(components.TimePoint{
+		Time:    event.Time,
+		Value:   a.damage.Total(),
+		Details: a.damage,
+	})
+
+	return nil
+}
+
+func (a *ActivityCollector) TabName() string {
+	return "Activity"
+}
+
+func (a *ActivityCollector) inCombat() time.Duration {
+	var total time.Duration
+	for _, fight := range a.fights {
+		total += fight.Length()
+	}
+	return total
+}
+
+func (a *ActivityCollector) share() activityShare {
+	session := a.session.To.Sub(a.session.From)
+	if session <= 0 {
+		return 0
+	}
+	return activityShare(float64(a.inCombat()) / float64(session))
+}
+
+// pauses Time between fights, not counting time before the first and after the last fight
+func (a *ActivityCollector) pauses() []time.Duration {
+	var pauses []time.Duration
+	for i := 1; i < len(a.fights); i++ {
+		pauses = append(pauses, a.fights[i].span.From.Sub(a.fights[i-1].span.To))
+	}
+	return pauses
+}
+
+func (a *ActivityCollector) longestIdle() time.Duration {
+	if len(a.fights) == 0 {
+		return a.session.To.Sub(a.session.From)
+	}
+
+	longest := max(
+		a.fights[0].span.From.Sub(a.session.From),
+		a.session.To.Sub(a.fights[len(a.fights)-1].span.To),
+	)
+	for _, pause := range a.pauses() {
+		longest = max(longest, pause)
+	}
+	return longest
+}
+
+func (a *ActivityCollector) longestFights() []activityFight {
+	fights := slices.Clone(a.fights)
+	slices.SortStableFunc(fights, func(a, b activityFight) int {
+		return cmp.Compare(b.Length(), a.Length())
+	})
+	return fights[:min(len(fights), shownFights)]
+}
+
+func addActivityLabels[T any](a *ActivityCollector, long bool, label func(format string, args ...any) T) []T {
+	session := a.session.To.Sub(a.session.From)
+	inCombat := a.inCombat()
+	duration := func(d time.Duration) string {
+		return ttkValue(d).StringCL(long)
+	}
+
+	labels := []T{
+		label("Session length: %v", duration(session)),
+		label("In combat: %v (%v)", duration(inCombat), a.share().StringCL(long)),
+		label("Out of combat: %v", duration(session-inCombat)),
+		label("Active DPS: %.1f", dps(a.damage.Total(), inCombat)),
+		label("DPS over the whole session: %.1f", dps(a.damage.Total(), session)),
+		label("Longest idle stretch: %v", duration(a.longestIdle())),
+		label("%d pulls", len(a.fights)),
+	}
+
+	if pauses := a.pauses(); len(pauses) > 0 {
+		var total time.Duration
+		for _, pause := range pauses {
+			total += pause
+		}
+
+		labels = append(labels, label(
+			"Time between pulls: avg %v (%v - %v)",
+			duration(total/time.Duration(len(pauses))),
+			duration(slices.Min(pauses)),
+			duration(slices.Max(pauses)),
+		))
+	}
+
+	return labels
+}
+
+func (a *ActivityCollector) UI(state abstract.LayeredState) (layout.Widget, []layout.Widget) {
+	long := a.longFormatBool.Value
+
+	topWidget := topBarSurface(func(gtx layout.Context) layout.Dimensions {
+		if a.longFormatBool.Update(gtx) {
+			gtx.Source.Execute(op.InvalidateCmd{})
+		}
+
+		return layout.Flex{
+			Axis: layout.Vertical,
+		}.Layout(
+			gtx,
+			layout.Rigid(defaultCheckboxStyle(state, a.longFormatBool, "Use long numbers").Layout),
+			utils.FlexSpacerH(utils.CommonSpacing),
+			layout.Rigid(components.StyleTimeController(state.Theme(), a.timeController).Layout),
+		)
+	})
+
+	label := func(format string, args ...any) layout.Widget {
+		text := fmt.Sprintf(format, args...)
+
+		return func(gtx layout.Context) layout.Dimensions {
+			return layout.UniformInset(utils.CommonSpacing).Layout(
+				gtx,
+				func(gtx layout.Context) layout.Dimensions {
+					style := defaultLabelStyle(state, text)
+					style.TextSize = 14
+					return style.Layout(gtx)
+				},
+			)
+		}
+	}
+
+	widgets := []layout.Widget{
+		drawUniversalStatsText(
+			state, a.share(),
+			components.TimelineLaneWidget(
+				components.StringToColor(a.TabName()), 40,
+				a.timeController.CurrentTimeFrame, a.spans...,
+			),
+			0,
+			"In combat", "",
+			40, long,
+		),
+	}
+	widgets = append(widgets, addActivityLabels(a, long, label)...)
+	widgets = append(widgets, label("Longest fights"))
+
+	fights := a.longestFights()
+	for _, fight := range fights {
+		widgets = append(widgets, drawUniversalBar(
+			state, fight,
+			int(fight.Length()), int(fights[0].Length()), 0,
+			fmt.Sprintf("Fight at %v", fight.span.From.Format(time.TimeOnly)), "",
+			25, long,
+		))
+	}
+
+	return topWidget, widgets
+}
+
+func (a *ActivityCollector) Export(state abstract.LayeredState) image.Image {
+	long := a.longFormatBool.Value
+	styledFonts := drawing.StyleFontPack(state.FontPack(), state.Theme().Fg)
+
+	label := func(format string, args ...any) drawing.FlexChild {
+		text := fmt.Sprintf(format, args...)
+
+		return drawing.Rigid(drawing.UniformInset(drawing.CommonSpacing).Layout(
+			styledFonts.Body.Layout(text),
+		))
+	}
+
+	items := []drawing.FlexChild{
+		drawing.Rigid(exportTimeFrame(styledFonts, a.timeController.CurrentTimeFrame)),
+		drawing.FlexVSpacer(drawing.CommonSpacing),
+		drawing.Rigid(exportUniversalStatsTextAsSurface(
+			styledFonts, a.share(),
+			timelineLane(components.StringToColor(a.TabName()), a.timeController.CurrentTimeFrame, a.spans...),
+			0,
+			"In combat", "",
+			long,
+		)),
+	}
+	items = append(items, addActivityLabels(a, long, label)...)
+	items = append(items, label("Longest fights"))
+
+	fights := a.longestFights()
+	for _, fight := range fights {
+		items = append(
+			items,
+			drawing.Rigid(exportUniversalBar(
+				styledFonts, fight,
+				int(fight.Length()), int(fights[0].Length()), 0,
+				fmt.Sprintf("Fight at %v", fight.span.From.Format(time.TimeOnly)), "",
+				long,
+			)),
+			drawing.FlexVSpacer(drawing.CommonSpacing),
+		)
+	}
+
+	body := drawing.Flex{
+		Axis:    layout.Vertical,
+		ExpandW: true,
+	}.Layout(
+		items...,
+	)
+
+	base := layoutTitle(
+		styledFonts,
+		a.TabName(),
+		styledFonts.Smaller.Layout("Combat activity of the current character"),
+		drawing.RoundedSurface(
+			utils.SecondBG,
+			body,
+		),
+	)
+
+	return drawing.ExportImage(state.Theme(), base, drawing.F64(800, 10000))
+}
+
+func (a *ActivityCollector) TextTable(state abstract.LayeredState) abstract.TextTable {
+	long := a.longFormatBool.Value
+
+	table := abstract.TextTable{
+		Title:      textTitle(a.TabName()),
+		Parameters: []string{"Combat activity of the current character"},
+		TimeFrame:  textTimeFrame(a.timeController.CurrentTimeFrame),
+		Columns:    []string{"Longest fights", "Length", "Damage", "DPS"},
+	}
+	table.Parameters = append(table.Parameters, addActivityLabels(a, long, func(format string, args ...any) string {
+		return fmt.Sprintf(format, args...)
+	})...)
+
+	for _, fight := range a.longestFights() {
+		table.Rows = append(table.Rows, []string{
+			fmt.Sprintf("Fight at %v", fight.span.From.Format(time.TimeOnly)),
+			ttkValue(fight.Length()).StringCL(long),
+			fight.damage.StringCL(long),
+			fmt.Sprintf("%.1f", dps(fight.damage.Total(), fight.Length())),
+		})
+	}
+
+	return table
+}
+
+func (a *ActivityCollector) ChatLine(state abstract.LayeredState) string {
+	inCombat := a.inCombat()
+
+	return chatLine(
+		fmt.Sprintf("In combat: %v", a.share().StringCL(false)),
+		fmt.Sprintf("Active DPS: %v", utils.FormatNumber(int(dps(a.damage.Total(), inCombat)))),
+		fmt.Sprintf("%d pulls", len(a.fights)),
+	)
+}
@@ -0,0 +1,240 @@
+package collectors
+
+import (
+	"PGCombatTracker/abstract"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+type allegianceRuleKind uint8
+
+const (
+	ruleExact allegianceRuleKind = iota
+	rulePrefix
+	ruleRegex
+)
+
+type allegianceRule struct {
+	kind    allegianceRuleKind
+	pattern string
+	regex   *regexp.Regexp
+}
+
+func normalizeEntityName(name string) string {
+	return strings.ToLower(strings.TrimSpace(SplitOffId(name)))
+}
+
+// parseAllegianceRule Rules are exact names by default, "prefix:" and "regex:" change how they're matched
+func parseAllegianceRule(rule string) (allegianceRule, bool) {
+	rule = strings.TrimSpace(rule)
+
+	switch {
+	case strings.HasPrefix(rule, "regex:"):
+		regex, err := regexp.Compile(strings.TrimSpace(strings.TrimPrefix(rule, "regex:")))
+		if err != nil {
+			log.Printf("Ignoring invalid ally rule '%v': %v\n", rule, err)
+			return allegianceRule{}, false
+		}
+
+		return allegianceRule{
+			kind:  ruleRegex,
+			regex: regex,
+		}, true
+	case strings.HasPrefix(rule, "prefix:"):
+		return allegianceRule{
+			kind:    rulePrefix,
+			pattern: strings.ToLower(strings.TrimSpace(strings.TrimPrefix(rule, "prefix:"))),
+		}, true
+	case strings.HasPrefix(rule, "exact:"):
+		rule = strings.TrimPrefix(rule, "exact:")
+	}
+
+	if rule == "" {
+		return allegianceRule{}, false
+	}
+
+	return allegianceRule{
+		kind:    ruleExact,
+		pattern: normalizeEntityName(rule),
+	}, true
+}
+
+func (r allegianceRule) matches(name string) bool {
+	switch r.kind {
+	case rulePrefix:
+		return strings.HasPrefix(normalizeEntityName(name), r.pattern)
+	case ruleRegex:
+		return r.regex.MatchString(name)
+	}
+
+	return normalizeEntityName(name) == r.pattern
+}
+
+// petCommandWindow How long after the character used a skill its pet can echo it as a "(Pet)" skill
+const petCommandWindow = 3 * time.Second
+
+type petCommand struct {
+	skill string
+	at    time.Time
+}
+
+// Allegiance Decides who is a friend and who is a foe, every collector should ask it instead of guessing on its own
+type Allegiance struct {
+	pets         abstract.PetsBearer
+	partyMembers []string
+	petRules     []allegianceRule
+	allyRules    []allegianceRule
+	// petTypes Pet types persisted per character, they tell pets from enemies but don't prove who owns them
+	petTypes map[string][]string
+	// owners Pet instances that were linked to their owner in this file
+	owners map[string]string
+	// seenPets Entities that used "(Pet)" skills, they're on somebody's side even if the owner is unknown
+	seenPets map[string]bool
+	// lastCommand Last skill the character used
+	lastCommand petCommand
+}
+
+func NewAllegiance(settings *abstract.Settings, pets abstract.PetsBearer) *Allegiance {
+	allegiance := &Allegiance{
+		pets:     pets,
+		petTypes: make(map[string][]string),
+		owners:   make(map[string]string),
+		seenPets: make(map[string]bool),
+	}
+
+	for _, member := range settings.PartyMembers {
+		if member = strings.TrimSpace(member); member != "" {
+			allegiance.partyMembers = append(allegiance.partyMembers, member)
+		}
+	}
+
+	for _, name := range settings.EntitiesThatCountAsPets {
+		if rule, ok := parseAllegianceRule(name); ok {
+			allegiance.petRules = append(allegiance.petRules, rule)
+		}
+	}
+
+	for _, name := range settings.AllyRules {
+		if rule, ok := parseAllegianceRule(name); ok {
+			allegiance.allyRules = append(allegiance.allyRules, rule)
+		}
+	}
+
+	return allegiance
+}
+
+func (a *Allegiance) petTypesOf(character string) []string {
+	types, ok := a.petTypes[character]
+	if !ok && a.pets != nil {
+		// Older versions saved pet instances, their ids are cut off so only types are left
+		for _, pet := range a.pets.KnownPets(character) {
+			if petType := SplitOffId(pet); !slices.Contains(types, petType) {
+				types = append(types, petType)
+			}
+		}
+		a.petTypes[character] = types
+	}
+	return types
+}
+
+// Learn Links pets to the character when they echo the skill the character just used as a "(Pet)" skill,
+// pets of other players use "(Pet)" skills too, so that alone doesn't say whose pet it is
+func (a *Allegiance) Learn(character string, event *abstract.ChatEvent) {
+	skillUse, ok := event.Contents.(*abstract.SkillUse)
+	if !ok || skillUse.Subject == "" {
+		return
+	}
+
+	if skillUse.Subject == character {
+		a.lastCommand = petCommand{skill: skillUse.Skill, at: event.Time}
+		return
+	}
+
+	if !strings.Contains(skillUse.Skill, "(Pet)") {
+		return
+	}
+
+	a.seenPets[skillUse.Subject] = true
+
+	if character == "" || a.owners[skillUse.Subject] != "" {
+		return
+	}
+
+	command := strings.TrimSpace(strings.Replace(skillUse.Skill, "(Pet)", "", 1))
+	if command != a.lastCommand.skill || event.Time.Sub(a.lastCommand.at) > petCommandWindow {
+		return
+	}
+
+	a.owners[skillUse.Subject] = character
+
+	petType := SplitOffId(skillUse.Subject)
+	types := a.petTypesOf(character)
+	if slices.Contains(types, petType) {
+		return
+	}
+
+	a.petTypes[character] = append(types, petType)
+
+	if a.pets != nil {
+		a.pets.LearnPet(character, petType)
+	}
+}
+
+// IsPet Checks if the entity is a configured pet, a linked pet or of a pet type the character had before,
+// wild creatures share types with pets, so a type only counts once the entity used a "(Pet)" skill
+func (a *Allegiance) IsPet(character, name string) bool {
+	if owner, ok := a.owners[name]; ok && owner == character {
+		return true
+	}
+
+	if character != "" && a.seenPets[name] && slices.Contains(a.petTypesOf(character), SplitOffId(name)) {
+		return true
+	}
+
+	for _, rule := range a.petRules {
+		if rule.matches(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// OwnerOf Returns the character the pet was linked to, empty if the owner isn't known
+func (a *Allegiance) OwnerOf(name string) string {
+	return a.owners[name]
+}
+
+// IsAlly Checks if the entity is on character's side, skill can be empty if it's not known
+func (a *Allegiance) IsAlly(character, name, skill string) bool {
+	if name == "" {
+		return false
+	}
+
+	if name == character {
+		return true
+	}
+
+	if strings.Contains(skill, "(Pet)") {
+		return true
+	}
+
+	if slices.Contains(a.partyMembers, name) {
+		return true
+	}
+
+	if a.seenPets[name] || a.IsPet(character, name) {
+		return true
+	}
+
+	for _, rule := range a.allyRules {
+		if rule.matches(name) {
+			return true
+		}
+	}
+
+	return false
+}
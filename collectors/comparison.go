@@ -0,0 +1,27 @@
+package collectors
+
+import "PGCombatTracker/ui/components"
+
+// splitFights Splits DPS chart points into fights, a fight ends when the DPS drops back to zero
+func splitFights(points []components.TimePoint) [][]components.TimePoint {
+	var fights [][]components.TimePoint
+	var current []components.TimePoint
+
+	for _, point := range points {
+		if point.Value > 0 {
+			current = append(current, point)
+			continue
+		}
+
+		if len(current) > 0 {
+			fights = append(fights, current)
+			current = nil
+		}
+	}
+
+	if len(current) > 0 {
+		fights = append(fights, current)
+	}
+
+	return fights
+}
@@ -14,7 +14,7 @@ import (
 	"image"
 	"log"
 	"slices"
-	"strings"
+	"strconv"
 	"time"
 )
 
@@ -23,6 +23,8 @@ type skillDamage struct {
 	amount        int
 	damage        abstract.Vitals
 	lastUsed      time.Time
+	highestHit    int
+	highestHitAt  time.Time
 	totalChart    *components.TimeBasedChart
 	dpsChart      *components.TimeBasedChart
 	dpsCalculator *DPSCalculator
@@ -41,10 +43,54 @@ type subjectiveDamageDealt struct {
 	stackedDpsChart *components.StackedTimeBasedChart
 	dpsCalculator   *DPSCalculator
 
+	// Total damage split by vital type
+	vitalCharts *vitalCharts
+	// burst Damage over the last burst window, for the burst DPS record
+	burst slidingWindow
+
 	totalDamage    abstract.Vitals
 	maxDamage      abstract.Vitals
 	indirectDamage abstract.Vitals
 	skillDamage    []skillDamage
+	// active Spans of fighting, shown in a band under the overview chart
+	active []components.TimeFrame
+	// zones Damage grouped by the zone it was dealt in, kept sorted by damage
+	zones []skillDamage
+}
+
+func (s *subjectiveDamageDealt) markActive(at time.Time, settings *abstract.Settings) {
+	s.active, _ = markActive(s.active, at, time.Duration(settings.SecondsUntilDPSReset)*time.Second)
+	s.totalChart.Activity = s.active
+	s.dpsChart.Activity = s.active
+}
+
+// addZoneDamage Adds damage to the zone it was dealt in, zones are kept sorted by damage
+func addZoneDamage(zones []skillDamage, zone string, damage abstract.Vitals, at time.Time) []skillDamage {
+	update := func(skill skillDamage) skillDamage {
+		if skill.lastUsed != at {
+			skill.amount++
+		}
+		skill.damage = skill.damage.Add(damage)
+		skill.lastUsed = at
+		return skill
+	}
+
+	zones = utils.CreateUpdate(
+		zones,
+		func(skill skillDamage) bool {
+			return skill.name == zone
+		},
+		func() skillDamage {
+			return update(skillDamage{name: zone})
+		},
+		update,
+	)
+
+	slices.SortFunc(zones, func(a, b skillDamage) int {
+		return cmp.Compare(b.damage.Total(), a.damage.Total())
+	})
+
+	return zones
 }
 
 func NewDamageDealtCollector(settings *abstract.Settings) *DamageDealtCollector {
@@ -58,6 +104,7 @@ func NewDamageDealtCollector(settings *abstract.Settings) *DamageDealtCollector
 		DisplayBars,
 		DisplayPie,
 		DisplayGraphs,
+		DisplayVitals,
 	)
 	if err != nil {
 		log.Fatalln(err)
@@ -75,6 +122,7 @@ func NewDamageDealtCollector(settings *abstract.Settings) *DamageDealtCollector
 		subjectDropdown: subjectDropdown,
 		displayDropdown: displayDropdown,
 		chartDropdown:   chartDropdown,
+		groupDropdown:   newGroupDropdown(GroupNone, GroupSkill, GroupCategory, GroupZone),
 		longFormatBool:  &widget.Bool{},
 		total: subjectiveDamageDealt{
 			totalChart:        components.NewTimeControllerOrCrash(components.NewTimeBasedChart("Total")),
@@ -82,6 +130,7 @@ func NewDamageDealtCollector(settings *abstract.Settings) *DamageDealtCollector
 			dpsChart:          dpsTimeController,
 			stackedDpsChart:   components.NewStackedTimeBasedChart(),
 			dpsCalculator:     NewDPSCalculatorForController(dpsTimeController, settings),
+			vitalCharts:       newVitalCharts(),
 		},
 	}
 }
@@ -93,21 +142,29 @@ type DamageDealtCollector struct {
 
 	currentDisplay   displayChoice
 	currentChartView damageChartChoice
+	currentGroup     groupChoice
 
 	subjectDropdown *components.Dropdown
 	displayDropdown *components.Dropdown
 	chartDropdown   *components.Dropdown
+	groupDropdown   *components.Dropdown
 	longFormatBool  *widget.Bool
 }
 
 func (d *DamageDealtCollector) ingestSkillDamage(info abstract.StatisticsInformation, event *abstract.ChatEvent) {
 	skillUse := event.Contents.(*abstract.SkillUse)
 	skillName := skillUse.Skill
+	subjectName := skillUse.Subject
 
 	if info.Settings().RemoveLevelsFromSkills {
 		skillName = SplitOffId(skillName)
 	}
 
+	if owner := info.OwnerOf(skillUse.Subject); owner != "" && info.Settings().FoldPetDamageIntoOwner {
+		skillName = fmt.Sprintf("%v by %v", skillName, SplitOffId(skillUse.Subject))
+		subjectName = owner
+	}
+
 	// Functions for dealing with skillDamage
 	findSkillDamage := func(skill skillDamage) bool {
 		return skill.name == skillName
@@ -132,6 +189,8 @@ func (d *DamageDealtCollector) ingestSkillDamage(info abstract.StatisticsInforma
 				amount:        1,
 				damage:        *skillUse.Damage,
 				lastUsed:      event.Time,
+				highestHit:    skillUse.Damage.Total(),
+				highestHitAt:  event.Time,
 				totalChart:    totalChart,
 				dpsChart:      dpsChart,
 				dpsCalculator: dpsCalculator,
@@ -144,6 +203,10 @@ func (d *DamageDealtCollector) ingestSkillDamage(info abstract.StatisticsInforma
 		}
 		skill.damage = skill.damage.Add(*skillUse.Damage)
 		skill.lastUsed = event.Time
+		if skillUse.Damage.Total() > skill.highestHit {
+			skill.highestHit = skillUse.Damage.Total()
+			skill.highestHitAt = event.Time
+		}
 		skill.totalChart.Add(components.TimePoint{
 			Time:    event.Time,
 			Value:   skill.damage.Total(),
@@ -167,6 +230,10 @@ func (d *DamageDealtCollector) ingestSkillDamage(info abstract.StatisticsInforma
 			Value: subject.totalDamage.Total(),
 		})
 		subject.dpsCalculator.Add(event.Time, skillUse.Damage.Total())
+		subject.burst.add(abstract.BurstWindow, skillUse.Damage.Total(), event.Time)
+		subject.vitalCharts.Add(event.Time, subject.totalDamage)
+		subject.markActive(event.Time, info.Settings())
+		subject.zones = addZoneDamage(subject.zones, zoneOf(info), *skillUse.Damage, event.Time)
 		subject.skillDamage = utils.CreateUpdate(
 			subject.skillDamage,
 			findSkillDamage,
@@ -186,7 +253,7 @@ func (d *DamageDealtCollector) ingestSkillDamage(info abstract.StatisticsInforma
 	d.subjects = utils.CreateUpdate(
 		d.subjects,
 		func(subject subjectiveDamageDealt) bool {
-			return subject.subject == skillUse.Subject
+			return subject.subject == subjectName
 		},
 		func() subjectiveDamageDealt {
 			totalChart, err := components.NewTimeController(components.NewTimeBasedChart("Total"))
@@ -206,7 +273,7 @@ func (d *DamageDealtCollector) ingestSkillDamage(info abstract.StatisticsInforma
 			dpsCalculator := NewDPSCalculatorForController(dpsChart, info.Settings())
 
 			subject := subjectiveDamageDealt{
-				subject:     skillUse.Subject,
+				subject:     subjectName,
 				totalDamage: *skillUse.Damage,
 				maxDamage:   *skillUse.Damage,
 				totalMaxRange: components.DataRange{
@@ -217,7 +284,12 @@ func (d *DamageDealtCollector) ingestSkillDamage(info abstract.StatisticsInforma
 				dpsChart:          dpsChart,
 				stackedDpsChart:   components.NewStackedTimeBasedChart(),
 				dpsCalculator:     dpsCalculator,
+				vitalCharts:       newVitalCharts(),
 			}
+			subject.vitalCharts.Add(event.Time, subject.totalDamage)
+			subject.burst.add(abstract.BurstWindow, skillUse.Damage.Total(), event.Time)
+			subject.markActive(event.Time, info.Settings())
+			subject.zones = addZoneDamage(nil, zoneOf(info), *skillUse.Damage, event.Time)
 
 			subject.skillDamage = []skillDamage{
 				createSkillDamage(&subject)(),
@@ -236,10 +308,10 @@ func (d *DamageDealtCollector) ingestSkillDamage(info abstract.StatisticsInforma
 		d.subjectDropdown.Options(),
 		func(item fmt.Stringer) bool {
 			casted := item.(subjectChoice)
-			return string(casted) == skillUse.Subject
+			return string(casted) == subjectName
 		},
 		func() fmt.Stringer {
-			return subjectChoice(skillUse.Subject)
+			return subjectChoice(subjectName)
 		},
 		func(stringer fmt.Stringer) fmt.Stringer {
 			return stringer
@@ -278,6 +350,7 @@ func (d *DamageDealtCollector) ingestIndirect(info abstract.StatisticsInformatio
 				dpsChart:          dpsChart,
 				stackedDpsChart:   components.NewStackedTimeBasedChart(),
 				dpsCalculator:     dpsCalculator,
+				vitalCharts:       newVitalCharts(),
 			}
 
 			return subject
@@ -300,31 +373,12 @@ func (d *DamageDealtCollector) Reset(info abstract.StatisticsInformation) {
 		dpsChart:          dpsTimeController,
 		stackedDpsChart:   components.NewStackedTimeBasedChart(),
 		dpsCalculator:     NewDPSCalculatorForController(dpsTimeController, info.Settings()),
+		vitalCharts:       newVitalCharts(),
 	}
 	d.subjectDropdown.SetOptions([]fmt.Stringer{subjectChoice("")})
 	d.currentSubject = ""
 }
 
-func isSubjectValuable(info abstract.StatisticsInformation, subject, skill string) bool {
-	if subject == info.CurrentUsername() {
-		return true
-	}
-
-	if strings.Contains(skill, "(Pet)") {
-		return true
-	}
-
-	lowTrimmedSubject := strings.ToLower(strings.TrimSpace(SplitOffId(subject)))
-
-	for _, expectedName := range info.Settings().EntitiesThatCountAsPets {
-		if lowTrimmedSubject == strings.ToLower(strings.TrimSpace(expectedName)) {
-			return true
-		}
-	}
-
-	return false
-}
-
 func (d *DamageDealtCollector) Tick(info abstract.StatisticsInformation, at time.Time) {
 	d.total.dpsCalculator.Tick(at)
 	for _, skill := range d.total.skillDamage {
@@ -340,11 +394,11 @@ func (d *DamageDealtCollector) Tick(info abstract.StatisticsInformation, at time
 }
 
 func (d *DamageDealtCollector) Collect(info abstract.StatisticsInformation, event *abstract.ChatEvent) error {
-	if skillUse, ok := event.Contents.(*abstract.SkillUse); ok && skillUse.Damage != nil && isSubjectValuable(info, skillUse.Subject, skillUse.Skill) {
+	if skillUse, ok := event.Contents.(*abstract.SkillUse); ok && skillUse.Damage != nil && info.IsAlly(skillUse.Subject, skillUse.Skill) {
 		d.ingestSkillDamage(info, event)
 	}
 
-	if indirect, ok := event.Contents.(*abstract.IndirectDamage); ok && !isSubjectValuable(info, indirect.Subject, "") {
+	if indirect, ok := event.Contents.(*abstract.IndirectDamage); ok && !info.IsAlly(indirect.Subject, "") {
 		d.ingestIndirect(info, event)
 	}
 
@@ -355,6 +409,250 @@ func (d *DamageDealtCollector) TabName() string {
 	return "Damage Dealt"
 }
 
+func (d *DamageDealtCollector) ComparisonRows() []abstract.ComparisonRow {
+	subject := d.total
+	for _, possibleSubject := range d.subjects {
+		if possibleSubject.subject == d.currentSubject {
+			subject = possibleSubject
+			break
+		}
+	}
+
+	active := activeTime(subject.active)
+
+	rows := []abstract.ComparisonRow{
+		{Metric: "Damage", Name: "Total", Value: float64(subject.totalDamage.Total())},
+		{Metric: "DPS", Name: "Total", Value: dps(subject.totalDamage.Total(), active)},
+	}
+
+	for _, skill := range subject.skillDamage {
+		rows = append(
+			rows,
+			abstract.ComparisonRow{Metric: "Damage", Name: skill.name, Value: float64(skill.damage.Total())},
+			abstract.ComparisonRow{Metric: "DPS", Name: skill.name, Value: dps(skill.damage.Total(), active)},
+			abstract.ComparisonRow{Metric: "Uses", Name: skill.name, Value: float64(skill.amount)},
+		)
+	}
+
+	return rows
+}
+
+// Records Highest hit of every skill and best burst DPS of the character
+func (d *DamageDealtCollector) Records(character string) []abstract.PersonalRecord {
+	var records []abstract.PersonalRecord
+
+	for _, subject := range d.subjects {
+		if subject.subject != character {
+			continue
+		}
+
+		for _, skill := range subject.skillDamage {
+			records = append(records, abstract.PersonalRecord{
+				Kind:  abstract.RecordHighestHit,
+				Name:  skill.name,
+				Value: float64(skill.highestHit),
+				At:    skill.highestHitAt,
+			})
+		}
+
+		if subject.burst.best > 0 {
+			records = append(records, abstract.PersonalRecord{
+				Kind:  abstract.RecordBurstDPS,
+				Value: float64(subject.burst.best) / abstract.BurstWindow.Seconds(),
+				At:    subject.burst.bestAt,
+			})
+		}
+	}
+
+	return records
+}
+
+func (d *DamageDealtCollector) Metrics(character string) []abstract.Metric {
+	var metrics []abstract.Metric
+
+	for _, subject := range d.subjects {
+		if subject.subject != character {
+			continue
+		}
+
+		for _, skill := range subject.skillDamage {
+			metrics = append(metrics, abstract.Metric{
+				Name:   "pgct_damage_dealt",
+				Help:   "Damage dealt to health, armor and power",
+				Type:   abstract.MetricCounter,
+				Labels: []abstract.MetricLabel{{Name: "skill", Value: skill.name}},
+				Value:  float64(skill.damage.Total()),
+			})
+		}
+
+		points := subject.dpsChart.BaseChart.DataPoints
+		if len(points) > 0 {
+			metrics = append(metrics, abstract.Metric{
+				Name:  "pgct_dps",
+				Help:  "Current damage per second, drops to zero when the fight is over",
+				Type:  abstract.MetricGauge,
+				Value: float64(points[len(points)-1].Value),
+			})
+		}
+	}
+
+	return metrics
+}
+
+// dealtWithPets Damage of the character and their pets, active time is of whoever of them fought the longest
+func (d *DamageDealtCollector) dealtWithPets(info abstract.StatisticsInformation, character string) (int, time.Duration) {
+	var damage int
+	var active time.Duration
+
+	for _, subject := range d.subjects {
+		if subject.subject != character && info.OwnerOf(subject.subject) != character {
+			continue
+		}
+
+		damage += subject.totalDamage.Total()
+		active = max(active, activeTime(subject.active))
+	}
+
+	return damage, active
+}
+
+func (d *DamageDealtCollector) Summary(character string) string {
+	subject := d.total
+	if character != "" {
+		index := slices.IndexFunc(d.subjects, func(subject subjectiveDamageDealt) bool {
+			return subject.subject == character
+		})
+		if index < 0 {
+			return ""
+		}
+		subject = d.subjects[index]
+	}
+
+	if subject.totalDamage.Total() == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(
+		"Damage dealt: %v, %v DPS while fighting",
+		utils.FormatNumber(subject.totalDamage.Total()),
+		utils.FormatNumber(int(dps(subject.totalDamage.Total(), activeTime(subject.active)))),
+	)
+}
+
+// InEncounter DPS of everyone only drops to zero once nobody did damage for a while
+func (d *DamageDealtCollector) InEncounter() bool {
+	points := d.total.dpsChart.BaseChart.DataPoints
+	return len(points) > 0 && points[len(points)-1].Value > 0
+}
+
+type SkillDamageSnapshot struct {
+	Name       string
+	Damage     abstract.Vitals
+	Uses       int
+	HighestHit int
+}
+
+type DamageDealtSnapshot struct {
+	Name string
+	// CurrentDPS Latest value of the DPS chart, drops to zero when the fight is over
+	CurrentDPS int
+	ActiveDPS  float64
+	Damage     abstract.Vitals
+	Indirect   abstract.Vitals
+	Skills     []SkillDamageSnapshot
+}
+
+func snapshotDamageDealt(subject subjectiveDamageDealt) DamageDealtSnapshot {
+	points := subject.dpsChart.BaseChart.DataPoints
+
+	snapshot := DamageDealtSnapshot{
+		Name:      subject.subject,
+		ActiveDPS: dps(subject.totalDamage.Total(), activeTime(subject.active)),
+		Damage:    subject.totalDamage,
+		Indirect:  subject.indirectDamage,
+		Skills:    make([]SkillDamageSnapshot, len(subject.skillDamage)),
+	}
+
+	if len(points) > 0 {
+		snapshot.CurrentDPS = points[len(points)-1].Value
+	}
+
+	for i, skill := range subject.skillDamage {
+		snapshot.Skills[i] = SkillDamageSnapshot{
+			Name:       skill.name,
+			Damage:     skill.damage,
+			Uses:       skill.amount,
+			HighestHit: skill.highestHit,
+		}
+	}
+
+	return snapshot
+}
+
+// Snapshot Total of everyone and every subject separately
+func (d *DamageDealtCollector) Snapshot() any {
+	subjects := make([]DamageDealtSnapshot, len(d.subjects))
+	for i, subject := range d.subjects {
+		subjects[i] = snapshotDamageDealt(subject)
+	}
+
+	return struct {
+		Total    DamageDealtSnapshot
+		Subjects []DamageDealtSnapshot
+	}{
+		Total:    snapshotDamageDealt(d.total),
+		Subjects: subjects,
+	}
+}
+
+// FightDPS DPS of the selected subject split into fights
+func (d *DamageDealtCollector) FightDPS() [][]components.TimePoint {
+	subject := d.total
+	for _, possibleSubject := range d.subjects {
+		if possibleSubject.subject == d.currentSubject {
+			subject = possibleSubject
+			break
+		}
+	}
+
+	return splitFights(subject.dpsChart.BaseChart.DataPoints)
+}
+
+// grouped Skills merged by the current grouping, used by bars and pie only
+func (d *DamageDealtCollector) grouped(state abstract.GlobalState, subject subjectiveDamageDealt) ([]skillDamage, int) {
+	if d.currentGroup == GroupZone {
+		if len(subject.zones) == 0 {
+			return subject.zones, 0
+		}
+
+		return subject.zones, subject.zones[0].damage.Total()
+	}
+
+	skills := groupItems(
+		state.SkillCategories(),
+		d.currentGroup,
+		subject.skillDamage,
+		func(skill skillDamage) string {
+			return skill.name
+		},
+		func(group string, grouped skillDamage, skill skillDamage) skillDamage {
+			grouped.name = group
+			grouped.amount += skill.amount
+			grouped.damage = grouped.damage.Add(skill.damage)
+			return grouped
+		},
+		func(a, b skillDamage) int {
+			return cmp.Compare(b.damage.Total(), a.damage.Total())
+		},
+	)
+
+	if len(skills) == 0 {
+		return skills, subject.maxDamage.Total()
+	}
+
+	return skills, skills[0].damage.Total()
+}
+
 func (d *DamageDealtCollector) drawWidget(state abstract.LayeredState, skill skillDamage, widget layout.Widget, size unit.Dp) layout.Widget {
 	return drawUniversalStatsText(
 		state, skill.damage,
@@ -388,6 +686,10 @@ func (d *DamageDealtCollector) UI(state abstract.LayeredState) (layout.Widget, [
 		d.currentChartView = d.chartDropdown.Value.(damageChartChoice)
 	}
 
+	if d.groupDropdown.Changed() {
+		d.currentGroup = d.groupDropdown.Value.(groupChoice)
+	}
+
 	subject := d.total
 	for _, possibleSubject := range d.subjects {
 		if possibleSubject.subject == d.currentSubject {
@@ -420,11 +722,25 @@ func (d *DamageDealtCollector) UI(state abstract.LayeredState) (layout.Widget, [
 							return defaultDropdownStyle(state, d.chartDropdown).Layout(gtx)
 						}
 
+						if d.currentDisplay == DisplayBars || d.currentDisplay == DisplayPie {
+							return defaultDropdownStyle(state, d.groupDropdown).Layout(gtx)
+						}
+
 						return layout.Dimensions{}
 					},
 				)
 			}),
 			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if d.currentDisplay == DisplayVitals {
+					return layout.Flex{
+						Axis: layout.Vertical,
+					}.Layout(
+						gtx,
+						utils.FlexSpacerH(utils.CommonSpacing),
+						layout.Rigid(components.StyleTimeController(state.Theme(), subject.totalChart).Layout),
+					)
+				}
+
 				if d.currentDisplay == DisplayGraphs {
 					return layout.Flex{
 						Axis: layout.Vertical,
@@ -455,7 +771,7 @@ func (d *DamageDealtCollector) UI(state abstract.LayeredState) (layout.Widget, [
 
 	switch d.currentDisplay {
 	case DisplayBars:
-		var maxDamage = subject.maxDamage.Total()
+		skills, maxDamage := d.grouped(state, subject)
 
 		widgets = append(widgets, d.drawBar(
 			state,
@@ -468,7 +784,7 @@ func (d *DamageDealtCollector) UI(state abstract.LayeredState) (layout.Widget, [
 			25,
 		))
 
-		for _, skill := range subject.skillDamage {
+		for _, skill := range skills {
 			widgets = append(widgets, d.drawBar(state, skill, maxDamage, 40))
 		}
 
@@ -483,11 +799,12 @@ func (d *DamageDealtCollector) UI(state abstract.LayeredState) (layout.Widget, [
 			25,
 		))
 	case DisplayPie:
+		skills, _ := d.grouped(state, subject)
 		widgets = append(widgets, func(gtx layout.Context) layout.Dimensions {
 			totalValue := subject.totalDamage.Total()
 
-			pieItems := make([]components.PieChartItem, len(subject.skillDamage))
-			for i, skill := range subject.skillDamage {
+			pieItems := make([]components.PieChartItem, len(skills))
+			for i, skill := range skills {
 				pieItems[i] = components.PieChartItem{
 					Name:    skill.name,
 					Value:   skill.damage.Total(),
@@ -552,6 +869,21 @@ func (d *DamageDealtCollector) UI(state abstract.LayeredState) (layout.Widget, [
 
 			widgets = append(widgets, d.drawWidget(state, skill, chartStyle.Layout, 100))
 		}
+	case DisplayVitals:
+		widgets = append(
+			widgets,
+			drawVitalsSplit(state, subject.vitalCharts, subject.totalChart, subject.totalDamage, d.longFormatBool.Value)...,
+		)
+
+		maxDamage := subject.maxDamage.Total()
+		for _, skill := range subject.skillDamage {
+			widgets = append(widgets, drawVitalsBar(
+				state, skill.damage,
+				maxDamage, skill.amount,
+				skill.name, "used %v times",
+				40, d.longFormatBool.Value,
+			))
+		}
 	}
 
 	return topWidget, widgets
@@ -590,9 +922,8 @@ func (d *DamageDealtCollector) Export(state abstract.LayeredState) image.Image {
 
 	switch d.currentDisplay {
 	case DisplayBars:
-		items := make([]drawing.FlexChild, 0, len(subject.skillDamage)*2-1+4)
-
-		maxDamage := subject.maxDamage.Total()
+		skills, maxDamage := d.grouped(state, subject)
+		items := make([]drawing.FlexChild, 0, len(skills)*2-1+4)
 
 		items = append(
 			items,
@@ -608,7 +939,7 @@ func (d *DamageDealtCollector) Export(state abstract.LayeredState) image.Image {
 			drawing.FlexVSpacer(drawing.CommonSpacing),
 		)
 
-		for i, skill := range subject.skillDamage {
+		for i, skill := range skills {
 			if i != 0 {
 				items = append(items, drawing.FlexVSpacer(drawing.CommonSpacing))
 			}
@@ -637,10 +968,11 @@ func (d *DamageDealtCollector) Export(state abstract.LayeredState) image.Image {
 			items...,
 		)
 	case DisplayPie:
+		skills, _ := d.grouped(state, subject)
 		totalValue := subject.totalDamage.Total()
 
-		pieItems := make([]drawing.PieChartItem, len(subject.skillDamage))
-		for i, skill := range subject.skillDamage {
+		pieItems := make([]drawing.PieChartItem, len(skills))
+		for i, skill := range skills {
 			pieItems[i] = drawing.PieChartItem{
 				Name:    skill.name,
 				Value:   skill.damage.Total(),
@@ -708,6 +1040,29 @@ func (d *DamageDealtCollector) Export(state abstract.LayeredState) image.Image {
 			items = append(items, drawing.Rigid(d.exportWidget(styledFonts, skill, style.Layout())))
 		}
 
+		body = drawing.Flex{
+			ExpandW: true,
+			Axis:    layout.Vertical,
+		}.Layout(
+			items...,
+		)
+	case DisplayVitals:
+		items := exportVitalsSplit(styledFonts, subject.vitalCharts, subject.totalChart, subject.totalDamage, d.longFormatBool.Value)
+
+		maxDamage := subject.maxDamage.Total()
+		for _, skill := range subject.skillDamage {
+			items = append(
+				items,
+				drawing.FlexVSpacer(drawing.CommonSpacing),
+				drawing.Rigid(exportVitalsBar(
+					styledFonts, skill.damage,
+					maxDamage, skill.amount,
+					skill.name, "used %v times",
+					d.longFormatBool.Value,
+				)),
+			)
+		}
+
 		body = drawing.Flex{
 			ExpandW: true,
 			Axis:    layout.Vertical,
@@ -737,6 +1092,10 @@ func (d *DamageDealtCollector) Export(state abstract.LayeredState) image.Image {
 					return styledFonts.Smaller.Layout(fmt.Sprintf("%v Chart", d.currentChartView))(ltx)
 				}
 
+				if d.currentDisplay == DisplayBars || d.currentDisplay == DisplayPie {
+					return styledFonts.Smaller.Layout(fmt.Sprintf("Grouped by: %v", d.currentGroup))(ltx)
+				}
+
 				return drawing.Empty(ltx)
 			},
 		),
@@ -748,3 +1107,62 @@ func (d *DamageDealtCollector) Export(state abstract.LayeredState) image.Image {
 
 	return drawing.ExportImage(state.Theme(), base, drawing.F64(800, 10000))
 }
+
+func (d *DamageDealtCollector) TextTable(state abstract.LayeredState) abstract.TextTable {
+	subject := d.total
+	for _, possibleSubject := range d.subjects {
+		if possibleSubject.subject == d.currentSubject {
+			subject = possibleSubject
+			break
+		}
+	}
+
+	long := d.longFormatBool.Value
+	skills, _ := d.grouped(state, subject)
+
+	table := abstract.TextTable{
+		Title: textTitle(d.TabName()),
+		Parameters: []string{
+			fmt.Sprintf("Subject: %v", subjectChoice(d.currentSubject)),
+			fmt.Sprintf("Grouped by: %v", d.currentGroup),
+			fmt.Sprintf("Total damage: %v", subject.totalDamage.StringCL(long)),
+			fmt.Sprintf("Indirect damage: %v", subject.indirectDamage.StringCL(long)),
+		},
+		TimeFrame: textTimeFrame(subject.totalChart.CurrentTimeFrame),
+		Columns:   []string{"Skill", "Uses", "Damage", "Share"},
+	}
+
+	for _, skill := range skills {
+		table.Rows = append(table.Rows, []string{
+			skill.name,
+			strconv.Itoa(skill.amount),
+			skill.damage.StringCL(long),
+			textShare(skill.damage.Total(), subject.totalDamage.Total()),
+		})
+	}
+
+	return table
+}
+
+func (d *DamageDealtCollector) ChatLine(state abstract.LayeredState) string {
+	subject := d.total
+	for _, possibleSubject := range d.subjects {
+		if possibleSubject.subject == d.currentSubject {
+			subject = possibleSubject
+			break
+		}
+	}
+
+	total := subject.totalDamage.Total()
+	line := fmt.Sprintf(
+		"DPS: %v",
+		utils.FormatNumber(int(dps(total, activeTime(subject.active)))),
+	)
+
+	top := ""
+	if skills, _ := d.grouped(state, subject); len(skills) > 0 {
+		top = chatTop(skills[0].name, skills[0].damage.Total(), total)
+	}
+
+	return chatLine(line, top)
+}
@@ -14,7 +14,7 @@ import (
 	"image"
 	"log"
 	"slices"
-	"strings"
+	"strconv"
 	"time"
 )
 
@@ -25,6 +25,77 @@ type enemyDamage struct {
 	chart  *components.TimeBasedChart
 }
 
+// abilityDamage Damage that a single ability of an enemy did
+type abilityDamage struct {
+	enemy  string
+	name   string
+	hits   int
+	crits  int
+	evades int
+	damage abstract.Vitals
+	maxHit abstract.Vitals
+}
+
+func (a abilityDamage) caption(totalTaken int, long bool) string {
+	share := 0.0
+	if totalTaken > 0 {
+		share = float64(a.damage.Total()) / float64(totalTaken) * 100
+	}
+
+	return fmt.Sprintf(
+		"%d crits, %d evaded, max hit %v, %.1f%% of damage taken",
+		a.crits, a.evades, a.maxHit.StringCL(long), share,
+	)
+}
+
+func updateAbilityDamage(abilities []abilityDamage, enemy, name string, skillUse *abstract.SkillUse, damage abstract.Vitals) []abilityDamage {
+	update := func(ability abilityDamage) abilityDamage {
+		if skillUse.Evaded {
+			ability.evades++
+		} else {
+			ability.hits++
+		}
+		if skillUse.Crit {
+			ability.crits++
+		}
+		ability.damage = ability.damage.Add(damage)
+		if damage.Total() > ability.maxHit.Total() {
+			ability.maxHit = damage
+		}
+		return ability
+	}
+
+	abilities = utils.CreateUpdate(
+		abilities,
+		func(ability abilityDamage) bool {
+			return ability.enemy == enemy && ability.name == name
+		},
+		func() abilityDamage {
+			return update(abilityDamage{
+				enemy: enemy,
+				name:  name,
+			})
+		},
+		update,
+	)
+
+	slices.SortFunc(abilities, func(a, b abilityDamage) int {
+		return cmp.Compare(b.damage.Total(), a.damage.Total())
+	})
+
+	return abilities
+}
+
+func abilitiesOf(abilities []abilityDamage, enemy string) []abilityDamage {
+	var found []abilityDamage
+	for _, ability := range abilities {
+		if ability.enemy == enemy {
+			found = append(found, ability)
+		}
+	}
+	return found
+}
+
 type enemyDamageWithMax struct {
 	enemies   []enemyDamage
 	maxDamage abstract.Vitals
@@ -39,6 +110,10 @@ type subjectiveDamageTaken struct {
 	indirectDamage       abstract.Vitals
 	damageFromEnemies    enemyDamageWithMax
 	damageFromEnemyTypes enemyDamageWithMax
+	damageFromZones      enemyDamageWithMax
+	abilities            []abilityDamage
+	abilityTypes         []abilityDamage
+	vitalCharts          *vitalCharts
 }
 
 func NewDamageTakenCollector() *DamageTakenCollector {
@@ -46,6 +121,7 @@ func NewDamageTakenCollector() *DamageTakenCollector {
 		"Group By",
 		DontGroup,
 		GroupByType,
+		GroupByZone,
 	)
 	if err != nil {
 		log.Panicln(err)
@@ -61,6 +137,8 @@ func NewDamageTakenCollector() *DamageTakenCollector {
 		DisplayBars,
 		DisplayPie,
 		DisplayGraphs,
+		DisplayAbilities,
+		DisplayVitals,
 	)
 	if err != nil {
 		log.Fatalln(err)
@@ -80,20 +158,21 @@ func NewDamageTakenCollector() *DamageTakenCollector {
 		groupByDropdown: groupByDropdown,
 		victimDropdown:  victimDropdown,
 		longFormatBool:  &widget.Bool{},
+		abilitiesBool:   &widget.Bool{},
 		displayDropdown: displayDropdown,
 		limitDropdown:   limitDropdown,
 		total: subjectiveDamageTaken{
 			timeController: components.NewTimeControllerOrCrash(components.NewTimeBasedChart("Total")),
 			totalChart:     components.NewTimeBasedChart("Total"),
+			vitalCharts:    newVitalCharts(),
 		},
 	}
 }
 
 type DamageTakenCollector struct {
-	currentVictim  string
-	total          subjectiveDamageTaken
-	victims        []subjectiveDamageTaken
-	registeredPets []string
+	currentVictim string
+	total         subjectiveDamageTaken
+	victims       []subjectiveDamageTaken
 
 	currentDisplay displayChoice
 	currentLimit   limitChoice
@@ -102,6 +181,7 @@ type DamageTakenCollector struct {
 	victimDropdown  *components.Dropdown
 	groupByDropdown *components.Dropdown
 	longFormatBool  *widget.Bool
+	abilitiesBool   *widget.Bool
 	displayDropdown *components.Dropdown
 	limitDropdown   *components.Dropdown
 }
@@ -111,49 +191,49 @@ func (d *DamageTakenCollector) Reset(info abstract.StatisticsInformation) {
 	d.total = subjectiveDamageTaken{
 		timeController: components.NewTimeControllerOrCrash(components.NewTimeBasedChart("Total")),
 		totalChart:     components.NewTimeBasedChart("Total"),
+		vitalCharts:    newVitalCharts(),
 	}
-	d.registeredPets = nil
 	d.victimDropdown.SetOptions([]fmt.Stringer{subjectChoice("")})
 	d.currentVictim = ""
 }
 
-func (d *DamageTakenCollector) ingestDamage(event *abstract.ChatEvent) {
+func (d *DamageTakenCollector) ingestDamage(info abstract.StatisticsInformation, event *abstract.ChatEvent) {
 	skillUse := event.Contents.(*abstract.SkillUse)
+	skillName := skillUse.Skill
+
+	if info.Settings().RemoveLevelsFromSkills {
+		skillName = SplitOffId(skillName)
+	}
 
-	findEnemyDamage := func(grouped bool) func(enemy enemyDamage) bool {
+	damage := *skillUse.Damage
+
+	zone := zoneOf(info)
+
+	findEnemyDamage := func(subject string) func(enemy enemyDamage) bool {
 		return func(enemy enemyDamage) bool {
-			if grouped {
-				return enemy.name == SplitOffId(skillUse.Subject)
-			} else {
-				return enemy.name == skillUse.Subject
-			}
+			return enemy.name == subject
 		}
 	}
-	createEnemyDamage := func(grouped bool) func() enemyDamage {
+	createEnemyDamage := func(subject string) func() enemyDamage {
 		return func() enemyDamage {
-			subject := skillUse.Subject
-			if grouped {
-				subject = SplitOffId(subject)
-			}
-
 			chart := components.NewTimeBasedChart(subject)
 			chart.Add(components.TimePoint{
 				Time:    event.Time,
-				Value:   skillUse.Damage.Total(),
-				Details: *skillUse.Damage,
+				Value:   damage.Total(),
+				Details: damage,
 			})
 
 			return enemyDamage{
 				name:   subject,
 				amount: 1,
-				damage: *skillUse.Damage,
+				damage: damage,
 				chart:  chart,
 			}
 		}
 	}
 	updateEnemyDamage := func(enemy enemyDamage) enemyDamage {
 		enemy.amount++
-		enemy.damage = enemy.damage.Add(*skillUse.Damage)
+		enemy.damage = enemy.damage.Add(damage)
 		enemy.chart.Add(components.TimePoint{
 			Time:    event.Time,
 			Value:   enemy.damage.Total(),
@@ -169,11 +249,11 @@ func (d *DamageTakenCollector) ingestDamage(event *abstract.ChatEvent) {
 		return cmp.Compare(b.damage.Total(), a.damage.Total())
 	}
 
-	processEnemyDamageWithMax := func(enemies *enemyDamageWithMax, grouped bool) {
+	processEnemyDamageWithMax := func(enemies *enemyDamageWithMax, subject string) {
 		enemies.enemies = utils.CreateUpdate(
 			enemies.enemies,
-			findEnemyDamage(grouped),
-			createEnemyDamage(grouped),
+			findEnemyDamage(subject),
+			createEnemyDamage(subject),
 			updateEnemyDamage,
 		)
 		slices.SortFunc(enemies.enemies, enemyDamageSort)
@@ -182,7 +262,7 @@ func (d *DamageTakenCollector) ingestDamage(event *abstract.ChatEvent) {
 	}
 
 	processSubjectiveDT := func(subject *subjectiveDamageTaken) {
-		subject.totalDamage = subject.totalDamage.Add(*skillUse.Damage)
+		subject.totalDamage = subject.totalDamage.Add(damage)
 		point := components.TimePoint{
 			Time:    event.Time,
 			Value:   subject.totalDamage.Total(),
@@ -190,8 +270,12 @@ func (d *DamageTakenCollector) ingestDamage(event *abstract.ChatEvent) {
 		}
 		subject.timeController.Add(point)
 		subject.totalChart.Add(point)
-		processEnemyDamageWithMax(&subject.damageFromEnemies, false)
-		processEnemyDamageWithMax(&subject.damageFromEnemyTypes, true)
+		subject.vitalCharts.Add(event.Time, subject.totalDamage)
+		processEnemyDamageWithMax(&subject.damageFromEnemies, skillUse.Subject)
+		processEnemyDamageWithMax(&subject.damageFromEnemyTypes, SplitOffId(skillUse.Subject))
+		processEnemyDamageWithMax(&subject.damageFromZones, zone)
+		subject.abilities = updateAbilityDamage(subject.abilities, skillUse.Subject, skillName, skillUse, damage)
+		subject.abilityTypes = updateAbilityDamage(subject.abilityTypes, SplitOffId(skillUse.Subject), skillName, skillUse, damage)
 	}
 
 	// Ingest total stuff
@@ -204,7 +288,7 @@ func (d *DamageTakenCollector) ingestDamage(event *abstract.ChatEvent) {
 			return victim.victim == skillUse.Victim
 		},
 		func() subjectiveDamageTaken {
-			totalDamage := *skillUse.Damage
+			totalDamage := damage
 
 			timeController := components.NewTimeControllerOrCrash(components.NewTimeBasedChart("Total"))
 			totalChart := components.NewTimeBasedChart("Total")
@@ -216,25 +300,38 @@ func (d *DamageTakenCollector) ingestDamage(event *abstract.ChatEvent) {
 			timeController.Add(point)
 			totalChart.Add(point)
 
+			charts := newVitalCharts()
+			charts.Add(event.Time, totalDamage)
+
 			return subjectiveDamageTaken{
 				victim:      skillUse.Victim,
 				totalDamage: totalDamage,
 				damageFromEnemies: enemyDamageWithMax{
 					enemies: []enemyDamage{
-						createEnemyDamage(false)(),
+						createEnemyDamage(skillUse.Subject)(),
 					},
-					maxDamage: *skillUse.Damage,
-					maxRange:  components.DataRange{Max: skillUse.Damage.Total()},
+					maxDamage: damage,
+					maxRange:  components.DataRange{Max: damage.Total()},
 				},
 				damageFromEnemyTypes: enemyDamageWithMax{
 					enemies: []enemyDamage{
-						createEnemyDamage(true)(),
+						createEnemyDamage(SplitOffId(skillUse.Subject))(),
 					},
-					maxDamage: *skillUse.Damage,
-					maxRange:  components.DataRange{Max: skillUse.Damage.Total()},
+					maxDamage: damage,
+					maxRange:  components.DataRange{Max: damage.Total()},
 				},
+				damageFromZones: enemyDamageWithMax{
+					enemies: []enemyDamage{
+						createEnemyDamage(zone)(),
+					},
+					maxDamage: damage,
+					maxRange:  components.DataRange{Max: damage.Total()},
+				},
+				abilities:      updateAbilityDamage(nil, skillUse.Subject, skillName, skillUse, damage),
+				abilityTypes:   updateAbilityDamage(nil, SplitOffId(skillUse.Subject), skillName, skillUse, damage),
 				timeController: timeController,
 				totalChart:     totalChart,
+				vitalCharts:    charts,
 			}
 		},
 		func(victim subjectiveDamageTaken) subjectiveDamageTaken {
@@ -258,11 +355,78 @@ func (d *DamageTakenCollector) ingestDamage(event *abstract.ChatEvent) {
 	))
 }
 
+// ingestEvade Evaded attacks only count towards abilities, enemy damage and charts are about hits
+func (d *DamageTakenCollector) ingestEvade(info abstract.StatisticsInformation, event *abstract.ChatEvent) {
+	skillUse := event.Contents.(*abstract.SkillUse)
+	skillName := skillUse.Skill
+
+	if info.Settings().RemoveLevelsFromSkills {
+		skillName = SplitOffId(skillName)
+	}
+
+	processAbilities := func(subject *subjectiveDamageTaken) {
+		subject.abilities = updateAbilityDamage(subject.abilities, skillUse.Subject, skillName, skillUse, abstract.Vitals{})
+		subject.abilityTypes = updateAbilityDamage(subject.abilityTypes, SplitOffId(skillUse.Subject), skillName, skillUse, abstract.Vitals{})
+	}
+
+	processAbilities(&d.total)
+
+	d.victims = utils.CreateUpdate(
+		d.victims,
+		func(victim subjectiveDamageTaken) bool {
+			return victim.victim == skillUse.Victim
+		},
+		func() subjectiveDamageTaken {
+			timeController := components.NewTimeControllerOrCrash(components.NewTimeBasedChart("Total"))
+			totalChart := components.NewTimeBasedChart("Total")
+			point := components.TimePoint{
+				Time:    event.Time,
+				Value:   0,
+				Details: abstract.Vitals{},
+			}
+			timeController.Add(point)
+			totalChart.Add(point)
+
+			charts := newVitalCharts()
+			charts.Add(event.Time, abstract.Vitals{})
+
+			victim := subjectiveDamageTaken{
+				victim:         skillUse.Victim,
+				timeController: timeController,
+				totalChart:     totalChart,
+				vitalCharts:    charts,
+			}
+			processAbilities(&victim)
+
+			return victim
+		},
+		func(victim subjectiveDamageTaken) subjectiveDamageTaken {
+			processAbilities(&victim)
+			return victim
+		},
+	)
+
+	d.victimDropdown.SetOptions(utils.CreateUpdate(
+		d.victimDropdown.Options(),
+		func(item fmt.Stringer) bool {
+			casted := item.(subjectChoice)
+			return string(casted) == skillUse.Victim
+		},
+		func() fmt.Stringer {
+			return subjectChoice(skillUse.Victim)
+		},
+		func(stringer fmt.Stringer) fmt.Stringer {
+			return stringer
+		},
+	))
+}
+
 func (d *DamageTakenCollector) ingestIndirectDamage(event *abstract.ChatEvent) {
 	indirect := event.Contents.(*abstract.IndirectDamage)
 	indirectDamage := indirect.Damage.Abs()
 	d.total.totalDamage = d.total.totalDamage.Add(indirectDamage)
 	d.total.indirectDamage = d.total.indirectDamage.Add(indirectDamage)
+	d.total.vitalCharts.Add(event.Time, d.total.totalDamage)
 
 	d.victims = utils.CreateUpdate(
 		d.victims,
@@ -270,7 +434,7 @@ func (d *DamageTakenCollector) ingestIndirectDamage(event *abstract.ChatEvent) {
 			return victim.victim == indirect.Subject
 		},
 		func() subjectiveDamageTaken {
-			totalDamage := indirect.Damage
+			totalDamage := indirectDamage
 
 			timeController := components.NewTimeControllerOrCrash(components.NewTimeBasedChart("Total"))
 			totalChart := components.NewTimeBasedChart("Total")
@@ -282,12 +446,16 @@ func (d *DamageTakenCollector) ingestIndirectDamage(event *abstract.ChatEvent) {
 			timeController.Add(point)
 			totalChart.Add(point)
 
+			charts := newVitalCharts()
+			charts.Add(event.Time, totalDamage)
+
 			return subjectiveDamageTaken{
 				victim:         indirect.Subject,
 				totalDamage:    totalDamage,
 				indirectDamage: totalDamage,
 				timeController: timeController,
 				totalChart:     totalChart,
+				vitalCharts:    charts,
 			}
 		},
 		func(victim subjectiveDamageTaken) subjectiveDamageTaken {
@@ -302,62 +470,161 @@ func (d *DamageTakenCollector) ingestIndirectDamage(event *abstract.ChatEvent) {
 
 			victim.timeController.Add(point)
 			victim.totalChart.Add(point)
+			victim.vitalCharts.Add(event.Time, victim.totalDamage)
 
 			return victim
 		},
 	)
 }
 
-func (d *DamageTakenCollector) lookForPet(skillUse *abstract.SkillUse) {
-	if strings.Contains(skillUse.Skill, "(Pet)") {
-		d.registeredPets = append(d.registeredPets, skillUse.Subject)
+func (d *DamageTakenCollector) Tick(info abstract.StatisticsInformation, at time.Time) {
+
+}
+
+func (d *DamageTakenCollector) Collect(info abstract.StatisticsInformation, event *abstract.ChatEvent) error {
+	if skillUse, ok := event.Contents.(*abstract.SkillUse); ok && info.IsAlly(skillUse.Victim, "") {
+		if skillUse.Damage != nil {
+			d.ingestDamage(info, event)
+		} else if skillUse.Evaded {
+			d.ingestEvade(info, event)
+		}
 	}
+
+	if indirect, ok := event.Contents.(*abstract.IndirectDamage); ok && info.IsAlly(indirect.Subject, "") {
+		d.ingestIndirectDamage(event)
+	}
+
+	return nil
 }
 
-func (d *DamageTakenCollector) isVictimValuable(info abstract.StatisticsInformation, victim string) bool {
-	if victim == info.CurrentUsername() {
-		return true
+func (d *DamageTakenCollector) TabName() string {
+	return "Damage Taken"
+}
+
+func (d *DamageTakenCollector) takenBy(victimName string) int {
+	for _, victim := range d.victims {
+		if victim.victim == victimName {
+			return victim.totalDamage.Total()
+		}
 	}
 
-	for _, pet := range d.registeredPets {
-		if pet == victim {
-			return true
+	return 0
+}
+
+func (d *DamageTakenCollector) Metrics(character string) []abstract.Metric {
+	var metrics []abstract.Metric
+
+	for _, victim := range d.victims {
+		if victim.victim != character {
+			continue
+		}
+
+		for _, enemy := range victim.damageFromEnemyTypes.enemies {
+			metrics = append(metrics, abstract.Metric{
+				Name:   "pgct_damage_taken",
+				Help:   "Damage taken to health, armor and power",
+				Type:   abstract.MetricCounter,
+				Labels: []abstract.MetricLabel{{Name: "enemy_type", Value: enemy.name}},
+				Value:  float64(enemy.damage.Total()),
+			})
 		}
 	}
 
-	lowTrimmedSubject := strings.ToLower(strings.TrimSpace(SplitOffId(victim)))
+	return metrics
+}
 
-	for _, expectedName := range info.Settings().EntitiesThatCountAsPets {
-		if lowTrimmedSubject == strings.ToLower(strings.TrimSpace(expectedName)) {
-			return true
+func (d *DamageTakenCollector) Summary(character string) string {
+	victim := d.total
+	if character != "" {
+		index := slices.IndexFunc(d.victims, func(victim subjectiveDamageTaken) bool {
+			return victim.victim == character
+		})
+		if index < 0 {
+			return ""
 		}
+		victim = d.victims[index]
+	}
+
+	if victim.totalDamage.Total() == 0 {
+		return ""
 	}
 
-	return false
+	return fmt.Sprintf("Damage taken: %v", utils.FormatNumber(victim.totalDamage.Total()))
 }
 
-func (d *DamageTakenCollector) Tick(info abstract.StatisticsInformation, at time.Time) {
+type DamageTakenSnapshot struct {
+	Name    string
+	Damage  abstract.Vitals
+	Enemies []EnemyDamageSnapshot
+}
 
+type EnemyDamageSnapshot struct {
+	Name   string
+	Damage abstract.Vitals
+	Hits   int
 }
 
-func (d *DamageTakenCollector) Collect(info abstract.StatisticsInformation, event *abstract.ChatEvent) error {
-	if skillUse, ok := event.Contents.(*abstract.SkillUse); ok && skillUse.Damage != nil {
-		d.lookForPet(skillUse)
+func snapshotDamageTaken(victim subjectiveDamageTaken) DamageTakenSnapshot {
+	snapshot := DamageTakenSnapshot{
+		Name:    victim.victim,
+		Damage:  victim.totalDamage,
+		Enemies: make([]EnemyDamageSnapshot, len(victim.damageFromEnemyTypes.enemies)),
+	}
 
-		if d.isVictimValuable(info, skillUse.Victim) {
-			d.ingestDamage(event)
+	for i, enemy := range victim.damageFromEnemyTypes.enemies {
+		snapshot.Enemies[i] = EnemyDamageSnapshot{
+			Name:   enemy.name,
+			Damage: enemy.damage,
+			Hits:   enemy.amount,
 		}
 	}
 
-	if indirect, ok := event.Contents.(*abstract.IndirectDamage); ok && d.isVictimValuable(info, indirect.Subject) {
-		d.ingestIndirectDamage(event)
+	return snapshot
+}
+
+// Snapshot Damage taken by everyone and by every victim, split by enemy type
+func (d *DamageTakenCollector) Snapshot() any {
+	victims := make([]DamageTakenSnapshot, len(d.victims))
+	for i, victim := range d.victims {
+		victims[i] = snapshotDamageTaken(victim)
 	}
 
-	return nil
+	return struct {
+		Total   DamageTakenSnapshot
+		Victims []DamageTakenSnapshot
+	}{
+		Total:   snapshotDamageTaken(d.total),
+		Victims: victims,
+	}
 }
 
-func (d *DamageTakenCollector) TabName() string {
-	return "Damage Taken"
+func (d *DamageTakenCollector) ComparisonRows() []abstract.ComparisonRow {
+	victim := d.total
+	for _, possibleVictim := range d.victims {
+		if possibleVictim.victim == d.currentVictim {
+			victim = possibleVictim
+			break
+		}
+	}
+
+	rows := []abstract.ComparisonRow{{
+		Metric:        "Damage taken",
+		Name:          "Total",
+		Value:         float64(victim.totalDamage.Total()),
+		LowerIsBetter: true,
+	}}
+
+	// Enemy types, because names of individual enemies have ids that won't match between sessions
+	for _, enemy := range victim.damageFromEnemyTypes.enemies {
+		rows = append(rows, abstract.ComparisonRow{
+			Metric:        "Damage taken",
+			Name:          enemy.name,
+			Value:         float64(enemy.damage.Total()),
+			LowerIsBetter: true,
+		})
+	}
+
+	return rows
 }
 
 type GroupBy int
@@ -365,6 +632,7 @@ type GroupBy int
 const (
 	DontGroup GroupBy = iota
 	GroupByType
+	GroupByZone
 )
 
 func (g GroupBy) String() string {
@@ -373,11 +641,22 @@ func (g GroupBy) String() string {
 		return "Don't Group"
 	case GroupByType:
 		return "Group By Enemy Type"
+	case GroupByZone:
+		return "Group By Zone"
 	}
 
 	return "Unknown"
 }
 
+// pieEnemies The pie always shows enemy types, unless it's grouped by zone
+func (d *DamageTakenCollector) pieEnemies(victim subjectiveDamageTaken) []enemyDamage {
+	if d.groupByDropdown.Value.(GroupBy) == GroupByZone {
+		return victim.damageFromZones.enemies
+	}
+
+	return victim.damageFromEnemyTypes.enemies
+}
+
 func (d *DamageTakenCollector) drawWidget(state abstract.LayeredState, enemy enemyDamage, widget layout.Widget, size unit.Dp) layout.Widget {
 	return drawUniversalStatsText(
 		state, enemy.damage,
@@ -396,6 +675,24 @@ func (d *DamageTakenCollector) drawBar(state abstract.LayeredState, enemy enemyD
 	)
 }
 
+func (d *DamageTakenCollector) drawAbilityBar(state abstract.LayeredState, ability abilityDamage, name string, maxDamage int, size unit.Dp) layout.Widget {
+	return drawUniversalBar(
+		state, ability.damage,
+		ability.damage.Total(), maxDamage, ability.hits,
+		name, "hit %v times",
+		size, d.longFormatBool.Value,
+	)
+}
+
+func (d *DamageTakenCollector) abilityCaption(state abstract.LayeredState, ability abilityDamage, totalTaken int) layout.Widget {
+	return func(gtx layout.Context) layout.Dimensions {
+		return layout.Inset{
+			Left:   utils.CommonSpacing * 2,
+			Bottom: utils.CommonSpacing,
+		}.Layout(gtx, defaultLabelStyle(state, ability.caption(totalTaken, d.longFormatBool.Value)).Layout)
+	}
+}
+
 func (d *DamageTakenCollector) UI(state abstract.LayeredState) (layout.Widget, []layout.Widget) {
 	if d.victimDropdown.Changed() {
 		d.currentVictim = string(d.victimDropdown.Value.(subjectChoice))
@@ -418,7 +715,7 @@ func (d *DamageTakenCollector) UI(state abstract.LayeredState) (layout.Widget, [
 	}
 
 	topWidget := topBarSurface(func(gtx layout.Context) layout.Dimensions {
-		if d.longFormatBool.Update(gtx) {
+		if d.longFormatBool.Update(gtx) || d.abilitiesBool.Update(gtx) {
 			gtx.Source.Execute(op.InvalidateCmd{})
 		}
 
@@ -440,18 +737,29 @@ func (d *DamageTakenCollector) UI(state abstract.LayeredState) (layout.Widget, [
 						switch d.currentDisplay {
 						case DisplayGraphs:
 							fallthrough
+						case DisplayVitals:
+							fallthrough
 						case DisplayBars:
 							return defaultDropdownStyle(state, d.groupByDropdown).Layout(gtx)
 						case DisplayPie:
+							fallthrough
+						case DisplayAbilities:
 							return defaultDropdownStyle(state, d.limitDropdown).Layout(gtx)
 						}
 
+						return layout.Dimensions{}
+					},
+					func(gtx layout.Context) layout.Dimensions {
+						if d.currentDisplay == DisplayBars {
+							return defaultCheckboxStyle(state, d.abilitiesBool, "Show abilities").Layout(gtx)
+						}
+
 						return layout.Dimensions{}
 					},
 				)
 			}),
 			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-				if d.currentDisplay == DisplayGraphs {
+				if d.currentDisplay == DisplayGraphs || d.currentDisplay == DisplayVitals {
 					return layout.Flex{
 						Axis: layout.Vertical,
 					}.Layout(
@@ -470,11 +778,16 @@ func (d *DamageTakenCollector) UI(state abstract.LayeredState) (layout.Widget, [
 
 	// All the bars go here
 	var enemies *enemyDamageWithMax
+	var abilities []abilityDamage
 	switch d.groupByDropdown.Value.(GroupBy) {
 	case DontGroup:
 		enemies = &victim.damageFromEnemies
+		abilities = victim.abilities
 	case GroupByType:
 		enemies = &victim.damageFromEnemyTypes
+		abilities = victim.abilityTypes
+	case GroupByZone:
+		enemies = &victim.damageFromZones
 	default:
 		log.Fatalln("wtf happened to the dropdown")
 	}
@@ -496,6 +809,16 @@ func (d *DamageTakenCollector) UI(state abstract.LayeredState) (layout.Widget, [
 
 		for _, enemy := range enemies.enemies {
 			widgets = append(widgets, d.drawBar(state, enemy, maxDamage, 40))
+
+			if d.abilitiesBool.Value {
+				for _, ability := range abilitiesOf(abilities, enemy.name) {
+					widgets = append(
+						widgets,
+						d.drawAbilityBar(state, ability, ability.name, enemy.damage.Total(), 30),
+						d.abilityCaption(state, ability, victim.totalDamage.Total()),
+					)
+				}
+			}
 		}
 
 		widgets = append(widgets, d.drawBar(
@@ -511,8 +834,9 @@ func (d *DamageTakenCollector) UI(state abstract.LayeredState) (layout.Widget, [
 	case DisplayPie:
 		widgets = append(widgets, func(gtx layout.Context) layout.Dimensions {
 			var totalValue int
-			pieItems := make([]components.PieChartItem, 0, max(1, len(victim.damageFromEnemyTypes.enemies)))
-			for i, enemy := range victim.damageFromEnemyTypes.enemies {
+			pieEnemies := d.pieEnemies(victim)
+			pieItems := make([]components.PieChartItem, 0, max(1, len(pieEnemies)))
+			for i, enemy := range pieEnemies {
 				if i >= d.currentLimit.Int() {
 					break
 				}
@@ -562,6 +886,38 @@ func (d *DamageTakenCollector) UI(state abstract.LayeredState) (layout.Widget, [
 
 			widgets = append(widgets, d.drawWidget(state, enemy, chartStyle.Layout, 100))
 		}
+	case DisplayAbilities:
+		maxDamage := 0
+		if len(victim.abilityTypes) > 0 {
+			maxDamage = victim.abilityTypes[0].damage.Total()
+		}
+
+		for i, ability := range victim.abilityTypes {
+			if i >= d.currentLimit.Int() {
+				break
+			}
+
+			widgets = append(
+				widgets,
+				d.drawAbilityBar(state, ability, fmt.Sprintf("%v (%v)", ability.name, ability.enemy), maxDamage, 40),
+				d.abilityCaption(state, ability, victim.totalDamage.Total()),
+			)
+		}
+	case DisplayVitals:
+		widgets = append(
+			widgets,
+			drawVitalsSplit(state, victim.vitalCharts, victim.timeController, victim.totalDamage, d.longFormatBool.Value)...,
+		)
+
+		maxDamage := enemies.maxDamage.Total()
+		for _, enemy := range enemies.enemies {
+			widgets = append(widgets, drawVitalsBar(
+				state, enemy.damage,
+				maxDamage, enemy.amount,
+				enemy.name, "attacked %v times",
+				40, d.longFormatBool.Value,
+			))
+		}
 	}
 
 	return topWidget, widgets
@@ -585,6 +941,23 @@ func (d *DamageTakenCollector) exportBar(styledFonts *drawing.StyledFontPack, en
 	)
 }
 
+func (d *DamageTakenCollector) exportAbilityBar(styledFonts *drawing.StyledFontPack, ability abilityDamage, name string, maxDamage, totalTaken int) drawing.Widget {
+	return drawing.Flex{
+		Axis:    layout.Vertical,
+		ExpandW: true,
+	}.Layout(
+		drawing.Rigid(exportUniversalBar(
+			styledFonts, ability.damage,
+			ability.damage.Total(), maxDamage, ability.hits,
+			name, "hit %v times",
+			d.longFormatBool.Value,
+		)),
+		drawing.Rigid(drawing.UniformInset(drawing.CommonSpacing).Layout(
+			styledFonts.Smaller.Layout(ability.caption(totalTaken, d.longFormatBool.Value)),
+		)),
+	)
+}
+
 func (d *DamageTakenCollector) Export(state abstract.LayeredState) image.Image {
 	victim := d.total
 	for _, possibleVictim := range d.victims {
@@ -595,11 +968,16 @@ func (d *DamageTakenCollector) Export(state abstract.LayeredState) image.Image {
 	}
 
 	var enemies *enemyDamageWithMax
+	var abilities []abilityDamage
 	switch d.groupByDropdown.Value.(GroupBy) {
 	case DontGroup:
 		enemies = &victim.damageFromEnemies
+		abilities = victim.abilities
 	case GroupByType:
 		enemies = &victim.damageFromEnemyTypes
+		abilities = victim.abilityTypes
+	case GroupByZone:
+		enemies = &victim.damageFromZones
 	default:
 		log.Fatalln("wtf happened to the dropdown")
 	}
@@ -634,6 +1012,19 @@ func (d *DamageTakenCollector) Export(state abstract.LayeredState) image.Image {
 			}
 
 			items = append(items, drawing.Rigid(d.exportBar(styledFonts, enemy, maxDamage)))
+
+			if d.abilitiesBool.Value {
+				for _, ability := range abilitiesOf(abilities, enemy.name) {
+					items = append(
+						items,
+						drawing.FlexVSpacer(drawing.CommonSpacing),
+						drawing.Rigid(d.exportAbilityBar(
+							styledFonts, ability, ability.name,
+							enemy.damage.Total(), victim.totalDamage.Total(),
+						)),
+					)
+				}
+			}
 		}
 
 		items = append(
@@ -659,8 +1050,9 @@ func (d *DamageTakenCollector) Export(state abstract.LayeredState) image.Image {
 	case DisplayPie:
 		var totalValue int
 
-		pieItems := make([]drawing.PieChartItem, 0, max(1, len(victim.damageFromEnemyTypes.enemies)))
-		for i, enemy := range victim.damageFromEnemyTypes.enemies {
+		pieEnemies := d.pieEnemies(victim)
+		pieItems := make([]drawing.PieChartItem, 0, max(1, len(pieEnemies)))
+		for i, enemy := range pieEnemies {
 			if i >= d.currentLimit.Int() {
 				break
 			}
@@ -718,6 +1110,58 @@ func (d *DamageTakenCollector) Export(state abstract.LayeredState) image.Image {
 			items = append(items, drawing.Rigid(d.exportWidget(styledFonts, enemy, style.Layout())))
 		}
 
+		body = drawing.Flex{
+			ExpandW: true,
+			Axis:    layout.Vertical,
+		}.Layout(
+			items...,
+		)
+	case DisplayAbilities:
+		var items []drawing.FlexChild
+
+		maxDamage := 0
+		if len(victim.abilityTypes) > 0 {
+			maxDamage = victim.abilityTypes[0].damage.Total()
+		}
+
+		for i, ability := range victim.abilityTypes {
+			if i >= d.currentLimit.Int() {
+				break
+			}
+
+			if i != 0 {
+				items = append(items, drawing.FlexVSpacer(drawing.CommonSpacing))
+			}
+
+			items = append(items, drawing.Rigid(d.exportAbilityBar(
+				styledFonts, ability, fmt.Sprintf("%v (%v)", ability.name, ability.enemy),
+				maxDamage, victim.totalDamage.Total(),
+			)))
+		}
+
+		body = drawing.Flex{
+			ExpandW: true,
+			Axis:    layout.Vertical,
+		}.Layout(
+			items...,
+		)
+	case DisplayVitals:
+		items := exportVitalsSplit(styledFonts, victim.vitalCharts, victim.timeController, victim.totalDamage, d.longFormatBool.Value)
+
+		maxDamage := enemies.maxDamage.Total()
+		for _, enemy := range enemies.enemies {
+			items = append(
+				items,
+				drawing.FlexVSpacer(drawing.CommonSpacing),
+				drawing.Rigid(exportVitalsBar(
+					styledFonts, enemy.damage,
+					maxDamage, enemy.amount,
+					enemy.name, "attacked %v times",
+					d.longFormatBool.Value,
+				)),
+			)
+		}
+
 		body = drawing.Flex{
 			ExpandW: true,
 			Axis:    layout.Vertical,
@@ -743,7 +1187,7 @@ func (d *DamageTakenCollector) Export(state abstract.LayeredState) image.Image {
 			},
 			styledFonts.Smaller.Layout(fmt.Sprintf("Display: %v", d.currentDisplay)),
 			func(ltx drawing.Context) drawing.Result {
-				if d.currentDisplay != DisplayPie {
+				if d.currentDisplay != DisplayPie && d.currentDisplay != DisplayAbilities {
 					return styledFonts.Smaller.Layout(fmt.Sprintf("Group: %v", d.groupByDropdown.Value.(GroupBy)))(ltx)
 				}
 
@@ -758,3 +1202,63 @@ func (d *DamageTakenCollector) Export(state abstract.LayeredState) image.Image {
 
 	return drawing.ExportImage(state.Theme(), base, drawing.F64(800, 10000))
 }
+
+// textVictim Victim and enemies the way the tab currently shows them
+func (d *DamageTakenCollector) textVictim() (subjectiveDamageTaken, []enemyDamage) {
+	victim := d.total
+	for _, possibleVictim := range d.victims {
+		if possibleVictim.victim == d.currentVictim {
+			victim = possibleVictim
+			break
+		}
+	}
+
+	switch d.groupByDropdown.Value.(GroupBy) {
+	case GroupByType:
+		return victim, victim.damageFromEnemyTypes.enemies
+	case GroupByZone:
+		return victim, victim.damageFromZones.enemies
+	}
+
+	return victim, victim.damageFromEnemies.enemies
+}
+
+func (d *DamageTakenCollector) TextTable(state abstract.LayeredState) abstract.TextTable {
+	victim, enemies := d.textVictim()
+	long := d.longFormatBool.Value
+
+	table := abstract.TextTable{
+		Title: textTitle(d.TabName()),
+		Parameters: []string{
+			fmt.Sprintf("Victim: %v", subjectChoice(d.currentVictim)),
+			fmt.Sprintf("Group: %v", d.groupByDropdown.Value.(GroupBy)),
+			fmt.Sprintf("Total damage: %v", victim.totalDamage.StringCL(long)),
+			fmt.Sprintf("Indirect damage: %v", victim.indirectDamage.StringCL(long)),
+		},
+		TimeFrame: textTimeFrame(victim.timeController.CurrentTimeFrame),
+		Columns:   []string{"Enemy", "Attacks", "Damage", "Share"},
+	}
+
+	for _, enemy := range enemies {
+		table.Rows = append(table.Rows, []string{
+			enemy.name,
+			strconv.Itoa(enemy.amount),
+			enemy.damage.StringCL(long),
+			textShare(enemy.damage.Total(), victim.totalDamage.Total()),
+		})
+	}
+
+	return table
+}
+
+func (d *DamageTakenCollector) ChatLine(state abstract.LayeredState) string {
+	victim, enemies := d.textVictim()
+	total := victim.totalDamage.Total()
+
+	top := ""
+	if len(enemies) > 0 {
+		top = chatTop(enemies[0].name, enemies[0].damage.Total(), total)
+	}
+
+	return chatLine(fmt.Sprintf("Damage taken: %v", utils.FormatNumber(total)), top)
+}
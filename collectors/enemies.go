@@ -0,0 +1,992 @@
+package collectors
+
+import (
+	"PGCombatTracker/abstract"
+	"PGCombatTracker/ui/components"
+	"PGCombatTracker/utils"
+	"PGCombatTracker/utils/drawing"
+	"cmp"
+	"fmt"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/widget"
+	"image"
+	"log"
+	"slices"
+	"strings"
+	"time"
+)
+
+type enemyViewChoice uint8
+
+const (
+	ViewEnemyTypes enemyViewChoice = iota
+	ViewUnkilledEnemies
+	ViewEnemiesInCombat
+	ViewEnemyTargets
+)
+
+func (e enemyViewChoice) String() string {
+	switch e {
+	case ViewEnemyTypes:
+		return "Time to kill"
+	case ViewUnkilledEnemies:
+		return "Not killed"
+	case ViewEnemiesInCombat:
+		return "In combat"
+	case ViewEnemyTargets:
+		return "Targets"
+	}
+	return ""
+}
+
+type ttkValue time.Duration
+
+func (t ttkValue) StringCL(long bool) string {
+	if long {
+		return time.Duration(t).Round(100 * time.Millisecond).String()
+	}
+	return time.Duration(t).Round(time.Second).String()
+}
+
+func (t ttkValue) Interpolate(other utils.Interpolatable, interpolant float64) utils.Interpolatable {
+	otherTTK, ok := other.(ttkValue)
+	if !ok {
+		return other
+	}
+
+	return ttkValue(utils.LerpInt(int(t), int(otherTTK), interpolant))
+}
+
+func (t ttkValue) InterpolateILF(other utils.InterpolatableLongFormatable, interpolant float64) utils.InterpolatableLongFormatable {
+	return t.Interpolate(other, interpolant).(utils.InterpolatableLongFormatable)
+}
+
+type ttkSummary struct {
+	average time.Duration
+	min     time.Duration
+	max     time.Duration
+}
+
+func (t ttkSummary) StringCL(long bool) string {
+	return fmt.Sprintf(
+		"avg %v (%v - %v)",
+		ttkValue(t.average).StringCL(long),
+		ttkValue(t.min).StringCL(long),
+		ttkValue(t.max).StringCL(long),
+	)
+}
+
+type enemyCounter int
+
+func (counter enemyCounter) StringCL(long bool) string {
+	if long {
+		return fmt.Sprintf("%d enemies", counter)
+	}
+	return fmt.Sprintf("%v enemies", utils.FormatNumber(int(counter)))
+}
+
+type remainingHealth struct {
+	known     bool
+	remaining int
+	ttk       time.Duration
+}
+
+func (r remainingHealth) StringCL(long bool) string {
+	if !r.known {
+		return "Health unknown"
+	}
+
+	remaining := utils.FormatNumber(r.remaining)
+	if long {
+		remaining = fmt.Sprint(r.remaining)
+	}
+
+	if r.ttk <= 0 {
+		return fmt.Sprintf("~%v HP left", remaining)
+	}
+
+	return fmt.Sprintf("~%v HP left, ~%v to kill", remaining, ttkValue(r.ttk).StringCL(long))
+}
+
+type enemyContribution struct {
+	name   string
+	damage abstract.Vitals
+}
+
+// enemyInstance Single enemy from the first hit it took until it died
+type enemyInstance struct {
+	name         string
+	enemyType    string
+	firstHit     time.Time
+	lastHit      time.Time
+	damage       abstract.Vitals
+	hits         int
+	contributors []enemyContribution
+	killed       bool
+	estimate     abstract.EnemyHealthEstimate
+	hasEstimate  bool
+}
+
+func (e enemyInstance) ttk() time.Duration {
+	return e.lastHit.Sub(e.firstHit)
+}
+
+// remaining Uses learned health of the enemy type and damage rate so far to guess how long it has left
+func (e enemyInstance) remaining() remainingHealth {
+	if !e.hasEstimate {
+		return remainingHealth{}
+	}
+
+	dealt := e.damage.Health + e.damage.Armor
+	remaining := max(0, int(e.estimate.Mean)-dealt)
+
+	elapsed := max(e.ttk(), time.Second)
+	dps := float64(dealt) / elapsed.Seconds()
+
+	var ttk time.Duration
+	if dps > 0 {
+		ttk = time.Duration(float64(remaining) / dps * float64(time.Second))
+	}
+
+	return remainingHealth{
+		known:     true,
+		remaining: remaining,
+		ttk:       ttk,
+	}
+}
+
+func (e enemyInstance) contributorsString(long bool) string {
+	parts := make([]string, len(e.contributors))
+	for i, contributor := range e.contributors {
+		parts[i] = fmt.Sprintf("%v (%v)", contributor.name, contributor.damage.StringCL(long))
+	}
+	return strings.Join(parts, ", ")
+}
+
+type enemyTypeStats struct {
+	name    string
+	engaged int
+	kills   int
+	ttks    []time.Duration
+}
+
+func (e enemyTypeStats) summary() ttkSummary {
+	if len(e.ttks) == 0 {
+		return ttkSummary{}
+	}
+
+	var total time.Duration
+	for _, ttk := range e.ttks {
+		total += ttk
+	}
+
+	return ttkSummary{
+		average: total / time.Duration(len(e.ttks)),
+		min:     slices.Min(e.ttks),
+		max:     slices.Max(e.ttks),
+	}
+}
+
+type targetShare struct {
+	damage abstract.Vitals
+	share  float64
+}
+
+func (t targetShare) StringCL(long bool) string {
+	return fmt.Sprintf("%v (%.0f%%)", t.damage.StringCL(long), t.share*100)
+}
+
+type enemyTarget struct {
+	name   string
+	hits   int
+	damage abstract.Vitals
+}
+
+type targetSwitch struct {
+	at     time.Time
+	target string
+}
+
+// enemyAggro Who a single enemy attacked and when it changed its mind
+type enemyAggro struct {
+	name      string
+	enemyType string
+	targets   []enemyTarget
+	switches  []targetSwitch
+}
+
+func (a enemyAggro) timeline() string {
+	parts := make([]string, len(a.switches))
+	for i, change := range a.switches {
+		parts[i] = fmt.Sprintf("%v %v", change.at.Format(time.TimeOnly), change.target)
+	}
+	return strings.Join(parts, " → ")
+}
+
+func addTarget(targets []enemyTarget, target enemyTarget) []enemyTarget {
+	return utils.CreateUpdate(
+		targets,
+		func(existing enemyTarget) bool {
+			return existing.name == target.name
+		},
+		func() enemyTarget {
+			return target
+		},
+		func(existing enemyTarget) enemyTarget {
+			existing.hits += target.hits
+			existing.damage = existing.damage.Add(target.damage)
+			return existing
+		},
+	)
+}
+
+// enemyRow Description of a single bar, so UI and export show the same thing, rows without side text are only captions
+type enemyRow struct {
+	sideText     utils.LongFormatable
+	value, max   int
+	amount       int
+	name         string
+	amountFormat string
+	caption      string
+}
+
+const ttkDistributionBuckets = 6
+
+func NewEnemiesCollector() *EnemiesCollector {
+	viewDropdown, err := components.NewDropdown(
+		"View",
+		ViewEnemyTypes,
+		ViewUnkilledEnemies,
+		ViewEnemiesInCombat,
+		ViewEnemyTargets,
+	)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	typeDropdown, err := components.NewDropdown("Enemy", subjectChoice(""))
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	return &EnemiesCollector{
+		alive:          make(map[string]int),
+		aggroIndex:     make(map[string]int),
+		viewDropdown:   viewDropdown,
+		typeDropdown:   typeDropdown,
+		longFormatBool: &widget.Bool{},
+	}
+}
+
+// EnemiesCollector Tracks every enemy instance, who it attacked and how long it took to kill it
+type EnemiesCollector struct {
+	instances []enemyInstance
+	types     []enemyTypeStats
+	// alive Indices of instances that haven't died yet
+	alive map[string]int
+	aggro []enemyAggro
+	// aggroIndex Indices of enemies in aggro that are still alive
+	aggroIndex map[string]int
+	// now Time of the latest tick or event, to know which enemies are still in combat
+	now          time.Time
+	combatWindow time.Duration
+
+	currentView    enemyViewChoice
+	currentType    string
+	viewDropdown   *components.Dropdown
+	typeDropdown   *components.Dropdown
+	longFormatBool *widget.Bool
+}
+
+func (e *EnemiesCollector) Reset(info abstract.StatisticsInformation) {
+	e.instances = nil
+	e.types = nil
+	e.alive = make(map[string]int)
+	e.aggro = nil
+	e.aggroIndex = make(map[string]int)
+	e.typeDropdown.SetOptions([]fmt.Stringer{subjectChoice("")})
+	e.currentType = ""
+}
+
+func (e *EnemiesCollector) updateType(enemyType string, updateFunc func(stats enemyTypeStats) enemyTypeStats) {
+	e.types = utils.CreateUpdate(
+		e.types,
+		func(stats enemyTypeStats) bool {
+			return stats.name == enemyType
+		},
+		func() enemyTypeStats {
+			return updateFunc(enemyTypeStats{
+				name: enemyType,
+			})
+		},
+		updateFunc,
+	)
+}
+
+func (e *EnemiesCollector) addTypeOption(enemyType string) {
+	e.typeDropdown.SetOptions(utils.CreateUpdate(
+		e.typeDropdown.Options(),
+		func(item fmt.Stringer) bool {
+			casted := item.(subjectChoice)
+			return string(casted) == enemyType
+		},
+		func() fmt.Stringer {
+			return subjectChoice(enemyType)
+		},
+		func(stringer fmt.Stringer) fmt.Stringer {
+			return stringer
+		},
+	))
+}
+
+func (e *EnemiesCollector) attack(enemy, target string, damage abstract.Vitals, at time.Time) {
+	index, ok := e.aggroIndex[enemy]
+	if !ok {
+		enemyType := SplitOffId(enemy)
+
+		index = len(e.aggro)
+		e.aggroIndex[enemy] = index
+		e.aggro = append(e.aggro, enemyAggro{
+			name:      enemy,
+			enemyType: enemyType,
+		})
+
+		e.addTypeOption(enemyType)
+	}
+
+	aggro := &e.aggro[index]
+	aggro.targets = addTarget(aggro.targets, enemyTarget{
+		name:   target,
+		hits:   1,
+		damage: damage,
+	})
+
+	if len(aggro.switches) == 0 || aggro.switches[len(aggro.switches)-1].target != target {
+		aggro.switches = append(aggro.switches, targetSwitch{
+			at:     at,
+			target: target,
+		})
+	}
+}
+
+func (e *EnemiesCollector) hit(info abstract.StatisticsInformation, victim, attacker string, damage abstract.Vitals, at time.Time, fatal bool) {
+	e.now = at
+	e.combatWindow = time.Duration(info.Settings().SecondsUntilDPSReset) * time.Second
+
+	index, ok := e.alive[victim]
+	if !ok {
+		enemyType := SplitOffId(victim)
+		estimate, hasEstimate := info.EnemyHealth(enemyType)
+
+		index = len(e.instances)
+		e.alive[victim] = index
+		e.instances = append(e.instances, enemyInstance{
+			name:        victim,
+			enemyType:   enemyType,
+			firstHit:    at,
+			estimate:    estimate,
+			hasEstimate: hasEstimate,
+		})
+
+		e.updateType(enemyType, func(stats enemyTypeStats) enemyTypeStats {
+			stats.engaged++
+			return stats
+		})
+
+		e.addTypeOption(enemyType)
+	}
+
+	instance := &e.instances[index]
+	instance.lastHit = at
+	instance.hits++
+	instance.damage = instance.damage.Add(damage)
+	instance.contributors = utils.CreateUpdate(
+		instance.contributors,
+		func(contribution enemyContribution) bool {
+			return contribution.name == attacker
+		},
+		func() enemyContribution {
+			return enemyContribution{
+				name:   attacker,
+				damage: damage,
+			}
+		},
+		func(contribution enemyContribution) enemyContribution {
+			contribution.damage = contribution.damage.Add(damage)
+			return contribution
+		},
+	)
+	slices.SortFunc(instance.contributors, func(a, b enemyContribution) int {
+		return cmp.Compare(b.damage.Total(), a.damage.Total())
+	})
+
+	if fatal {
+		instance.killed = true
+		delete(e.alive, victim)
+		delete(e.aggroIndex, victim)
+
+		ttk := instance.ttk()
+		e.updateType(instance.enemyType, func(stats enemyTypeStats) enemyTypeStats {
+			stats.kills++
+			stats.ttks = append(stats.ttks, ttk)
+			return stats
+		})
+	}
+}
+
+func (e *EnemiesCollector) Tick(info abstract.StatisticsInformation, at time.Time) {
+	e.now = at
+	e.combatWindow = time.Duration(info.Settings().SecondsUntilDPSReset) * time.Second
+}
+
+func (e *EnemiesCollector) Collect(info abstract.StatisticsInformation, event *abstract.ChatEvent) error {
+	switch contents := event.Contents.(type) {
+	case *abstract.SkillUse:
+		if contents.Victim != "" && info.IsAlly(contents.Victim, "") && !info.IsAlly(contents.Subject, contents.Skill) {
+			var damage abstract.Vitals
+			if contents.Damage != nil {
+				damage = *contents.Damage
+			}
+
+			e.attack(contents.Subject, contents.Victim, damage, event.Time)
+			return nil
+		}
+
+		if contents.Victim == "" || info.IsAlly(contents.Victim, "") || !info.IsAlly(contents.Subject, contents.Skill) {
+			return nil
+		}
+
+		if contents.Damage == nil && !contents.Fatality {
+			return nil
+		}
+
+		var damage abstract.Vitals
+		if contents.Damage != nil {
+			damage = *contents.Damage
+		}
+
+		e.hit(info, contents.Victim, contents.Subject, damage, event.Time, contents.Fatality)
+	case *abstract.IndirectDamage:
+		if info.IsAlly(contents.Subject, "") {
+			return nil
+		}
+
+		e.hit(info, contents.Subject, info.CurrentUsername(), contents.Damage.Abs(), event.Time, false)
+	}
+
+	return nil
+}
+
+func (e *EnemiesCollector) TabName() string {
+	return "Enemies"
+}
+
+// killsOf Kills of an enemy type, type name isn't case-sensitive
+func (e *EnemiesCollector) killsOf(enemyType string) int {
+	for _, stats := range e.types {
+		if strings.EqualFold(stats.name, enemyType) {
+			return stats.kills
+		}
+	}
+
+	return 0
+}
+
+// Records Best DPS the character did in a single fight against every enemy type, only kills count
+func (e *EnemiesCollector) Records(character string) []abstract.PersonalRecord {
+	var records []abstract.PersonalRecord
+
+	for _, instance := range e.instances {
+		if !instance.killed {
+			continue
+		}
+
+		for _, contribution := range instance.contributors {
+			if contribution.name != character {
+				continue
+			}
+
+			record := abstract.PersonalRecord{
+				Kind:  abstract.RecordFightDPS,
+				Name:  instance.enemyType,
+				Value: float64(contribution.damage.Total()) / max(instance.ttk(), time.Second).Seconds(),
+				At:    instance.lastHit,
+			}
+
+			records = utils.CreateUpdate(
+				records,
+				func(other abstract.PersonalRecord) bool {
+					return other.Name == record.Name
+				},
+				func() abstract.PersonalRecord {
+					return record
+				},
+				func(other abstract.PersonalRecord) abstract.PersonalRecord {
+					if record.Value > other.Value {
+						return record
+					}
+					return other
+				},
+			)
+		}
+	}
+
+	return records
+}
+
+func (e *EnemiesCollector) typeRows() []enemyRow {
+	sorted := slices.Clone(e.types)
+	slices.SortFunc(sorted, func(a, b enemyTypeStats) int {
+		return cmp.Compare(b.kills, a.kills)
+	})
+
+	maxAverage := 0
+	for _, stats := range sorted {
+		maxAverage = max(maxAverage, int(stats.summary().average))
+	}
+
+	rows := make([]enemyRow, 0, len(sorted))
+	for _, stats := range sorted {
+		summary := stats.summary()
+		rows = append(rows, enemyRow{
+			sideText:     summary,
+			value:        int(summary.average),
+			max:          maxAverage,
+			amount:       stats.kills,
+			name:         stats.name,
+			amountFormat: "killed %v times",
+			caption:      fmt.Sprintf("Engaged %v times, %v never died", stats.engaged, stats.engaged-stats.kills),
+		})
+	}
+
+	return rows
+}
+
+func (e *EnemiesCollector) distributionRows(stats enemyTypeStats) []enemyRow {
+	summary := stats.summary()
+	if len(stats.ttks) == 0 {
+		return nil
+	}
+
+	step := (summary.max - summary.min) / ttkDistributionBuckets
+	if step <= 0 {
+		step = time.Second
+	}
+
+	var counts [ttkDistributionBuckets]int
+	for _, ttk := range stats.ttks {
+		bucket := min(int((ttk-summary.min)/step), ttkDistributionBuckets-1)
+		counts[bucket]++
+	}
+
+	maxCount := slices.Max(counts[:])
+
+	rows := make([]enemyRow, 0, ttkDistributionBuckets)
+	for i, count := range counts {
+		from := summary.min + step*time.Duration(i)
+		rows = append(rows, enemyRow{
+			sideText: enemyCounter(count),
+			value:    count,
+			max:      maxCount,
+			name:     fmt.Sprintf("%v - %v", ttkValue(from).StringCL(false), ttkValue(from+step).StringCL(false)),
+		})
+	}
+
+	return rows
+}
+
+func (e *EnemiesCollector) instanceRows(killed bool, long bool) []enemyRow {
+	maxValue := 0
+	for _, instance := range e.instances {
+		if instance.killed != killed || (e.currentType != "" && instance.enemyType != e.currentType) {
+			continue
+		}
+
+		if killed {
+			maxValue = max(maxValue, int(instance.ttk()))
+		} else {
+			maxValue = max(maxValue, instance.damage.Total())
+		}
+	}
+
+	var rows []enemyRow
+	for _, instance := range e.instances {
+		if instance.killed != killed || (e.currentType != "" && instance.enemyType != e.currentType) {
+			continue
+		}
+
+		row := enemyRow{
+			sideText:     instance.damage,
+			value:        instance.damage.Total(),
+			max:          maxValue,
+			amount:       instance.hits,
+			name:         instance.name,
+			amountFormat: "hit %v times",
+			caption:      fmt.Sprintf("Damaged by %v", instance.contributorsString(long)),
+		}
+
+		if killed {
+			row.value = int(instance.ttk())
+			row.name = fmt.Sprintf("%v in %v", instance.name, ttkValue(instance.ttk()).StringCL(long))
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+func (e *EnemiesCollector) combatRows(long bool) []enemyRow {
+	var rows []enemyRow
+	for _, index := range e.alive {
+		instance := e.instances[index]
+		if e.now.Sub(instance.lastHit) > e.combatWindow {
+			continue
+		}
+
+		if e.currentType != "" && instance.enemyType != e.currentType {
+			continue
+		}
+
+		remaining := instance.remaining()
+
+		row := enemyRow{
+			sideText:     remaining,
+			value:        remaining.remaining,
+			max:          int(instance.estimate.Mean),
+			amount:       instance.hits,
+			name:         instance.name,
+			amountFormat: "hit %v times",
+			caption:      "Never killed before, health is unknown",
+		}
+
+		if instance.hasEstimate {
+			row.caption = fmt.Sprintf("Estimated %v", instance.estimate)
+		}
+
+		rows = append(rows, row)
+	}
+
+	slices.SortFunc(rows, func(a, b enemyRow) int {
+		return cmp.Compare(a.name, b.name)
+	})
+
+	return rows
+}
+
+func targetRows(targets []enemyTarget) []enemyRow {
+	sorted := slices.Clone(targets)
+	slices.SortFunc(sorted, func(a, b enemyTarget) int {
+		return cmp.Compare(b.damage.Total(), a.damage.Total())
+	})
+
+	total := 0
+	for _, target := range sorted {
+		total += target.damage.Total()
+	}
+
+	rows := make([]enemyRow, 0, len(sorted))
+	for _, target := range sorted {
+		share := 0.0
+		if total > 0 {
+			share = float64(target.damage.Total()) / float64(total)
+		}
+
+		rows = append(rows, enemyRow{
+			sideText:     targetShare{damage: target.damage, share: share},
+			value:        target.damage.Total(),
+			max:          total,
+			amount:       target.hits,
+			name:         target.name,
+			amountFormat: "hit %v times",
+		})
+	}
+
+	return rows
+}
+
+func (e *EnemiesCollector) targetSections() ([]string, [][]enemyRow) {
+	var headings []string
+	var sections [][]enemyRow
+
+	if e.currentType != "" {
+		for _, aggro := range e.aggro {
+			if aggro.enemyType != e.currentType {
+				continue
+			}
+
+			headings = append(headings, fmt.Sprintf("%v switched targets %v times", aggro.name, max(0, len(aggro.switches)-1)))
+			sections = append(sections, append(targetRows(aggro.targets), enemyRow{
+				caption: fmt.Sprintf("Timeline: %v", aggro.timeline()),
+			}))
+		}
+
+		return headings, sections
+	}
+
+	type typeAggro struct {
+		name     string
+		enemies  int
+		switches int
+		targets  []enemyTarget
+	}
+
+	var types []typeAggro
+	for _, aggro := range e.aggro {
+		types = utils.CreateUpdate(
+			types,
+			func(stats typeAggro) bool {
+				return stats.name == aggro.enemyType
+			},
+			func() typeAggro {
+				return typeAggro{
+					name:     aggro.enemyType,
+					enemies:  1,
+					switches: max(0, len(aggro.switches)-1),
+					targets:  slices.Clone(aggro.targets),
+				}
+			},
+			func(stats typeAggro) typeAggro {
+				stats.enemies++
+				stats.switches += max(0, len(aggro.switches)-1)
+				for _, target := range aggro.targets {
+					stats.targets = addTarget(stats.targets, target)
+				}
+				return stats
+			},
+		)
+	}
+
+	for _, stats := range types {
+		headings = append(headings, fmt.Sprintf(
+			"%v: %v enemies switched targets %v times",
+			stats.name, stats.enemies, stats.switches,
+		))
+		sections = append(sections, targetRows(stats.targets))
+	}
+
+	return headings, sections
+}
+
+// rows Returns headings and the bars that go under them
+func (e *EnemiesCollector) rows(long bool) ([]string, [][]enemyRow) {
+	if e.currentView == ViewEnemyTargets {
+		return e.targetSections()
+	}
+
+	if e.currentView == ViewEnemiesInCombat {
+		return []string{"Enemies currently in combat"}, [][]enemyRow{e.combatRows(long)}
+	}
+
+	if e.currentView == ViewUnkilledEnemies {
+		return []string{"Enemies that were engaged but never killed"}, [][]enemyRow{e.instanceRows(false, long)}
+	}
+
+	if e.currentType == "" {
+		return []string{"Average time to kill"}, [][]enemyRow{e.typeRows()}
+	}
+
+	for _, stats := range e.types {
+		if stats.name != e.currentType {
+			continue
+		}
+
+		return []string{
+			"Time to kill distribution",
+			"Killed enemies",
+		}, [][]enemyRow{
+			e.distributionRows(stats),
+			e.instanceRows(true, long),
+		}
+	}
+
+	return nil, nil
+}
+
+func (e *EnemiesCollector) UI(state abstract.LayeredState) (layout.Widget, []layout.Widget) {
+	if e.viewDropdown.Changed() {
+		e.currentView = e.viewDropdown.Value.(enemyViewChoice)
+	}
+
+	if e.typeDropdown.Changed() {
+		e.currentType = string(e.typeDropdown.Value.(subjectChoice))
+	}
+
+	topWidget := topBarSurface(func(gtx layout.Context) layout.Dimensions {
+		if e.longFormatBool.Update(gtx) {
+			gtx.Source.Execute(op.InvalidateCmd{})
+		}
+
+		return components.HorizontalWrap{
+			Alignment:   layout.Middle,
+			Spacing:     utils.CommonSpacing * 2,
+			LineSpacing: utils.CommonSpacing,
+		}.Layout(
+			gtx,
+			defaultDropdownStyle(state, e.viewDropdown).Layout,
+			defaultDropdownStyle(state, e.typeDropdown).Layout,
+			defaultCheckboxStyle(state, e.longFormatBool, "Use long numbers").Layout,
+		)
+	})
+
+	label := func(text string, heading bool) layout.Widget {
+		return func(gtx layout.Context) layout.Dimensions {
+			return layout.UniformInset(utils.CommonSpacing).Layout(
+				gtx,
+				func(gtx layout.Context) layout.Dimensions {
+					style := defaultLabelStyle(state, text)
+					if heading {
+						style.TextSize = 14
+					}
+					return style.Layout(gtx)
+				},
+			)
+		}
+	}
+
+	var widgets []layout.Widget
+
+	long := e.longFormatBool.Value
+	headings, sections := e.rows(long)
+	for i, heading := range headings {
+		widgets = append(widgets, label(heading, true))
+
+		for _, row := range sections[i] {
+			if row.sideText != nil {
+				widgets = append(widgets, drawUniversalBar(
+					state, row.sideText,
+					row.value, row.max, row.amount,
+					row.name, row.amountFormat,
+					40, long,
+				))
+			}
+
+			if row.caption != "" {
+				widgets = append(widgets, label(row.caption, false))
+			}
+		}
+	}
+
+	return topWidget, widgets
+}
+
+func (e *EnemiesCollector) Export(state abstract.LayeredState) image.Image {
+	styledFonts := drawing.StyleFontPack(state.FontPack(), state.Theme().Fg)
+
+	var items []drawing.FlexChild
+
+	long := e.longFormatBool.Value
+	headings, sections := e.rows(long)
+	for i, heading := range headings {
+		items = append(items, drawing.Rigid(drawing.UniformInset(drawing.CommonSpacing).Layout(
+			styledFonts.Body.Layout(heading),
+		)))
+
+		for _, row := range sections[i] {
+			if row.sideText != nil {
+				items = append(
+					items,
+					drawing.FlexVSpacer(drawing.CommonSpacing),
+					drawing.Rigid(exportUniversalBar(
+						styledFonts, row.sideText,
+						row.value, row.max, row.amount,
+						row.name, row.amountFormat,
+						long,
+					)),
+				)
+			}
+
+			if row.caption != "" {
+				items = append(items, drawing.Rigid(drawing.UniformInset(drawing.CommonSpacing).Layout(
+					styledFonts.Smaller.Layout(row.caption),
+				)))
+			}
+		}
+	}
+
+	body := drawing.Flex{
+		Axis:    layout.Vertical,
+		ExpandW: true,
+	}.Layout(
+		items...,
+	)
+
+	base := layoutTitle(
+		styledFonts,
+		e.TabName(),
+		drawing.HorizontalWrap{
+			Alignment:   layout.Middle,
+			Spacing:     drawing.CommonSpacing * 3,
+			LineSpacing: drawing.CommonSpacing,
+		}.Layout(
+			styledFonts.Smaller.Layout(fmt.Sprintf("View: %v", e.currentView)),
+			func(ltx drawing.Context) drawing.Result {
+				if e.currentType != "" {
+					return styledFonts.Smaller.Layout(fmt.Sprintf("Enemy: %v", e.currentType))(ltx)
+				}
+
+				return styledFonts.Smaller.Layout("Enemy: All")(ltx)
+			},
+		),
+		drawing.RoundedSurface(
+			utils.SecondBG,
+			body,
+		),
+	)
+
+	return drawing.ExportImage(state.Theme(), base, drawing.F64(800, 10000))
+}
+
+func (e *EnemiesCollector) TextTable(state abstract.LayeredState) abstract.TextTable {
+	long := e.longFormatBool.Value
+
+	table := abstract.TextTable{
+		Title: textTitle(e.TabName()),
+		Parameters: []string{
+			fmt.Sprintf("View: %v", e.currentView),
+			fmt.Sprintf("Enemy: %v", subjectChoice(e.currentType)),
+		},
+		Columns: []string{"Name", "Value", "Details"},
+	}
+
+	headings, sections := e.rows(long)
+	for i, heading := range headings {
+		if len(headings) > 1 {
+			table.Rows = append(table.Rows, []string{heading})
+		}
+
+		for _, row := range sections[i] {
+			if row.sideText == nil {
+				continue
+			}
+
+			details := ""
+			if row.amountFormat != "" {
+				details = fmt.Sprintf(row.amountFormat, row.amount)
+			}
+
+			table.Rows = append(table.Rows, []string{row.name, row.sideText.StringCL(long), details})
+		}
+	}
+
+	return table
+}
+
+func (e *EnemiesCollector) ChatLine(state abstract.LayeredState) string {
+	total := 0
+	var top enemyTypeStats
+	for _, stats := range e.types {
+		total += stats.kills
+		if stats.kills > top.kills {
+			top = stats
+		}
+	}
+
+	line := fmt.Sprintf("Enemies killed: %v", utils.FormatNumber(total))
+	if top.kills == 0 {
+		return line
+	}
+
+	return chatLine(line, chatTop(top.name, top.kills, total))
+}
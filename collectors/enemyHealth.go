@@ -0,0 +1,57 @@
+package collectors
+
+import (
+	"PGCombatTracker/abstract"
+)
+
+// enemyHealthTracker Sums up health and armor damage of enemies until they die, to learn how much health they have
+type enemyHealthTracker struct {
+	pools map[string]int
+	kills []abstract.EnemyKill
+}
+
+func newEnemyHealthTracker() *enemyHealthTracker {
+	return &enemyHealthTracker{
+		pools: make(map[string]int),
+	}
+}
+
+func (t *enemyHealthTracker) Track(info abstract.StatisticsInformation, event *abstract.ChatEvent) {
+	switch contents := event.Contents.(type) {
+	case *abstract.SkillUse:
+		if contents.Victim == "" || info.IsAlly(contents.Victim, "") || !info.IsAlly(contents.Subject, contents.Skill) {
+			return
+		}
+
+		if contents.Damage != nil {
+			t.pools[contents.Victim] += contents.Damage.Health + contents.Damage.Armor
+		}
+
+		if contents.Fatality {
+			t.kills = append(t.kills, abstract.EnemyKill{
+				EnemyType: SplitOffId(contents.Victim),
+				Instance:  contents.Victim,
+				Pool:      t.pools[contents.Victim],
+				At:        event.Time,
+			})
+			delete(t.pools, contents.Victim)
+		}
+	case *abstract.IndirectDamage:
+		if info.IsAlly(contents.Subject, "") {
+			return
+		}
+
+		damage := contents.Damage.Abs()
+		t.pools[contents.Subject] += damage.Health + damage.Armor
+	}
+}
+
+// Flush Hands over collected kills, so they don't get saved one by one
+func (t *enemyHealthTracker) Flush(bearer abstract.EnemyHealthBearer, path string) {
+	if len(t.kills) == 0 {
+		return
+	}
+
+	bearer.LearnEnemyHealth(path, t.kills)
+	t.kills = nil
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/fogleman/gg"
 	"image/color"
 	"math"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -58,6 +60,41 @@ func percentBar(progress float64, color color.NRGBA) drawing.Widget {
 	}
 }
 
+func segmentedPercentBar(segments ...components.BarSegment) drawing.Widget {
+	return func(ltx drawing.Context) drawing.Result {
+		size := ltx.Min
+
+		var total float64
+		for _, segment := range segments {
+			total += segment.Progress
+		}
+		fullWidth := size.X * max(min(1, total), 0)
+
+		return drawing.Result{
+			Size: size,
+			Draw: func(gg *gg.Context) {
+				gg.Push()
+
+				gg.DrawRoundedRectangle(0, 0, fullWidth, size.Y, min(drawing.CommonSpacing*2, fullWidth))
+				gg.Clip()
+
+				x := 0.0
+				for _, segment := range segments {
+					width := size.X * max(segment.Progress, 0)
+
+					gg.SetColor(segment.Color)
+					gg.DrawRectangle(x, 0, width, size.Y)
+					gg.Fill()
+
+					x += width
+				}
+
+				gg.Pop()
+			},
+		}
+	}
+}
+
 func exportUniversalStatsTextItself(
 	styledFonts *drawing.StyledFontPack,
 	sideText utils.LongFormatable, amount int,
@@ -160,3 +197,66 @@ func exportTimeFrame(styledFonts *drawing.StyledFontPack, timeFrame components.T
 		))),
 	))
 }
+
+func timelineLane(color color.NRGBA, frame components.TimeFrame, spans ...components.TimeFrame) drawing.Widget {
+	return func(ltx drawing.Context) drawing.Result {
+		size := ltx.Min
+
+		return drawing.Result{
+			Size: size,
+			Draw: func(gg *gg.Context) {
+				gg.SetColor(color)
+
+				for _, span := range spans {
+					if span.To.Before(frame.From) || span.From.After(frame.To) {
+						continue
+					}
+
+					from := size.X * frame.ProportionOfTarget(span.From)
+					to := size.X * frame.ProportionOfTarget(span.To)
+
+					gg.DrawRectangle(min(from, size.X-2), 0, max(to-from, 2), size.Y)
+				}
+
+				gg.Fill()
+			},
+		}
+	}
+}
+
+func textTitle(tabName string) string {
+	return fmt.Sprintf("%v Tab", tabName)
+}
+
+// textTimeFrame Same header that exportTimeFrame draws on images
+func textTimeFrame(timeFrame components.TimeFrame) string {
+	return fmt.Sprintf(
+		"Data from %v to %v",
+		timeFrame.From.Format(time.DateTime),
+		timeFrame.To.Format(time.DateTime),
+	)
+}
+
+func textShare(value, total int) string {
+	if total == 0 {
+		return "0%"
+	}
+
+	return fmt.Sprintf("%.1f%%", float64(value)/float64(total)*100)
+}
+
+// chatTop Biggest entry of a chat line, like "Top: Skill (40%)"
+func chatTop(name string, value, total int) string {
+	if total == 0 {
+		return fmt.Sprintf("Top: %v", name)
+	}
+
+	return fmt.Sprintf("Top: %v (%.0f%%)", name, float64(value)/float64(total)*100)
+}
+
+// chatLine Joins the parts of a chat line, leaving out empty ones
+func chatLine(parts ...string) string {
+	return strings.Join(slices.DeleteFunc(parts, func(part string) bool {
+		return part == ""
+	}), " | ")
+}
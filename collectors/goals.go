@@ -0,0 +1,459 @@
+package collectors
+
+import (
+	"PGCombatTracker/abstract"
+	"PGCombatTracker/ui/components"
+	"PGCombatTracker/utils"
+	"PGCombatTracker/utils/drawing"
+	"fmt"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/widget"
+	"image"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type goalKind uint8
+
+const (
+	goalXP goalKind = iota
+	goalCoins
+	goalKills
+	goalMaxDTPS
+)
+
+type goal struct {
+	rule string
+	kind goalKind
+	// target Skill or enemy type, empty for coins and DTPS
+	target string
+	amount int
+	// area Progress only counts while in the area, empty means everywhere
+	area string
+}
+
+func (g goal) String() string {
+	var text string
+
+	switch g.kind {
+	case goalXP:
+		text = fmt.Sprintf("Reach %v XP in %v", utils.FormatNumber(g.amount), g.target)
+	case goalCoins:
+		text = fmt.Sprintf("Farm %v coins", utils.FormatNumber(g.amount))
+	case goalKills:
+		text = fmt.Sprintf("Kill %d %v", g.amount, g.target)
+	case goalMaxDTPS:
+		text = fmt.Sprintf("Keep DTPS under %d", g.amount)
+	}
+
+	if g.area != "" {
+		text = fmt.Sprintf("%v in %v", text, g.area)
+	}
+
+	return text
+}
+
+// parseGoal Goals look like "XP:Skill Name=10000", "Coins=50000", "Kills:Enemy Type=30" or "DTPS<200",
+// "@Area Name" at the end only counts progress in that area
+func parseGoal(rule string) (goal, bool) {
+	body, area, _ := strings.Cut(rule, "@")
+	parsed := goal{
+		rule: rule,
+		area: strings.TrimSpace(area),
+	}
+
+	var amount string
+	valid := true
+
+	if kind, limit, found := strings.Cut(body, "<"); found {
+		parsed.kind = goalMaxDTPS
+		amount = limit
+		valid = strings.EqualFold(strings.TrimSpace(kind), "DTPS")
+	} else {
+		left, right, found := strings.Cut(body, "=")
+		kind, target, _ := strings.Cut(left, ":")
+		parsed.target = strings.TrimSpace(target)
+		amount = right
+		valid = found
+
+		switch strings.ToLower(strings.TrimSpace(kind)) {
+		case "xp":
+			parsed.kind = goalXP
+			valid = valid && parsed.target != ""
+		case "coins":
+			parsed.kind = goalCoins
+		case "kills":
+			parsed.kind = goalKills
+			valid = valid && parsed.target != ""
+		default:
+			valid = false
+		}
+	}
+
+	if !valid {
+		log.Printf("Ignoring goal '%v', expected 'XP:Skill=amount', 'Coins=amount', 'Kills:Enemy=amount' or 'DTPS<limit'\n", rule)
+		return goal{}, false
+	}
+
+	var err error
+	parsed.amount, err = strconv.Atoi(strings.TrimSpace(amount))
+	if err == nil && parsed.amount <= 0 {
+		err = fmt.Errorf("amount has to be positive")
+	}
+
+	if err != nil {
+		log.Printf("Ignoring goal '%v': %v\n", rule, err)
+		return goal{}, false
+	}
+
+	return parsed, true
+}
+
+type goalProgress struct {
+	goal
+	// value Progress so far, or the current DTPS
+	value int
+	// last Value of the source when the goal was last checked, so only changes made in the area count
+	last        int
+	initialized bool
+	reached     bool
+	breached    bool
+	breaches    int
+}
+
+func (g goalProgress) StringCL(long bool) string {
+	format := func(value int) string {
+		if long {
+			return strconv.Itoa(value)
+		}
+		return utils.FormatNumber(value)
+	}
+
+	if g.kind == goalMaxDTPS {
+		status := "under the limit"
+		if g.breached {
+			status = "over the limit"
+		}
+		return fmt.Sprintf("%v DTPS, %v, broken %d times", format(g.value), status, g.breaches)
+	}
+
+	if g.reached {
+		return fmt.Sprintf("%v / %v, reached", format(g.value), format(g.amount))
+	}
+
+	return fmt.Sprintf(
+		"%v / %v (%.0f%%)",
+		format(g.value), format(g.amount),
+		float64(g.value)/float64(g.amount)*100,
+	)
+}
+
+// NewGoalsCollector Only one set of collectors should raise alerts, or every banner would show up once per set
+func NewGoalsCollector(settings *abstract.Settings, alerts bool, leveling *LevelingCollector, misc *MiscCollector, enemies *EnemiesCollector, damageTaken *DamageTakenCollector) *GoalsCollector {
+	goals := &GoalsCollector{
+		leveling:       leveling,
+		misc:           misc,
+		enemies:        enemies,
+		damageTaken:    damageTaken,
+		alerts:         alerts,
+		longFormatBool: &widget.Bool{},
+	}
+	goals.dtps = NewDPSCalculator(func(point components.TimePoint) {
+		goals.currentDTPS = point.Value
+	}, settings)
+
+	return goals
+}
+
+// GoalsCollector Progress of goals from settings, fed by the other collectors of the same set,
+// so it has to come after them
+type GoalsCollector struct {
+	leveling    *LevelingCollector
+	misc        *MiscCollector
+	enemies     *EnemiesCollector
+	damageTaken *DamageTakenCollector
+
+	rules []string
+	goals []goalProgress
+	// subject Character the sources were last read for, progress doesn't carry over between characters
+	subject     string
+	dtps        *DPSCalculator
+	currentDTPS int
+	lastTaken   int
+	alerts      bool
+
+	longFormatBool *widget.Bool
+}
+
+func (g *GoalsCollector) alert(info abstract.StatisticsInformation, text string) {
+	if g.alerts {
+		info.Alert(text)
+	}
+}
+
+func (g *GoalsCollector) Reset(info abstract.StatisticsInformation) {
+	g.rules = nil
+	g.goals = nil
+	g.subject = ""
+	g.dtps = NewDPSCalculator(func(point components.TimePoint) {
+		g.currentDTPS = point.Value
+	}, info.Settings())
+	g.currentDTPS = 0
+	g.lastTaken = 0
+}
+
+// parseRules Parses goals again if they were changed in settings, progress of goals that stayed is kept
+func (g *GoalsCollector) parseRules(rules []string) {
+	if slices.Equal(rules, g.rules) {
+		return
+	}
+
+	g.rules = slices.Clone(rules)
+
+	var goals []goalProgress
+	for _, rule := range rules {
+		parsed, ok := parseGoal(rule)
+		if !ok {
+			continue
+		}
+
+		index := slices.IndexFunc(g.goals, func(progress goalProgress) bool {
+			return progress.rule == rule
+		})
+
+		if index >= 0 {
+			goals = append(goals, g.goals[index])
+		} else {
+			goals = append(goals, goalProgress{goal: parsed})
+		}
+	}
+
+	g.goals = goals
+}
+
+func (g *GoalsCollector) source(goal goal, subject string) int {
+	switch goal.kind {
+	case goalXP:
+		return g.leveling.xpOf(subject, goal.target)
+	case goalCoins:
+		return g.misc.coinsOf(subject)
+	case goalKills:
+		return g.enemies.killsOf(goal.target)
+	}
+
+	return 0
+}
+
+func (g *GoalsCollector) update(info abstract.StatisticsInformation, at time.Time) {
+	g.parseRules(info.Settings().Goals)
+
+	subject := info.CurrentUsername()
+	switched := subject != g.subject
+	g.subject = subject
+
+	taken := g.damageTaken.takenBy(subject)
+	if !switched && taken > g.lastTaken {
+		g.dtps.Add(at, taken-g.lastTaken)
+	}
+	g.lastTaken = taken
+
+	for i := range g.goals {
+		progress := &g.goals[i]
+		inArea := progress.area == "" || strings.EqualFold(progress.area, info.CurrentArea())
+
+		if progress.kind == goalMaxDTPS {
+			if !inArea {
+				continue
+			}
+
+			progress.value = g.currentDTPS
+			over := progress.value > progress.amount
+			if over && !progress.breached {
+				progress.breaches++
+				g.alert(info, fmt.Sprintf("DTPS is at %d, goal '%v' was broken", progress.value, progress.goal))
+			}
+			progress.breached = over
+			continue
+		}
+
+		source := g.source(progress.goal, subject)
+
+		// Goals without an area count everything from the start, even if they were added later
+		if !progress.initialized {
+			progress.initialized = true
+			if progress.area == "" {
+				progress.value = source
+			}
+		} else if !switched && inArea {
+			progress.value += source - progress.last
+		}
+		progress.last = source
+
+		if !progress.reached && progress.value >= progress.amount {
+			progress.reached = true
+			g.alert(info, fmt.Sprintf("Goal reached: %v", progress.goal))
+		}
+	}
+}
+
+type GoalSnapshot struct {
+	Goal     string
+	Value    int
+	Amount   int
+	Reached  bool
+	Breached bool
+	Breaches int
+}
+
+func (g *GoalsCollector) Snapshot() any {
+	goals := make([]GoalSnapshot, len(g.goals))
+	for i, progress := range g.goals {
+		goals[i] = GoalSnapshot{
+			Goal:     progress.goal.String(),
+			Value:    progress.value,
+			Amount:   progress.amount,
+			Reached:  progress.reached,
+			Breached: progress.breached,
+			Breaches: progress.breaches,
+		}
+	}
+
+	return goals
+}
+
+func (g *GoalsCollector) Tick(info abstract.StatisticsInformation, at time.Time) {
+	g.dtps.Tick(at)
+	g.update(info, at)
+}
+
+func (g *GoalsCollector) Collect(info abstract.StatisticsInformation, event *abstract.ChatEvent) error {
+	g.update(info, event.Time)
+	return nil
+}
+
+func (g *GoalsCollector) TabName() string {
+	return "Goals"
+}
+
+// barValue Bars are full once the goal is reached, DTPS bar is full at the limit
+func (g goalProgress) barValue() int {
+	return min(g.value, g.amount)
+}
+
+const noGoalsText = "No goals yet, add them in settings as 'XP:Skill=10000', 'Coins=50000', 'Kills:Enemy=30' or 'DTPS<200', with '@Area' at the end to only count one area"
+
+func (g *GoalsCollector) UI(state abstract.LayeredState) (layout.Widget, []layout.Widget) {
+	long := g.longFormatBool.Value
+
+	topWidget := topBarSurface(func(gtx layout.Context) layout.Dimensions {
+		if g.longFormatBool.Update(gtx) {
+			gtx.Source.Execute(op.InvalidateCmd{})
+		}
+
+		return defaultCheckboxStyle(state, g.longFormatBool, "Use long numbers").Layout(gtx)
+	})
+
+	if len(g.goals) == 0 {
+		return topWidget, []layout.Widget{
+			func(gtx layout.Context) layout.Dimensions {
+				return layout.UniformInset(utils.CommonSpacing).Layout(
+					gtx,
+					defaultLabelStyle(state, noGoalsText).Layout,
+				)
+			},
+		}
+	}
+
+	widgets := make([]layout.Widget, len(g.goals))
+	for i, progress := range g.goals {
+		widgets[i] = drawUniversalBar(
+			state, progress,
+			progress.barValue(), progress.amount, 0,
+			progress.goal.String(), "",
+			30, long,
+		)
+	}
+
+	return topWidget, widgets
+}
+
+func (g *GoalsCollector) Export(state abstract.LayeredState) image.Image {
+	long := g.longFormatBool.Value
+	styledFonts := drawing.StyleFontPack(state.FontPack(), state.Theme().Fg)
+
+	var items []drawing.FlexChild
+	if len(g.goals) == 0 {
+		items = append(items, drawing.Rigid(styledFonts.Body.Layout(noGoalsText)))
+	}
+
+	for _, progress := range g.goals {
+		items = append(
+			items,
+			drawing.Rigid(exportUniversalBar(
+				styledFonts, progress,
+				progress.barValue(), progress.amount, 0,
+				progress.goal.String(), "",
+				long,
+			)),
+			drawing.FlexVSpacer(drawing.CommonSpacing),
+		)
+	}
+
+	body := drawing.Flex{
+		Axis:    layout.Vertical,
+		ExpandW: true,
+	}.Layout(
+		items...,
+	)
+
+	base := layoutTitle(
+		styledFonts,
+		g.TabName(),
+		styledFonts.Smaller.Layout("Progress of goals for the current character"),
+		drawing.RoundedSurface(
+			utils.SecondBG,
+			body,
+		),
+	)
+
+	return drawing.ExportImage(state.Theme(), base, drawing.F64(800, 10000))
+}
+
+func (g *GoalsCollector) TextTable(state abstract.LayeredState) abstract.TextTable {
+	long := g.longFormatBool.Value
+
+	table := abstract.TextTable{
+		Title:      textTitle(g.TabName()),
+		Parameters: []string{"Progress of goals for the current character"},
+		Columns:    []string{"Goal", "Progress"},
+	}
+	if len(g.goals) == 0 {
+		table.Parameters = append(table.Parameters, noGoalsText)
+	}
+
+	for _, progress := range g.goals {
+		table.Rows = append(table.Rows, []string{progress.goal.String(), progress.StringCL(long)})
+	}
+
+	return table
+}
+
+func (g *GoalsCollector) ChatLine(state abstract.LayeredState) string {
+	goals, reached := 0, 0
+	for _, progress := range g.goals {
+		if progress.kind == goalMaxDTPS {
+			continue
+		}
+
+		goals++
+		if progress.reached {
+			reached++
+		}
+	}
+
+	return fmt.Sprintf("Goals reached: %d of %d", reached, goals)
+}
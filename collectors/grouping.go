@@ -0,0 +1,102 @@
+package collectors
+
+import (
+	"PGCombatTracker/abstract"
+	"PGCombatTracker/ui/components"
+	"fmt"
+	"log"
+	"slices"
+)
+
+type groupChoice uint8
+
+const (
+	GroupNone groupChoice = iota
+	GroupSkill
+	GroupCategory
+	GroupZone
+)
+
+func (g groupChoice) String() string {
+	switch g {
+	case GroupNone:
+		return "None"
+	case GroupSkill:
+		return "Skill"
+	case GroupCategory:
+		return "Category"
+	case GroupZone:
+		return "Zone"
+	}
+
+	return ""
+}
+
+func (g groupChoice) groupOf(categories *abstract.SkillCategories, name string) string {
+	switch g {
+	case GroupSkill:
+		return categories.SkillOf(SplitOffId(name))
+	case GroupCategory:
+		return categories.CategoryOf(SplitOffId(name))
+	}
+
+	return name
+}
+
+// zoneOf Name of the area the events are currently coming from, for grouping by zone
+func zoneOf(info abstract.StatisticsInformation) string {
+	if area := info.CurrentArea(); area != "" {
+		return area
+	}
+
+	return "Unknown Zone"
+}
+
+func newGroupDropdown(first groupChoice, other ...groupChoice) *components.Dropdown {
+	options := make([]fmt.Stringer, len(other))
+	for i, choice := range other {
+		options[i] = choice
+	}
+
+	dropdown, err := components.NewDropdown("Group by", first, options...)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	return dropdown
+}
+
+// groupItems Merges items that fall into the same group, merge receives zero value for the first item of a group
+func groupItems[T any](
+	categories *abstract.SkillCategories,
+	choice groupChoice,
+	items []T,
+	nameOf func(T) string,
+	merge func(group string, grouped T, item T) T,
+	compare func(a, b T) int,
+) []T {
+	if choice == GroupNone {
+		return items
+	}
+
+	var groups []string
+	var grouped []T
+
+	for _, item := range items {
+		group := choice.groupOf(categories, nameOf(item))
+
+		i := slices.Index(groups, group)
+		if i < 0 {
+			var zero T
+			groups = append(groups, group)
+			grouped = append(grouped, merge(group, zero, item))
+			continue
+		}
+
+		grouped[i] = merge(group, grouped[i], item)
+	}
+
+	slices.SortStableFunc(grouped, compare)
+
+	return grouped
+}
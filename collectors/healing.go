@@ -14,7 +14,7 @@ import (
 	"image"
 	"log"
 	"slices"
-	"strings"
+	"strconv"
 	"time"
 )
 
@@ -31,6 +31,23 @@ type healingWithMax struct {
 	total          abstract.Vitals
 	max            abstract.Vitals
 	maxRange       components.DataRange
+	vitalCharts    *vitalCharts
+}
+
+// healerRate Healing per second of a healer, worked out the same way as DPS
+type healerRate struct {
+	total      int
+	current    int
+	calculator *DPSCalculator
+}
+
+func newHealerRate(settings *abstract.Settings) *healerRate {
+	rate := &healerRate{}
+	rate.calculator = NewDPSCalculator(func(point components.TimePoint) {
+		rate.current = point.Value
+	}, settings)
+
+	return rate
 }
 
 type healingSubject int
@@ -39,6 +56,8 @@ const (
 	RecAllies healingSubject = iota
 	RecEnemies
 	RecAll
+	RecHealers
+	RecZones
 )
 
 func (h healingSubject) String() string {
@@ -49,6 +68,10 @@ func (h healingSubject) String() string {
 		return "Enemies"
 	case RecAll:
 		return "All"
+	case RecHealers:
+		return "Healing done by"
+	case RecZones:
+		return "Allies by zone"
 	}
 
 	return ""
@@ -57,15 +80,18 @@ func (h healingSubject) String() string {
 func freshHealingWithMax() healingWithMax {
 	return healingWithMax{
 		timeController: components.NewTimeControllerOrCrash(components.NewTimeBasedChart("Total")),
+		vitalCharts:    newVitalCharts(),
 	}
 }
 
-func NewHealingCollector() *HealingCollector {
+func NewHealingCollector(settings *abstract.Settings) *HealingCollector {
 	subjectDropdown, err := components.NewDropdown(
 		"Subject",
 		RecAllies,
 		RecEnemies,
 		RecAll,
+		RecHealers,
+		RecZones,
 	)
 	if err != nil {
 		log.Fatalln(err)
@@ -76,6 +102,7 @@ func NewHealingCollector() *HealingCollector {
 		DisplayBars,
 		DisplayPie,
 		DisplayGraphs,
+		DisplayVitals,
 	)
 	if err != nil {
 		log.Fatalln(err)
@@ -87,6 +114,11 @@ func NewHealingCollector() *HealingCollector {
 		enemyTypes:        freshHealingWithMax(),
 		allWithEnemies:    freshHealingWithMax(),
 		allWithEnemyTypes: freshHealingWithMax(),
+		healers:           freshHealingWithMax(),
+		zones:             freshHealingWithMax(),
+		healerConfidence:  make(map[string]float64),
+		healerRates:       make(map[string]*healerRate),
+		sources:           newHealingSources(settings),
 
 		subjectDropdown:    subjectDropdown,
 		displayDropdown:    displayDropdown,
@@ -101,7 +133,14 @@ type HealingCollector struct {
 	enemyTypes        healingWithMax
 	allWithEnemies    healingWithMax
 	allWithEnemyTypes healingWithMax
-	registeredPets    []string
+	healers           healingWithMax
+	// zones Recovery of allies grouped by the zone it happened in
+	zones healingWithMax
+	// healerConfidence Sum of confidence for every heal attributed to the healer
+	healerConfidence map[string]float64
+	// healerRates Keyed by the healer alone, unlike healers which are split by skill
+	healerRates map[string]*healerRate
+	sources     *healingSources
 
 	currentSubject     healingSubject
 	currentDisplay     displayChoice
@@ -117,36 +156,12 @@ func (h *HealingCollector) Reset(info abstract.StatisticsInformation) {
 	h.enemyTypes = freshHealingWithMax()
 	h.allWithEnemies = freshHealingWithMax()
 	h.allWithEnemyTypes = freshHealingWithMax()
-	h.registeredPets = nil
-}
-func (h *HealingCollector) lookForPet(skillUse *abstract.SkillUse) {
-	if strings.Contains(skillUse.Skill, "(Pet)") {
-		h.registeredPets = append(h.registeredPets, skillUse.Subject)
-	}
-}
-
-func (h *HealingCollector) isAlly(info abstract.StatisticsInformation, subject string) bool {
-	if subject == info.CurrentUsername() {
-		return true
-	}
-
-	for _, pet := range h.registeredPets {
-		if pet == subject {
-			return true
-		}
-	}
-
-	lowTrimmedSubject := strings.ToLower(strings.TrimSpace(SplitOffId(subject)))
-
-	for _, expectedName := range info.Settings().EntitiesThatCountAsPets {
-		if lowTrimmedSubject == strings.ToLower(strings.TrimSpace(expectedName)) {
-			return true
-		}
-	}
-
-	return false
+	h.healers = freshHealingWithMax()
+	h.zones = freshHealingWithMax()
+	h.healerConfidence = make(map[string]float64)
+	h.healerRates = make(map[string]*healerRate)
+	h.sources = newHealingSources(info.Settings())
 }
-
 func (h *HealingCollector) ingestRecovered(info abstract.StatisticsInformation, event *abstract.ChatEvent) {
 	recovered := event.Contents.(*abstract.Recovered)
 
@@ -201,15 +216,29 @@ func (h *HealingCollector) ingestRecovered(info abstract.StatisticsInformation,
 			Time:  event.Time,
 			Value: stat.total.Total(),
 		})
+		stat.vitalCharts.Add(event.Time, stat.total)
 		slices.SortFunc(stat.subjects, healSort)
 		stat.max = slices.MaxFunc(stat.subjects, healMax).recovered
 		stat.maxRange = stat.maxRange.Expand(stat.max.Total())
 	}
 
-	if h.isAlly(info, recovered.Subject) {
+	source := h.sources.Attribute(info, event)
+	processHealingWithMax(&h.healers, source.String())
+	h.healerConfidence[source.String()] += source.confidence
+
+	rate, ok := h.healerRates[source.healer]
+	if !ok {
+		rate = newHealerRate(info.Settings())
+		h.healerRates[source.healer] = rate
+	}
+	rate.total += recovered.Healed.Total()
+	rate.calculator.Add(event.Time, recovered.Healed.Total())
+
+	if info.IsAlly(recovered.Subject, "") {
 		processHealingWithMax(&h.allies, recovered.Subject)
 		processHealingWithMax(&h.allWithEnemies, recovered.Subject)
 		processHealingWithMax(&h.allWithEnemyTypes, recovered.Subject)
+		processHealingWithMax(&h.zones, zoneOf(info))
 	} else {
 		processHealingWithMax(&h.enemies, recovered.Subject)
 		processHealingWithMax(&h.allWithEnemies, recovered.Subject)
@@ -221,16 +250,38 @@ func (h *HealingCollector) ingestRecovered(info abstract.StatisticsInformation,
 }
 
 func (h *HealingCollector) Tick(info abstract.StatisticsInformation, at time.Time) {
-
+	for _, rate := range h.healerRates {
+		rate.calculator.Tick(at)
+	}
 }
 
-func (h *HealingCollector) Collect(info abstract.StatisticsInformation, event *abstract.ChatEvent) error {
-	if skillUse, ok := event.Contents.(*abstract.SkillUse); ok && skillUse.Damage != nil {
-		h.lookForPet(skillUse)
+func (h *HealingCollector) Metrics(character string) []abstract.Metric {
+	rate, ok := h.healerRates[character]
+	if !ok {
+		return nil
 	}
 
+	return []abstract.Metric{
+		{
+			Name:  "pgct_healing_done",
+			Help:  "Health, armor and power the character restored",
+			Type:  abstract.MetricCounter,
+			Value: float64(rate.total),
+		},
+		{
+			Name:  "pgct_hps",
+			Help:  "Current healing per second, drops to zero when the character stops healing",
+			Type:  abstract.MetricGauge,
+			Value: float64(rate.current),
+		},
+	}
+}
+
+func (h *HealingCollector) Collect(info abstract.StatisticsInformation, event *abstract.ChatEvent) error {
 	if _, ok := event.Contents.(*abstract.Recovered); ok {
 		h.ingestRecovered(info, event)
+	} else {
+		h.sources.Observe(info, event)
 	}
 
 	return nil
@@ -258,6 +309,11 @@ func (h *HealingCollector) drawBar(state abstract.LayeredState, healed healing,
 	)
 }
 
+// confidenceText Average confidence of the heals that were attributed to the healer
+func (h *HealingCollector) confidenceText(healed healing) string {
+	return fmt.Sprintf("Confidence: %.0f%%", h.healerConfidence[healed.name]/float64(healed.amount)*100)
+}
+
 func (h *HealingCollector) UI(state abstract.LayeredState) (layout.Widget, []layout.Widget) {
 	if h.subjectDropdown.Changed() {
 		h.currentSubject = h.subjectDropdown.Value.(healingSubject)
@@ -283,6 +339,10 @@ func (h *HealingCollector) UI(state abstract.LayeredState) (layout.Widget, []lay
 		} else {
 			stats = h.allWithEnemies
 		}
+	case RecHealers:
+		stats = h.healers
+	case RecZones:
+		stats = h.zones
 	}
 
 	topWidget := topBarSurface(func(gtx layout.Context) layout.Dimensions {
@@ -312,7 +372,7 @@ func (h *HealingCollector) UI(state abstract.LayeredState) (layout.Widget, []lay
 				)
 			}),
 			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-				if h.currentDisplay == DisplayGraphs {
+				if h.currentDisplay == DisplayGraphs || h.currentDisplay == DisplayVitals {
 					return layout.Flex{
 						Axis: layout.Vertical,
 					}.Layout(
@@ -335,6 +395,16 @@ func (h *HealingCollector) UI(state abstract.LayeredState) (layout.Widget, []lay
 
 		for _, healed := range stats.subjects {
 			widgets = append(widgets, h.drawBar(state, healed, maxHealed, 40))
+
+			if h.currentSubject == RecHealers {
+				text := h.confidenceText(healed)
+				widgets = append(widgets, func(gtx layout.Context) layout.Dimensions {
+					return layout.Inset{
+						Left:   utils.CommonSpacing * 2,
+						Bottom: utils.CommonSpacing,
+					}.Layout(gtx, defaultLabelStyle(state, text).Layout)
+				})
+			}
 		}
 	case DisplayPie:
 		widgets = append(widgets, func(gtx layout.Context) layout.Dimensions {
@@ -371,6 +441,21 @@ func (h *HealingCollector) UI(state abstract.LayeredState) (layout.Widget, []lay
 
 			widgets = append(widgets, h.drawWidget(state, subject, chartStyle.Layout, 100))
 		}
+	case DisplayVitals:
+		widgets = append(
+			widgets,
+			drawVitalsSplit(state, stats.vitalCharts, stats.timeController, stats.total, h.longFormatBool.Value)...,
+		)
+
+		maxHealed := stats.max.Total()
+		for _, healed := range stats.subjects {
+			widgets = append(widgets, drawVitalsBar(
+				state, healed.recovered,
+				maxHealed, healed.amount,
+				healed.name, "recovered %v times",
+				40, h.longFormatBool.Value,
+			))
+		}
 	}
 
 	return topWidget, widgets
@@ -411,6 +496,10 @@ func (h *HealingCollector) Export(state abstract.LayeredState) image.Image {
 		} else {
 			stats = h.allWithEnemies
 		}
+	case RecHealers:
+		stats = h.healers
+	case RecZones:
+		stats = h.zones
 	}
 
 	styledFonts := drawing.StyleFontPack(state.FontPack(), state.Theme().Fg)
@@ -429,6 +518,12 @@ func (h *HealingCollector) Export(state abstract.LayeredState) image.Image {
 			}
 
 			items = append(items, drawing.Rigid(h.exportBar(styledFonts, healed, maxRecovered)))
+
+			if h.currentSubject == RecHealers {
+				items = append(items, drawing.Rigid(drawing.UniformInset(drawing.CommonSpacing).Layout(
+					styledFonts.Smaller.Layout(h.confidenceText(healed)),
+				)))
+			}
 		}
 
 		body = drawing.Flex{
@@ -478,6 +573,29 @@ func (h *HealingCollector) Export(state abstract.LayeredState) image.Image {
 			items = append(items, drawing.Rigid(h.exportWidget(styledFonts, healed, style.Layout())))
 		}
 
+		body = drawing.Flex{
+			ExpandW: true,
+			Axis:    layout.Vertical,
+		}.Layout(
+			items...,
+		)
+	case DisplayVitals:
+		items := exportVitalsSplit(styledFonts, stats.vitalCharts, stats.timeController, stats.total, h.longFormatBool.Value)
+
+		maxHealed := stats.max.Total()
+		for _, healed := range stats.subjects {
+			items = append(
+				items,
+				drawing.FlexVSpacer(drawing.CommonSpacing),
+				drawing.Rigid(exportVitalsBar(
+					styledFonts, healed.recovered,
+					maxHealed, healed.amount,
+					healed.name, "recovered %v times",
+					h.longFormatBool.Value,
+				)),
+			)
+		}
+
 		body = drawing.Flex{
 			ExpandW: true,
 			Axis:    layout.Vertical,
@@ -512,3 +630,65 @@ func (h *HealingCollector) Export(state abstract.LayeredState) image.Image {
 
 	return drawing.ExportImage(state.Theme(), base, drawing.F64(800, 10000))
 }
+
+// textStats Stats the way the tab currently shows them
+func (h *HealingCollector) textStats() healingWithMax {
+	switch h.currentSubject {
+	case RecAllies:
+		return h.allies
+	case RecEnemies:
+		if h.enemyTypesCheckbox.Value {
+			return h.enemyTypes
+		}
+		return h.enemies
+	case RecAll:
+		if h.enemyTypesCheckbox.Value {
+			return h.allWithEnemyTypes
+		}
+		return h.allWithEnemies
+	case RecZones:
+		return h.zones
+	}
+
+	return h.healers
+}
+
+func (h *HealingCollector) TextTable(state abstract.LayeredState) abstract.TextTable {
+	stats := h.textStats()
+	long := h.longFormatBool.Value
+
+	table := abstract.TextTable{
+		Title: textTitle(h.TabName()),
+		Parameters: []string{
+			fmt.Sprintf("Subject: %v", h.currentSubject),
+			fmt.Sprintf("Total recovered: %v", stats.total.StringCL(long)),
+		},
+		TimeFrame: textTimeFrame(stats.timeController.CurrentTimeFrame),
+		Columns:   []string{"Name", "Times", "Recovered", "Share"},
+	}
+	if h.enemyTypesCheckbox.Value {
+		table.Parameters = append(table.Parameters, "Grouping by enemy type")
+	}
+
+	for _, healed := range stats.subjects {
+		table.Rows = append(table.Rows, []string{
+			healed.name,
+			strconv.Itoa(healed.amount),
+			healed.recovered.StringCL(long),
+			textShare(healed.recovered.Total(), stats.total.Total()),
+		})
+	}
+
+	return table
+}
+
+func (h *HealingCollector) ChatLine(state abstract.LayeredState) string {
+	stats := h.textStats()
+
+	top := ""
+	if len(stats.subjects) > 0 {
+		top = chatTop(stats.subjects[0].name, stats.subjects[0].recovered.Total(), stats.total.Total())
+	}
+
+	return chatLine(fmt.Sprintf("Recovered: %v", utils.FormatNumber(stats.total.Total())), top)
+}
@@ -0,0 +1,215 @@
+package collectors
+
+import (
+	"PGCombatTracker/abstract"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+type healEffect uint8
+
+const (
+	HealTarget healEffect = iota
+	HealSelf
+	HealGroup
+)
+
+func parseHealEffect(effect string) (healEffect, bool) {
+	switch strings.ToLower(strings.TrimSpace(effect)) {
+	case "", "target":
+		return HealTarget, true
+	case "self":
+		return HealSelf, true
+	case "group":
+		return HealGroup, true
+	}
+
+	return 0, false
+}
+
+type healingRule struct {
+	skill  string
+	effect healEffect
+}
+
+// parseHealingRule Rules look like "Skill Name=effect", where effect is target, self or group
+func parseHealingRule(rule string) (healingRule, bool) {
+	skill, effectName, _ := strings.Cut(rule, "=")
+
+	effect, ok := parseHealEffect(effectName)
+	if !ok {
+		log.Printf("Ignoring healing skill '%v', unknown effect '%v'\n", rule, effectName)
+		return healingRule{}, false
+	}
+
+	skill = normalizeEntityName(skill)
+	if skill == "" {
+		return healingRule{}, false
+	}
+
+	return healingRule{
+		skill:  skill,
+		effect: effect,
+	}, true
+}
+
+// Confidence of different ways a heal can be matched
+const (
+	confidenceMappedTarget = 0.9
+	confidenceMappedGroup  = 0.7
+	confidenceUnmapped     = 0.5
+	confidencePet          = 0.4
+	confidenceRegeneration = 0.2
+)
+
+type healCast struct {
+	caster string
+	skill  string
+	target string
+	at     time.Time
+}
+
+// healingSource Who most likely caused the recovery and how sure we are about it
+type healingSource struct {
+	healer     string
+	skill      string
+	confidence float64
+}
+
+func (h healingSource) String() string {
+	if h.skill == "" {
+		return h.healer
+	}
+
+	return fmt.Sprintf("%v (%v)", h.healer, h.skill)
+}
+
+// healingSources Remembers recent skill uses of allies, to guess who healed whom
+type healingSources struct {
+	rules  []healingRule
+	window time.Duration
+	recent []healCast
+}
+
+func newHealingSources(settings *abstract.Settings) *healingSources {
+	sources := &healingSources{
+		window: time.Duration(settings.SecondsToMatchHealing) * time.Second,
+	}
+
+	for _, rule := range settings.HealingSkills {
+		if parsed, ok := parseHealingRule(rule); ok {
+			sources.rules = append(sources.rules, parsed)
+		}
+	}
+
+	return sources
+}
+
+func (h *healingSources) ruleFor(skill string) (healingRule, bool) {
+	normalized := normalizeEntityName(skill)
+
+	for _, rule := range h.rules {
+		if rule.skill == normalized {
+			return rule, true
+		}
+	}
+
+	return healingRule{}, false
+}
+
+func (h *healingSources) forget(at time.Time) {
+	cutoff := 0
+	for cutoff < len(h.recent) && at.Sub(h.recent[cutoff].at) > h.window {
+		cutoff++
+	}
+	h.recent = h.recent[cutoff:]
+}
+
+// Observe Remembers skill uses of allies that could have healed somebody,
+// which are mapped ones, ones used on an ally without hurting them and ones that recovered something
+func (h *healingSources) Observe(info abstract.StatisticsInformation, event *abstract.ChatEvent) {
+	skillUse, ok := event.Contents.(*abstract.SkillUse)
+	if !ok || !info.IsAlly(skillUse.Subject, skillUse.Skill) {
+		return
+	}
+
+	_, mapped := h.ruleFor(skillUse.Skill)
+	recovers := skillUse.Damage != nil && skillUse.Damage.Total() < 0
+	harmless := skillUse.Damage == nil || skillUse.Damage.Total() == 0
+	onAlly := skillUse.Victim != "" && info.IsAlly(skillUse.Victim, "")
+
+	if !mapped && !recovers && !(onAlly && harmless) {
+		return
+	}
+
+	h.forget(event.Time)
+	h.recent = append(h.recent, healCast{
+		caster: skillUse.Subject,
+		skill:  skillUse.Skill,
+		target: skillUse.Victim,
+		at:     event.Time,
+	})
+}
+
+func (h *healingSources) match(info abstract.StatisticsInformation, cast healCast, recipient string) float64 {
+	if rule, ok := h.ruleFor(cast.skill); ok {
+		switch rule.effect {
+		case HealTarget:
+			if cast.target == recipient {
+				return confidenceMappedTarget
+			}
+		case HealSelf:
+			if cast.caster == recipient {
+				return confidenceMappedTarget
+			}
+		case HealGroup:
+			if info.IsAlly(recipient, "") {
+				return confidenceMappedGroup
+			}
+		}
+
+		return 0
+	}
+
+	if info.OwnerOf(cast.caster) != "" {
+		if cast.target == recipient || info.OwnerOf(recipient) != "" || recipient == info.CurrentUsername() {
+			return confidencePet
+		}
+
+		return 0
+	}
+
+	if cast.target == recipient {
+		return confidenceUnmapped
+	}
+
+	return 0
+}
+
+// Attribute Picks the most likely source of the recovery, falling back to regeneration
+func (h *healingSources) Attribute(info abstract.StatisticsInformation, event *abstract.ChatEvent) healingSource {
+	recovered := event.Contents.(*abstract.Recovered)
+	h.forget(event.Time)
+
+	best := healingSource{
+		healer:     "Regeneration",
+		confidence: confidenceRegeneration,
+	}
+
+	// Newer casts are more likely to be the cause, so they win ties
+	for i := len(h.recent) - 1; i >= 0; i-- {
+		cast := h.recent[i]
+
+		if confidence := h.match(info, cast, recovered.Subject); confidence > best.confidence {
+			best = healingSource{
+				healer:     cast.caster,
+				skill:      cast.skill,
+				confidence: confidence,
+			}
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,585 @@
+package collectors
+
+import (
+	"PGCombatTracker/abstract"
+	"PGCombatTracker/ui/components"
+	"PGCombatTracker/utils"
+	"PGCombatTracker/utils/drawing"
+	"cmp"
+	"fmt"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/widget"
+	"image"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const shownKills = 15
+
+type killingBlow struct {
+	name  string
+	kills int
+}
+
+func addKillingBlow(blows []killingBlow, name string) []killingBlow {
+	blows = utils.CreateUpdate(
+		blows,
+		func(blow killingBlow) bool {
+			return blow.name == name
+		},
+		func() killingBlow {
+			return killingBlow{name: name, kills: 1}
+		},
+		func(blow killingBlow) killingBlow {
+			blow.kills++
+			return blow
+		},
+	)
+
+	slices.SortStableFunc(blows, func(a, b killingBlow) int {
+		return cmp.Compare(b.kills, a.kills)
+	})
+
+	return blows
+}
+
+func blowsString(blows []killingBlow) string {
+	parts := make([]string, len(blows))
+	for i, blow := range blows {
+		parts[i] = fmt.Sprintf("%v %d", blow.name, blow.kills)
+	}
+	return strings.Join(parts, ", ")
+}
+
+type killRecord struct {
+	victim string
+	at     time.Time
+	skill  string
+	killer string
+}
+
+type killRate struct {
+	kills   int
+	perHour float64
+}
+
+func (k killRate) StringCL(long bool) string {
+	if long {
+		return fmt.Sprintf("%d kills, %.2f per hour", k.kills, k.perHour)
+	}
+	return fmt.Sprintf("%v kills, %.0f per hour", utils.FormatNumber(k.kills), k.perHour)
+}
+
+// enemyKills Kills of an enemy type, or of every enemy when name is empty
+type enemyKills struct {
+	name     string
+	kills    []killRecord
+	bySkill  []killingBlow
+	byKiller []killingBlow
+	// timeController Time that passed between kills
+	timeController *components.TimeController
+}
+
+func newEnemyKills(name string) enemyKills {
+	return enemyKills{
+		name:           name,
+		timeController: components.NewTimeControllerOrCrash(components.NewTimeBasedChart("Time between kills")),
+	}
+}
+
+// add Gap of the first kill is counted from the start of the session
+func (e enemyKills) add(kill killRecord, sessionStart time.Time) enemyKills {
+	previous := sessionStart
+	if len(e.kills) > 0 {
+		previous = e.kills[len(e.kills)-1].at
+	}
+	gap := kill.at.Sub(previous)
+
+	e.kills = append(e.kills, kill)
+	e.bySkill = addKillingBlow(e.bySkill, kill.skill)
+	e.byKiller = addKillingBlow(e.byKiller, kill.killer)
+	e.timeController.Add(components.TimePoint{
+		Time:    kill.at,
+		Value:   int(gap.Seconds()),
+		Details: ttkValue(gap),
+	})
+
+	return e
+}
+
+func (e enemyKills) rate(session time.Duration) killRate {
+	return killRate{
+		kills:   len(e.kills),
+		perHour: perHour(len(e.kills), session),
+	}
+}
+
+// averageGap Time between the first and the last kill split evenly
+func (e enemyKills) averageGap() time.Duration {
+	if len(e.kills) < 2 {
+		return 0
+	}
+	return e.kills[len(e.kills)-1].at.Sub(e.kills[0].at) / time.Duration(len(e.kills)-1)
+}
+
+func (e enemyKills) lastKills() []killRecord {
+	kills := slices.Clone(e.kills[max(0, len(e.kills)-shownKills):])
+	slices.Reverse(kills)
+	return kills
+}
+
+func NewKillsCollector() *KillsCollector {
+	typeDropdown, err := components.NewDropdown("Enemy", subjectChoice(""))
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	return &KillsCollector{
+		all:            newEnemyKills(""),
+		typeDropdown:   typeDropdown,
+		longFormatBool: &widget.Bool{},
+	}
+}
+
+// KillsCollector Killing blows dealt by the character, allies and pets, split by enemy type
+type KillsCollector struct {
+	session components.TimeFrame
+	all     enemyKills
+	types   []enemyKills
+
+	currentType    string
+	typeDropdown   *components.Dropdown
+	longFormatBool *widget.Bool
+}
+
+func (k *KillsCollector) Reset(info abstract.StatisticsInformation) {
+	k.session = components.TimeFrame{}
+	k.all = newEnemyKills("")
+	k.types = nil
+	k.typeDropdown.SetOptions([]fmt.Stringer{subjectChoice("")})
+	k.currentType = ""
+}
+
+func (k *KillsCollector) Tick(info abstract.StatisticsInformation, at time.Time) {
+
+}
+
+func (k *KillsCollector) Collect(info abstract.StatisticsInformation, event *abstract.ChatEvent) error {
+	if k.session.From.IsZero() {
+		k.session = components.TimeFrame{From: event.Time, To: event.Time}
+	} else {
+		k.session = k.session.Expand(event.Time)
+	}
+
+	skillUse, ok := event.Contents.(*abstract.SkillUse)
+	if !ok || !skillUse.Fatality || skillUse.Victim == "" {
+		return nil
+	}
+
+	if info.IsAlly(skillUse.Victim, "") || !info.IsAlly(skillUse.Subject, skillUse.Skill) {
+		return nil
+	}
+
+	skillName := skillUse.Skill
+	if info.Settings().RemoveLevelsFromSkills {
+		skillName = SplitOffId(skillName)
+	}
+
+	killer := skillUse.Subject
+	if owner := info.OwnerOf(skillUse.Subject); owner != "" {
+		killer = fmt.Sprintf("%v (pet of %v)", SplitOffId(skillUse.Subject), owner)
+	}
+
+	kill := killRecord{
+		victim: skillUse.Victim,
+		at:     event.Time,
+		skill:  skillName,
+		killer: killer,
+	}
+	enemyType := SplitOffId(skillUse.Victim)
+
+	k.all = k.all.add(kill, k.session.From)
+	k.types = utils.CreateUpdate(
+		k.types,
+		func(kills enemyKills) bool {
+			return kills.name == enemyType
+		},
+		func() enemyKills {
+			return newEnemyKills(enemyType).add(kill, k.session.From)
+		},
+		func(kills enemyKills) enemyKills {
+			return kills.add(kill, k.session.From)
+		},
+	)
+	slices.SortStableFunc(k.types, func(a, b enemyKills) int {
+		return cmp.Compare(len(b.kills), len(a.kills))
+	})
+
+	k.typeDropdown.SetOptions(utils.CreateUpdate(
+		k.typeDropdown.Options(),
+		func(item fmt.Stringer) bool {
+			casted := item.(subjectChoice)
+			return string(casted) == enemyType
+		},
+		func() fmt.Stringer {
+			return subjectChoice(enemyType)
+		},
+		func(stringer fmt.Stringer) fmt.Stringer {
+			return stringer
+		},
+	))
+
+	return nil
+}
+
+func (k *KillsCollector) TabName() string {
+	return "Kills"
+}
+
+func (k *KillsCollector) selected() enemyKills {
+	for _, kills := range k.types {
+		if kills.name == k.currentType {
+			return kills
+		}
+	}
+
+	return k.all
+}
+
+func (k *KillsCollector) sessionLength() time.Duration {
+	return k.session.To.Sub(k.session.From)
+}
+
+func addKillLabels[T any](kills enemyKills, session time.Duration, long bool, label func(format string, args ...any) T) []T {
+	return []T{
+		label("%v", kills.rate(session).StringCL(long)),
+		label("Average time between kills: %v", ttkValue(kills.averageGap()).StringCL(long)),
+		label("Over %v", ttkValue(session).StringCL(long)),
+	}
+}
+
+// Metrics Kills of the character's group, pets and allies included
+func (k *KillsCollector) Metrics(character string) []abstract.Metric {
+	metrics := make([]abstract.Metric, len(k.types))
+	for i, kills := range k.types {
+		metrics[i] = abstract.Metric{
+			Name:   "pgct_kills",
+			Help:   "Killing blows dealt by the character, allies and pets",
+			Type:   abstract.MetricCounter,
+			Labels: []abstract.MetricLabel{{Name: "enemy_type", Value: kills.name}},
+			Value:  float64(len(kills.kills)),
+		}
+	}
+
+	return metrics
+}
+
+// Summary Kills aren't split by character, the character's set only has kills made while playing it
+func (k *KillsCollector) Summary(character string) string {
+	if len(k.all.kills) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("Kills: %v", k.all.rate(k.sessionLength()).StringCL(false))
+}
+
+type KillsSnapshot struct {
+	Name        string
+	Kills       int
+	PerHour     float64
+	BySkill     map[string]int
+	ByAllyOrPet map[string]int
+}
+
+func snapshotKills(kills enemyKills, session time.Duration) KillsSnapshot {
+	snapshot := KillsSnapshot{
+		Name:        kills.name,
+		Kills:       len(kills.kills),
+		PerHour:     perHour(len(kills.kills), session),
+		BySkill:     make(map[string]int),
+		ByAllyOrPet: make(map[string]int),
+	}
+
+	for _, blow := range kills.bySkill {
+		snapshot.BySkill[blow.name] = blow.kills
+	}
+	for _, blow := range kills.byKiller {
+		snapshot.ByAllyOrPet[blow.name] = blow.kills
+	}
+
+	return snapshot
+}
+
+func (k *KillsCollector) Snapshot() any {
+	session := k.sessionLength()
+
+	types := make([]KillsSnapshot, len(k.types))
+	for i, kills := range k.types {
+		types[i] = snapshotKills(kills, session)
+	}
+
+	return struct {
+		Total KillsSnapshot
+		Types []KillsSnapshot
+	}{
+		Total: snapshotKills(k.all, session),
+		Types: types,
+	}
+}
+
+func (k *KillsCollector) UI(state abstract.LayeredState) (layout.Widget, []layout.Widget) {
+	if k.typeDropdown.Changed() {
+		k.currentType = string(k.typeDropdown.Value.(subjectChoice))
+	}
+
+	long := k.longFormatBool.Value
+	session := k.sessionLength()
+	kills := k.selected()
+
+	topWidget := topBarSurface(func(gtx layout.Context) layout.Dimensions {
+		if k.longFormatBool.Update(gtx) {
+			gtx.Source.Execute(op.InvalidateCmd{})
+		}
+
+		return layout.Flex{
+			Axis: layout.Vertical,
+		}.Layout(
+			gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{
+					Axis:      layout.Horizontal,
+					Alignment: layout.Middle,
+				}.Layout(
+					gtx,
+					layout.Rigid(defaultDropdownStyle(state, k.typeDropdown).Layout),
+					utils.FlexSpacerW(utils.CommonSpacing),
+					layout.Rigid(defaultCheckboxStyle(state, k.longFormatBool, "Use long numbers").Layout),
+				)
+			}),
+			utils.FlexSpacerH(utils.CommonSpacing),
+			layout.Rigid(components.StyleTimeController(state.Theme(), kills.timeController).Layout),
+		)
+	})
+
+	label := func(format string, args ...any) layout.Widget {
+		text := fmt.Sprintf(format, args...)
+
+		return func(gtx layout.Context) layout.Dimensions {
+			return layout.UniformInset(utils.CommonSpacing).Layout(
+				gtx,
+				func(gtx layout.Context) layout.Dimensions {
+					style := defaultLabelStyle(state, text)
+					style.TextSize = 14
+					return style.Layout(gtx)
+				},
+			)
+		}
+	}
+	caption := func(text string) layout.Widget {
+		return func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{
+				Left:   utils.CommonSpacing,
+				Bottom: utils.CommonSpacing,
+			}.Layout(gtx, utils.WithColor(defaultLabelStyle(state, text), utils.GrayText).Layout)
+		}
+	}
+	blowBars := func(blows []killingBlow) []layout.Widget {
+		widgets := make([]layout.Widget, len(blows))
+		for i, blow := range blows {
+			widgets[i] = drawUniversalBar(
+				state, killRate{kills: blow.kills, perHour: perHour(blow.kills, session)},
+				blow.kills, blows[0].kills, 0,
+				blow.name, "",
+				25, long,
+			)
+		}
+		return widgets
+	}
+
+	widgets := addKillLabels(kills, session, long, label)
+
+	if k.currentType == "" {
+		for _, enemy := range k.types {
+			widgets = append(
+				widgets,
+				drawUniversalBar(
+					state, enemy.rate(session),
+					len(enemy.kills), len(k.types[0].kills), 0,
+					enemy.name, "",
+					30, long,
+				),
+				caption(fmt.Sprintf("By skill: %v", blowsString(enemy.bySkill))),
+				caption(fmt.Sprintf("By ally or pet: %v", blowsString(enemy.byKiller))),
+			)
+		}
+
+		return topWidget, widgets
+	}
+
+	widgets = append(widgets, label("Killing blows by skill"))
+	widgets = append(widgets, blowBars(kills.bySkill)...)
+	widgets = append(widgets, label("Killing blows by ally or pet"))
+	widgets = append(widgets, blowBars(kills.byKiller)...)
+	widgets = append(widgets, label("Last kills"))
+	for _, kill := range kills.lastKills() {
+		widgets = append(widgets, caption(fmt.Sprintf(
+			"%v %v, %v by %v",
+			kill.at.Format(time.TimeOnly), kill.victim, kill.skill, kill.killer,
+		)))
+	}
+
+	return topWidget, widgets
+}
+
+func (k *KillsCollector) Export(state abstract.LayeredState) image.Image {
+	long := k.longFormatBool.Value
+	session := k.sessionLength()
+	kills := k.selected()
+	styledFonts := drawing.StyleFontPack(state.FontPack(), state.Theme().Fg)
+
+	label := func(format string, args ...any) drawing.FlexChild {
+		text := fmt.Sprintf(format, args...)
+
+		return drawing.Rigid(drawing.UniformInset(drawing.CommonSpacing).Layout(
+			styledFonts.Body.Layout(text),
+		))
+	}
+	caption := func(text string) drawing.FlexChild {
+		return drawing.Rigid(drawing.UniformInset(drawing.CommonSpacing).Layout(
+			styledFonts.Smaller.Layout(text),
+		))
+	}
+	blowBars := func(blows []killingBlow) []drawing.FlexChild {
+		var items []drawing.FlexChild
+		for _, blow := range blows {
+			items = append(
+				items,
+				drawing.Rigid(exportUniversalBar(
+					styledFonts, killRate{kills: blow.kills, perHour: perHour(blow.kills, session)},
+					blow.kills, blows[0].kills, 0,
+					blow.name, "",
+					long,
+				)),
+				drawing.FlexVSpacer(drawing.CommonSpacing),
+			)
+		}
+		return items
+	}
+
+	items := []drawing.FlexChild{
+		drawing.Rigid(exportTimeFrame(styledFonts, kills.timeController.CurrentTimeFrame)),
+		drawing.FlexVSpacer(drawing.CommonSpacing),
+	}
+	items = append(items, addKillLabels(kills, session, long, label)...)
+
+	if k.currentType == "" {
+		for _, enemy := range k.types {
+			items = append(
+				items,
+				drawing.Rigid(exportUniversalBar(
+					styledFonts, enemy.rate(session),
+					len(enemy.kills), len(k.types[0].kills), 0,
+					enemy.name, "",
+					long,
+				)),
+				caption(fmt.Sprintf("By skill: %v", blowsString(enemy.bySkill))),
+				caption(fmt.Sprintf("By ally or pet: %v", blowsString(enemy.byKiller))),
+			)
+		}
+	} else {
+		items = append(items, label("Killing blows by skill"))
+		items = append(items, blowBars(kills.bySkill)...)
+		items = append(items, label("Killing blows by ally or pet"))
+		items = append(items, blowBars(kills.byKiller)...)
+	}
+
+	body := drawing.Flex{
+		Axis:    layout.Vertical,
+		ExpandW: true,
+	}.Layout(
+		items...,
+	)
+
+	enemyName := k.currentType
+	if enemyName == "" {
+		enemyName = "all enemies"
+	}
+
+	base := layoutTitle(
+		styledFonts,
+		k.TabName(),
+		styledFonts.Smaller.Layout(fmt.Sprintf("Kills of %v", enemyName)),
+		drawing.RoundedSurface(
+			utils.SecondBG,
+			body,
+		),
+	)
+
+	return drawing.ExportImage(state.Theme(), base, drawing.F64(800, 10000))
+}
+
+func (k *KillsCollector) TextTable(state abstract.LayeredState) abstract.TextTable {
+	long := k.longFormatBool.Value
+	session := k.sessionLength()
+	kills := k.selected()
+
+	enemyName := k.currentType
+	if enemyName == "" {
+		enemyName = "all enemies"
+	}
+
+	table := abstract.TextTable{
+		Title:      textTitle(k.TabName()),
+		Parameters: []string{fmt.Sprintf("Kills of %v", enemyName)},
+		TimeFrame:  textTimeFrame(kills.timeController.CurrentTimeFrame),
+		Columns:    []string{"Enemy", "Kills", "Per hour", "Share"},
+	}
+	table.Parameters = append(table.Parameters, addKillLabels(kills, session, long, func(format string, args ...any) string {
+		return fmt.Sprintf(format, args...)
+	})...)
+
+	blows := make([]killingBlow, len(k.types))
+	for i, enemy := range k.types {
+		blows[i] = killingBlow{name: enemy.name, kills: len(enemy.kills)}
+	}
+	if k.currentType != "" {
+		blows = kills.bySkill
+		table.Columns[0] = "Killing blow by skill"
+		table.Parameters = append(table.Parameters, fmt.Sprintf("By ally or pet: %v", blowsString(kills.byKiller)))
+	}
+
+	for _, blow := range blows {
+		table.Rows = append(table.Rows, []string{
+			blow.name,
+			strconv.Itoa(blow.kills),
+			fmt.Sprintf("%.1f", perHour(blow.kills, session)),
+			textShare(blow.kills, len(kills.kills)),
+		})
+	}
+
+	return table
+}
+
+func (k *KillsCollector) ChatLine(state abstract.LayeredState) string {
+	session := k.sessionLength()
+	kills := k.selected()
+
+	name := "Kills"
+	top := ""
+	if k.currentType == "" && len(k.types) > 0 {
+		top = chatTop(k.types[0].name, len(k.types[0].kills), len(kills.kills))
+	} else if k.currentType != "" {
+		name = fmt.Sprintf("%v kills", k.currentType)
+		if len(kills.bySkill) > 0 {
+			top = chatTop(kills.bySkill[0].name, kills.bySkill[0].kills, len(kills.kills))
+		}
+	}
+
+	line := fmt.Sprintf("%v: %v (%.0f/h)", name, utils.FormatNumber(len(kills.kills)), perHour(len(kills.kills), session))
+
+	return chatLine(line, top)
+}
@@ -14,23 +14,67 @@ import (
 	"image"
 	"log"
 	"slices"
+	"strconv"
+	"strings"
 	"time"
 )
 
+type xpGain struct {
+	xp int
+	at time.Time
+}
+
 type skillXP struct {
 	name   string
 	xp     int
 	levels int
 	chart  *components.TimeBasedChart
+
+	// level Known from the last level up, 0 if the skill didn't level up
+	level int
+	// levelXP XP gained since the last level up, or since the first gain if level isn't known
+	levelXP int
+	gains   []xpGain
+}
+
+// addZoneXP Adds XP to the zone it was gained in, zones are kept sorted by XP
+func addZoneXP(zones []skillXP, zone string, xp int, leveled bool) []skillXP {
+	update := func(skill skillXP) skillXP {
+		skill.xp += xp
+		if leveled {
+			skill.levels++
+		}
+		return skill
+	}
+
+	zones = utils.CreateUpdate(
+		zones,
+		func(skill skillXP) bool {
+			return skill.name == zone
+		},
+		func() skillXP {
+			return update(skillXP{name: zone})
+		},
+		update,
+	)
+
+	slices.SortFunc(zones, func(a, b skillXP) int {
+		return cmp.Compare(b.xp, a.xp)
+	})
+
+	return zones
 }
 
 type subjectiveSkillsXP struct {
 	name           string
 	skills         []skillXP
+	zones          []skillXP
 	timeController *components.TimeController
 	totalXP        int
 	maxXP          int
 	maxRange       components.DataRange
+	// hourXP XP gained over the last hour, for the XP record
+	hourXP slidingWindow
 }
 
 func NewLevelingCollector() *LevelingCollector {
@@ -47,6 +91,7 @@ func NewLevelingCollector() *LevelingCollector {
 		DisplayBars,
 		DisplayPie,
 		DisplayGraphs,
+		DisplayProgress,
 	)
 	if err != nil {
 		log.Fatalln(err)
@@ -58,6 +103,7 @@ func NewLevelingCollector() *LevelingCollector {
 		},
 		subjectDropdown: subjectDropdown,
 		displayDropdown: displayDropdown,
+		groupDropdown:   newGroupDropdown(GroupNone, GroupCategory, GroupZone),
 		longFormatBool:  &widget.Bool{},
 	}
 }
@@ -65,11 +111,14 @@ func NewLevelingCollector() *LevelingCollector {
 type LevelingCollector struct {
 	all      subjectiveSkillsXP
 	subjects []subjectiveSkillsXP
+	now      time.Time
 
 	currentSubject  string
 	currentDisplay  displayChoice
+	currentGroup    groupChoice
 	subjectDropdown *components.Dropdown
 	displayDropdown *components.Dropdown
+	groupDropdown   *components.Dropdown
 	longFormatBool  *widget.Bool
 }
 
@@ -78,12 +127,15 @@ func (l *LevelingCollector) Reset(info abstract.StatisticsInformation) {
 		timeController: components.NewTimeControllerOrCrash(components.NewTimeBasedChart("Total")),
 	}
 	l.subjects = nil
+	l.now = time.Time{}
 	l.subjectDropdown.SetOptions([]fmt.Stringer{subjectChoice("")})
 	l.currentSubject = ""
 }
 
 func (l *LevelingCollector) Tick(info abstract.StatisticsInformation, at time.Time) {
-
+	if at.After(l.now) {
+		l.now = at
+	}
 }
 
 func (l *LevelingCollector) Collect(info abstract.StatisticsInformation, event *abstract.ChatEvent) error {
@@ -103,36 +155,49 @@ func (l *LevelingCollector) Collect(info abstract.StatisticsInformation, event *
 		return nil
 	}
 
+	if event.Time.After(l.now) {
+		l.now = event.Time
+	}
+
 	findSkillXp := func(skill skillXP) bool {
 		return skill.name == skillName
 	}
 	createSkillXp := func(leveled bool) func() skillXP {
 		return func() skillXP {
-			var level int
+			skill := skillXP{
+				name:    skillName,
+				xp:      gainedXP,
+				levelXP: gainedXP,
+				gains:   []xpGain{{xp: gainedXP, at: event.Time}},
+				chart:   components.NewTimeBasedChart(skillName),
+			}
+
 			if leveled {
-				level = 1
+				skill.levels = 1
+				skill.level = leveledXP.Level
+				skill.levelXP = 0
+				skill.chart.Marks = append(skill.chart.Marks, event.Time)
 			}
 
-			chart := components.NewTimeBasedChart(skillName)
-			chart.Add(components.TimePoint{
+			skill.chart.Add(components.TimePoint{
 				Time:    event.Time,
 				Value:   gainedXP,
 				Details: XPValue(gainedXP),
 			})
 
-			return skillXP{
-				name:   skillName,
-				xp:     gainedXP,
-				levels: level,
-				chart:  chart,
-			}
+			return skill
 		}
 	}
 	updateSkillXp := func(leveled bool) func(skillXP) skillXP {
 		return func(skill skillXP) skillXP {
 			skill.xp += gainedXP
+			skill.levelXP += gainedXP
+			skill.gains = append(skill.gains, xpGain{xp: gainedXP, at: event.Time})
 			if leveled {
 				skill.levels++
+				skill.level = leveledXP.Level
+				skill.levelXP = 0
+				skill.chart.Marks = append(skill.chart.Marks, event.Time)
 			}
 			skill.chart.Add(components.TimePoint{
 				Time:    event.Time,
@@ -157,6 +222,8 @@ func (l *LevelingCollector) Collect(info abstract.StatisticsInformation, event *
 			updateSkillXp(leveled),
 		)
 		stats.totalXP += gainedXP
+		stats.zones = addZoneXP(stats.zones, zoneOf(info), gainedXP, leveled)
+		stats.hourXP.add(time.Hour, gainedXP, event.Time)
 		stats.timeController.Add(components.TimePoint{
 			Time:  event.Time,
 			Value: stats.totalXP,
@@ -177,16 +244,20 @@ func (l *LevelingCollector) Collect(info abstract.StatisticsInformation, event *
 					Time:  event.Time,
 					Value: gainedXP,
 				})
-				return subjectiveSkillsXP{
+				subject := subjectiveSkillsXP{
 					name: info.CurrentUsername(),
 					skills: []skillXP{
 						createSkillXp(leveled)(),
 					},
+					zones:          addZoneXP(nil, zoneOf(info), gainedXP, leveled),
 					totalXP:        gainedXP,
 					maxXP:          gainedXP,
 					timeController: timeController,
 					maxRange:       components.DataRange{Max: gainedXP},
 				}
+				subject.hourXP.add(time.Hour, gainedXP, event.Time)
+
+				return subject
 			},
 			func(subject subjectiveSkillsXP) subjectiveSkillsXP {
 				processSubjectiveSkillXp(&subject, leveled)
@@ -225,6 +296,118 @@ func (l *LevelingCollector) TabName() string {
 	return "XP Gained"
 }
 
+// xpOf XP the subject gained in a skill, skill name isn't case-sensitive
+func (l *LevelingCollector) xpOf(subjectName, skillName string) int {
+	for _, subject := range l.subjects {
+		if subject.name != subjectName {
+			continue
+		}
+
+		for _, skill := range subject.skills {
+			if strings.EqualFold(skill.name, skillName) {
+				return skill.xp
+			}
+		}
+	}
+
+	return 0
+}
+
+func (l *LevelingCollector) totalXPOf(subjectName string) int {
+	for _, subject := range l.subjects {
+		if subject.name == subjectName {
+			return subject.totalXP
+		}
+	}
+
+	return 0
+}
+
+func (l *LevelingCollector) Metrics(character string) []abstract.Metric {
+	var metrics []abstract.Metric
+
+	for _, subject := range l.subjects {
+		if subject.name != character {
+			continue
+		}
+
+		for _, skill := range subject.skills {
+			metrics = append(metrics, abstract.Metric{
+				Name:   "pgct_xp_gained",
+				Help:   "XP gained in a skill",
+				Type:   abstract.MetricCounter,
+				Labels: []abstract.MetricLabel{{Name: "skill", Value: skill.name}},
+				Value:  float64(skill.xp),
+			})
+		}
+	}
+
+	return metrics
+}
+
+func (l *LevelingCollector) Summary(character string) string {
+	total := 0
+	for _, subject := range l.subjects {
+		if character == "" || subject.name == character {
+			total += subject.totalXP
+		}
+	}
+
+	if total == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("XP gained: %v", utils.FormatNumber(total))
+}
+
+type SkillXPSnapshot struct {
+	Name   string
+	XP     int
+	Levels int
+}
+
+type LevelingSnapshot struct {
+	Name   string
+	XP     int
+	Skills []SkillXPSnapshot
+}
+
+func (l *LevelingCollector) Snapshot() any {
+	subjects := make([]LevelingSnapshot, len(l.subjects))
+	for i, subject := range l.subjects {
+		subjects[i] = LevelingSnapshot{
+			Name:   subject.name,
+			XP:     subject.totalXP,
+			Skills: make([]SkillXPSnapshot, len(subject.skills)),
+		}
+
+		for j, skill := range subject.skills {
+			subjects[i].Skills[j] = SkillXPSnapshot{
+				Name:   skill.name,
+				XP:     skill.xp,
+				Levels: skill.levels,
+			}
+		}
+	}
+
+	return subjects
+}
+
+// Records Most XP the character gained within an hour
+func (l *LevelingCollector) Records(character string) []abstract.PersonalRecord {
+	for _, subject := range l.subjects {
+		if subject.name == character && subject.hourXP.best > 0 {
+			return []abstract.PersonalRecord{{
+				Kind:  abstract.RecordHourXP,
+				Value: float64(subject.hourXP.best),
+				At:    subject.hourXP.bestAt,
+			}}
+		}
+	}
+
+	return nil
+}
+
 type XPValue int
 
 func (xp XPValue) StringCL(long bool) string {
@@ -250,6 +433,55 @@ func (xp XPValue) InterpolateILF(other utils.InterpolatableLongFormatable, t flo
 	return xp.Interpolate(other, t).(utils.InterpolatableLongFormatable)
 }
 
+// rates XP rates of the subject's skills in the same order, and the highest overall rate
+func (l *LevelingCollector) rates(state abstract.GlobalState, subject subjectiveSkillsXP) ([]xpRate, int) {
+	window := time.Duration(state.Settings().XPRateWindowMinutes) * time.Minute
+
+	var maxRate int
+	rates := make([]xpRate, len(subject.skills))
+	for i, skill := range subject.skills {
+		rates[i] = skillRate(skill, l.now, window)
+		maxRate = max(maxRate, int(rates[i].overall))
+	}
+
+	return rates, maxRate
+}
+
+// grouped Skills merged by the current grouping, used by bars and pie only
+func (l *LevelingCollector) grouped(state abstract.GlobalState, subject subjectiveSkillsXP) ([]skillXP, int) {
+	if l.currentGroup == GroupZone {
+		if len(subject.zones) == 0 {
+			return subject.zones, 0
+		}
+
+		return subject.zones, subject.zones[0].xp
+	}
+
+	skills := groupItems(
+		state.SkillCategories(),
+		l.currentGroup,
+		subject.skills,
+		func(skill skillXP) string {
+			return skill.name
+		},
+		func(group string, grouped skillXP, skill skillXP) skillXP {
+			grouped.name = group
+			grouped.xp += skill.xp
+			grouped.levels += skill.levels
+			return grouped
+		},
+		func(a, b skillXP) int {
+			return cmp.Compare(b.xp, a.xp)
+		},
+	)
+
+	if len(skills) == 0 {
+		return skills, subject.maxXP
+	}
+
+	return skills, skills[0].xp
+}
+
 func (l *LevelingCollector) drawWidget(state abstract.LayeredState, skill skillXP, widget layout.Widget, size unit.Dp) layout.Widget {
 	return drawUniversalStatsText(
 		state, XPValue(skill.xp),
@@ -277,6 +509,10 @@ func (l *LevelingCollector) UI(state abstract.LayeredState) (layout.Widget, []la
 		l.currentDisplay = l.displayDropdown.Value.(displayChoice)
 	}
 
+	if l.groupDropdown.Changed() {
+		l.currentGroup = l.groupDropdown.Value.(groupChoice)
+	}
+
 	subject := l.all
 	for _, possibleSubject := range l.subjects {
 		if possibleSubject.name == l.currentSubject {
@@ -304,6 +540,12 @@ func (l *LevelingCollector) UI(state abstract.LayeredState) (layout.Widget, []la
 					defaultDropdownStyle(state, l.subjectDropdown).Layout,
 					defaultCheckboxStyle(state, l.longFormatBool, "Use long numbers").Layout,
 					defaultDropdownStyle(state, l.displayDropdown).Layout,
+					func(gtx layout.Context) layout.Dimensions {
+						if l.currentDisplay != DisplayBars && l.currentDisplay != DisplayPie {
+							return layout.Dimensions{}
+						}
+						return defaultDropdownStyle(state, l.groupDropdown).Layout(gtx)
+					},
 				)
 			}),
 			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
@@ -326,14 +568,16 @@ func (l *LevelingCollector) UI(state abstract.LayeredState) (layout.Widget, []la
 
 	switch l.currentDisplay {
 	case DisplayBars:
-		for _, skill := range subject.skills {
-			widgets = append(widgets, l.drawBar(state, skill, subject.maxXP, 40))
+		skills, maxXP := l.grouped(state, subject)
+		for _, skill := range skills {
+			widgets = append(widgets, l.drawBar(state, skill, maxXP, 40))
 		}
 	case DisplayPie:
+		skills, _ := l.grouped(state, subject)
 		widgets = append(widgets, func(gtx layout.Context) layout.Dimensions {
 			var totalValue int
-			pieItems := make([]components.PieChartItem, 0, max(1, len(subject.skills)))
-			for _, skill := range subject.skills {
+			pieItems := make([]components.PieChartItem, 0, max(1, len(skills)))
+			for _, skill := range skills {
 				pieItems = append(pieItems, components.PieChartItem{
 					Name:    skill.name,
 					Value:   skill.xp,
@@ -364,6 +608,26 @@ func (l *LevelingCollector) UI(state abstract.LayeredState) (layout.Widget, []la
 
 			widgets = append(widgets, l.drawWidget(state, skill, chartStyle.Layout, 100))
 		}
+	case DisplayProgress:
+		rates, maxRate := l.rates(state, subject)
+
+		for i, skill := range subject.skills {
+			widgets = append(widgets, drawUniversalBar(
+				state, rates[i],
+				int(rates[i].overall), maxRate, skill.levels,
+				skill.name, "leveled %v times",
+				40, l.longFormatBool.Value,
+			))
+
+			for _, text := range progressTexts(state, skill, rates[i]) {
+				widgets = append(widgets, func(gtx layout.Context) layout.Dimensions {
+					return layout.Inset{
+						Left:   utils.CommonSpacing * 2,
+						Bottom: utils.CommonSpacing,
+					}.Layout(gtx, defaultLabelStyle(state, text).Layout)
+				})
+			}
+		}
 	}
 
 	return topWidget, widgets
@@ -402,14 +666,15 @@ func (l *LevelingCollector) Export(state abstract.LayeredState) image.Image {
 
 	switch l.currentDisplay {
 	case DisplayBars:
-		items := make([]drawing.FlexChild, 0, len(subject.skills)*2-1)
+		skills, maxXP := l.grouped(state, subject)
+		items := make([]drawing.FlexChild, 0, len(skills)*2-1)
 
-		for i, skill := range subject.skills {
+		for i, skill := range skills {
 			if i != 0 {
 				items = append(items, drawing.FlexVSpacer(drawing.CommonSpacing))
 			}
 
-			items = append(items, drawing.Rigid(l.exportBar(styledFonts, skill, subject.maxXP)))
+			items = append(items, drawing.Rigid(l.exportBar(styledFonts, skill, maxXP)))
 		}
 
 		body = drawing.Flex{
@@ -419,10 +684,11 @@ func (l *LevelingCollector) Export(state abstract.LayeredState) image.Image {
 			items...,
 		)
 	case DisplayPie:
+		skills, _ := l.grouped(state, subject)
 		var totalValue int
 
-		pieItems := make([]drawing.PieChartItem, 0, max(1, len(subject.skills)))
-		for _, skill := range subject.skills {
+		pieItems := make([]drawing.PieChartItem, 0, max(1, len(skills)))
+		for _, skill := range skills {
 			pieItems = append(pieItems, drawing.PieChartItem{
 				Name:    skill.name,
 				Value:   skill.xp,
@@ -459,6 +725,35 @@ func (l *LevelingCollector) Export(state abstract.LayeredState) image.Image {
 			items = append(items, drawing.Rigid(l.exportWidget(styledFonts, skill, style.Layout())))
 		}
 
+		body = drawing.Flex{
+			ExpandW: true,
+			Axis:    layout.Vertical,
+		}.Layout(
+			items...,
+		)
+	case DisplayProgress:
+		rates, maxRate := l.rates(state, subject)
+
+		var items []drawing.FlexChild
+		for i, skill := range subject.skills {
+			if i != 0 {
+				items = append(items, drawing.FlexVSpacer(drawing.CommonSpacing))
+			}
+
+			items = append(items, drawing.Rigid(exportUniversalBar(
+				styledFonts, rates[i],
+				int(rates[i].overall), maxRate, skill.levels,
+				skill.name, "leveled %v times",
+				l.longFormatBool.Value,
+			)))
+
+			for _, text := range progressTexts(state, skill, rates[i]) {
+				items = append(items, drawing.Rigid(drawing.UniformInset(drawing.CommonSpacing).Layout(
+					styledFonts.Smaller.Layout(text),
+				)))
+			}
+		}
+
 		body = drawing.Flex{
 			ExpandW: true,
 			Axis:    layout.Vertical,
@@ -483,6 +778,13 @@ func (l *LevelingCollector) Export(state abstract.LayeredState) image.Image {
 				return styledFonts.Smaller.Layout("Subject: All")(ltx)
 			},
 			styledFonts.Smaller.Layout(fmt.Sprintf("Display: %v", l.currentDisplay)),
+			func(ltx drawing.Context) drawing.Result {
+				if l.currentDisplay == DisplayBars || l.currentDisplay == DisplayPie {
+					return styledFonts.Smaller.Layout(fmt.Sprintf("Grouped by: %v", l.currentGroup))(ltx)
+				}
+
+				return drawing.Empty(ltx)
+			},
 		),
 		drawing.RoundedSurface(
 			utils.SecondBG,
@@ -492,3 +794,55 @@ func (l *LevelingCollector) Export(state abstract.LayeredState) image.Image {
 
 	return drawing.ExportImage(state.Theme(), base, drawing.F64(800, 10000))
 }
+
+func (l *LevelingCollector) TextTable(state abstract.LayeredState) abstract.TextTable {
+	subject := l.all
+	for _, possibleSubject := range l.subjects {
+		if possibleSubject.name == l.currentSubject {
+			subject = possibleSubject
+			break
+		}
+	}
+
+	long := l.longFormatBool.Value
+	skills, _ := l.grouped(state, subject)
+
+	table := abstract.TextTable{
+		Title: textTitle(l.TabName()),
+		Parameters: []string{
+			fmt.Sprintf("Subject: %v", subjectChoice(l.currentSubject)),
+			fmt.Sprintf("Grouped by: %v", l.currentGroup),
+			fmt.Sprintf("Total XP: %v", XPValue(subject.totalXP).StringCL(long)),
+		},
+		TimeFrame: textTimeFrame(subject.timeController.CurrentTimeFrame),
+		Columns:   []string{"Skill", "Levels", "XP", "Share"},
+	}
+
+	for _, skill := range skills {
+		table.Rows = append(table.Rows, []string{
+			skill.name,
+			strconv.Itoa(skill.levels),
+			XPValue(skill.xp).StringCL(long),
+			textShare(skill.xp, subject.totalXP),
+		})
+	}
+
+	return table
+}
+
+func (l *LevelingCollector) ChatLine(state abstract.LayeredState) string {
+	subject := l.all
+	for _, possibleSubject := range l.subjects {
+		if possibleSubject.name == l.currentSubject {
+			subject = possibleSubject
+			break
+		}
+	}
+
+	top := ""
+	if skills, _ := l.grouped(state, subject); len(skills) > 0 {
+		top = chatTop(skills[0].name, skills[0].xp, subject.totalXP)
+	}
+
+	return chatLine(fmt.Sprintf("XP: %v", utils.FormatNumber(subject.totalXP)), top)
+}
@@ -9,7 +9,6 @@ import (
 	"gioui.org/layout"
 	"image"
 	"log"
-	"strings"
 	"time"
 )
 
@@ -27,6 +26,31 @@ type subjectiveMisc struct {
 	noDamageCount      int
 	enemyNoDamageCount int
 	deathCount         int
+
+	lastSeen        time.Time
+	streak          time.Duration
+	longestStreak   time.Duration
+	longestStreakAt time.Time
+}
+
+// advanceStreak Counts time played since the last death, long pauses are skipped
+func (misc subjectiveMisc) advanceStreak(at time.Time) subjectiveMisc {
+	if !misc.lastSeen.IsZero() {
+		if gap := at.Sub(misc.lastSeen); gap > 0 && gap <= streakIdleLimit {
+			misc.streak += gap
+		}
+	}
+
+	if at.After(misc.lastSeen) {
+		misc.lastSeen = at
+	}
+
+	if misc.streak > misc.longestStreak {
+		misc.longestStreak = misc.streak
+		misc.longestStreakAt = at
+	}
+
+	return misc
 }
 
 func NewMiscCollector() *MiscCollector {
@@ -75,31 +99,17 @@ func (m *MiscCollector) updateData(subject string, updateFunc func(misc subjecti
 	)
 }
 
-func (m *MiscCollector) isAlly(info abstract.StatisticsInformation, subject, skill string) bool {
-	if subject == info.CurrentUsername() {
-		return true
-	}
-
-	if strings.Contains(skill, "(Pet)") {
-		return true
-	}
-
-	lowTrimmedSubject := strings.ToLower(strings.TrimSpace(SplitOffId(subject)))
-
-	for _, expectedName := range info.Settings().EntitiesThatCountAsPets {
-		if lowTrimmedSubject == strings.ToLower(strings.TrimSpace(expectedName)) {
-			return true
-		}
-	}
-
-	return false
-}
-
 func (m *MiscCollector) Tick(info abstract.StatisticsInformation, at time.Time) {
 
 }
 
 func (m *MiscCollector) Collect(info abstract.StatisticsInformation, event *abstract.ChatEvent) error {
+	if info.CurrentUsername() != "" {
+		m.updateData(info.CurrentUsername(), func(misc subjectiveMisc) subjectiveMisc {
+			return misc.advanceStreak(event.Time)
+		})
+	}
+
 	if collected, ok := event.Contents.(*abstract.FoundCoins); ok {
 		m.updateData(info.CurrentUsername(), func(misc subjectiveMisc) subjectiveMisc {
 			misc.coinsFound += collected.Coins
@@ -115,7 +125,7 @@ func (m *MiscCollector) Collect(info abstract.StatisticsInformation, event *abst
 			misc.errorCount++
 			return misc
 		})
-	} else if skill, ok := event.Contents.(*abstract.SkillUse); ok && m.isAlly(info, skill.Subject, skill.Skill) {
+	} else if skill, ok := event.Contents.(*abstract.SkillUse); ok && info.IsAlly(skill.Subject, skill.Skill) {
 		m.updateData(info.CurrentUsername(), func(misc subjectiveMisc) subjectiveMisc {
 			switch {
 			case skill.Fatality:
@@ -135,6 +145,7 @@ func (m *MiscCollector) Collect(info abstract.StatisticsInformation, event *abst
 			switch {
 			case skill.Fatality:
 				misc.deathCount++
+				misc.streak = 0
 			case skill.Evaded:
 				misc.evadedCount++
 			case skill.Crit:
@@ -166,6 +177,111 @@ func (m *MiscCollector) Collect(info abstract.StatisticsInformation, event *abst
 	return nil
 }
 
+type MiscSnapshot struct {
+	Name          string
+	CoinsFound    int
+	CoinsReceived int
+	Kills         int
+	Crits         int
+	Deaths        int
+	// DeathlessStreak Seconds played since the last death
+	DeathlessStreak int
+}
+
+func (m *MiscCollector) Snapshot() any {
+	subjects := make([]MiscSnapshot, len(m.subjects))
+	for i, subject := range m.subjects {
+		subjects[i] = MiscSnapshot{
+			Name:            subject.name,
+			CoinsFound:      subject.coinsFound,
+			CoinsReceived:   subject.coinsReceived,
+			Kills:           subject.killedCount,
+			Crits:           subject.critCount,
+			Deaths:          subject.deathCount,
+			DeathlessStreak: int(subject.streak.Seconds()),
+		}
+	}
+
+	return subjects
+}
+
+func (m *MiscCollector) coinsOf(subjectName string) int {
+	for _, subject := range m.subjects {
+		if subject.name == subjectName {
+			return subject.coinsFound + subject.coinsReceived
+		}
+	}
+
+	return 0
+}
+
+func (m *MiscCollector) deathsOf(subjectName string) int {
+	for _, subject := range m.subjects {
+		if subject.name == subjectName {
+			return subject.deathCount
+		}
+	}
+
+	return 0
+}
+
+func (m *MiscCollector) Metrics(character string) []abstract.Metric {
+	for _, subject := range m.subjects {
+		if subject.name != character {
+			continue
+		}
+
+		return []abstract.Metric{
+			{
+				Name:  "pgct_deaths",
+				Help:  "Times the character died",
+				Type:  abstract.MetricCounter,
+				Value: float64(subject.deathCount),
+			},
+			{
+				Name:  "pgct_coins",
+				Help:  "Coins found and received",
+				Type:  abstract.MetricCounter,
+				Value: float64(subject.coinsFound + subject.coinsReceived),
+			},
+		}
+	}
+
+	return nil
+}
+
+func (m *MiscCollector) Summary(character string) string {
+	deaths, coins, found := 0, 0, false
+	for _, subject := range m.subjects {
+		if character == "" || subject.name == character {
+			deaths += subject.deathCount
+			coins += subject.coinsFound + subject.coinsReceived
+			found = true
+		}
+	}
+
+	if !found {
+		return ""
+	}
+
+	return fmt.Sprintf("Deaths: %d, coins: %v", deaths, utils.FormatNumber(coins))
+}
+
+// Records Longest time the character played without dying
+func (m *MiscCollector) Records(character string) []abstract.PersonalRecord {
+	for _, subject := range m.subjects {
+		if subject.name == character && subject.longestStreak > 0 {
+			return []abstract.PersonalRecord{{
+				Kind:  abstract.RecordDeathless,
+				Value: subject.longestStreak.Seconds(),
+				At:    subject.longestStreakAt,
+			}}
+		}
+	}
+
+	return nil
+}
+
 func (m *MiscCollector) TabName() string {
 	return "Misc"
 }
@@ -183,6 +299,7 @@ func addSubjectLabels[T any](subject *subjectiveMisc, label func(format string,
 		label("%d attacks enemies evaded", subject.enemyEvasions),
 		label("%d times enemy attacks did no damage", subject.enemyNoDamageCount),
 		label("%d times died", subject.deathCount),
+		label("Longest deathless streak of %v", subject.longestStreak.Round(time.Second)),
 	}
 }
 
@@ -291,3 +408,39 @@ func (m *MiscCollector) Export(state abstract.LayeredState) image.Image {
 
 	return drawing.ExportImage(state.Theme(), base, drawing.F64(800, 10000))
 }
+
+func (m *MiscCollector) TextTable(state abstract.LayeredState) abstract.TextTable {
+	subject := m.all
+	for _, possibleSubject := range m.subjects {
+		if possibleSubject.name == m.currentSubject {
+			subject = possibleSubject
+			break
+		}
+	}
+
+	return abstract.TextTable{
+		Title:      textTitle(m.TabName()),
+		Parameters: []string{fmt.Sprintf("Subject: %v", subjectChoice(m.currentSubject))},
+		Columns:    []string{"Stat"},
+		Rows: addSubjectLabels(&subject, func(format string, args ...any) []string {
+			return []string{fmt.Sprintf(format, args...)}
+		}),
+	}
+}
+
+func (m *MiscCollector) ChatLine(state abstract.LayeredState) string {
+	subject := m.all
+	for _, possibleSubject := range m.subjects {
+		if possibleSubject.name == m.currentSubject {
+			subject = possibleSubject
+			break
+		}
+	}
+
+	return chatLine(
+		fmt.Sprintf("Kills: %v", utils.FormatNumber(subject.killedCount)),
+		fmt.Sprintf("Crits: %v", utils.FormatNumber(subject.critCount)),
+		fmt.Sprintf("Deaths: %v", utils.FormatNumber(subject.deathCount)),
+		fmt.Sprintf("Coins: %v", utils.FormatNumber(subject.coinsFound+subject.coinsReceived)),
+	)
+}
@@ -0,0 +1,389 @@
+package collectors
+
+import (
+	"PGCombatTracker/abstract"
+	"PGCombatTracker/ui/components"
+	"PGCombatTracker/utils"
+	"PGCombatTracker/utils/drawing"
+	"cmp"
+	"fmt"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/widget"
+	"image"
+	"log"
+	"slices"
+	"strconv"
+	"time"
+)
+
+type petInstance struct {
+	from time.Time
+	to   time.Time
+}
+
+type petStats struct {
+	name  string
+	owner string
+
+	damageDealt     abstract.Vitals
+	damageTaken     abstract.Vitals
+	healingReceived abstract.Vitals
+	attacks         int
+	deaths          int
+
+	// Instances that are still alive, lifetime of dead ones is added to deadLifetime
+	instances    map[string]petInstance
+	deadLifetime time.Duration
+}
+
+func (p petStats) lifetime() time.Duration {
+	lifetime := p.deadLifetime
+	for _, instance := range p.instances {
+		lifetime += instance.to.Sub(instance.from)
+	}
+	return lifetime
+}
+
+func (p petStats) summons() int {
+	return len(p.instances) + p.deaths
+}
+
+func NewPetsCollector() *PetsCollector {
+	subjectDropdown, err := components.NewDropdown("Pet", subjectChoice(""))
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	return &PetsCollector{
+		subjectDropdown: subjectDropdown,
+		longFormatBool:  &widget.Bool{},
+	}
+}
+
+// PetsCollector Shows how pets and summons performed, with their owner when it is known
+type PetsCollector struct {
+	pets []petStats
+
+	currentSubject  string
+	subjectDropdown *components.Dropdown
+	longFormatBool  *widget.Bool
+}
+
+func (p *PetsCollector) Reset(info abstract.StatisticsInformation) {
+	p.pets = nil
+	p.subjectDropdown.SetOptions([]fmt.Stringer{subjectChoice("")})
+	p.currentSubject = ""
+}
+
+// updatePet Every owner gets their own row for a pet type, pets whose owner isn't known yet share one
+func (p *PetsCollector) updatePet(owner, name string, at time.Time, updateFunc func(pet petStats) petStats) {
+	petType := SplitOffId(name)
+
+	seen := func(pet petStats) petStats {
+		instance, ok := pet.instances[name]
+		if !ok {
+			instance.from = at
+		}
+		instance.to = at
+		pet.instances[name] = instance
+
+		return updateFunc(pet)
+	}
+
+	p.pets = utils.CreateUpdate(
+		p.pets,
+		func(pet petStats) bool {
+			return pet.name == petType && pet.owner == owner
+		},
+		func() petStats {
+			return seen(petStats{
+				name:      petType,
+				owner:     owner,
+				instances: make(map[string]petInstance),
+			})
+		},
+		seen,
+	)
+
+	slices.SortFunc(p.pets, func(a, b petStats) int {
+		return cmp.Compare(b.damageDealt.Total(), a.damageDealt.Total())
+	})
+
+	p.subjectDropdown.SetOptions(utils.CreateUpdate(
+		p.subjectDropdown.Options(),
+		func(item fmt.Stringer) bool {
+			casted := item.(subjectChoice)
+			return string(casted) == petType
+		},
+		func() fmt.Stringer {
+			return subjectChoice(petType)
+		},
+		func(stringer fmt.Stringer) fmt.Stringer {
+			return stringer
+		},
+	))
+}
+
+// petOwner Pets whose owner isn't known are still shown, the owner is empty for them
+func petOwner(info abstract.StatisticsInformation, name string) (string, bool) {
+	if name == "" {
+		return "", false
+	}
+
+	owner := info.OwnerOf(name)
+	return owner, owner != "" || info.IsPet(name)
+}
+
+func (p *PetsCollector) Tick(info abstract.StatisticsInformation, at time.Time) {
+
+}
+
+func (p *PetsCollector) Collect(info abstract.StatisticsInformation, event *abstract.ChatEvent) error {
+	switch contents := event.Contents.(type) {
+	case *abstract.SkillUse:
+		if owner, ok := petOwner(info, contents.Subject); ok {
+			p.updatePet(owner, contents.Subject, event.Time, func(pet petStats) petStats {
+				pet.attacks++
+				if contents.Damage != nil {
+					pet.damageDealt = pet.damageDealt.Add(*contents.Damage)
+				}
+				return pet
+			})
+		}
+
+		if owner, ok := petOwner(info, contents.Victim); ok {
+			p.updatePet(owner, contents.Victim, event.Time, func(pet petStats) petStats {
+				if contents.Damage != nil {
+					pet.damageTaken = pet.damageTaken.Add(*contents.Damage)
+				}
+
+				if contents.Fatality {
+					instance := pet.instances[contents.Victim]
+					pet.deadLifetime += instance.to.Sub(instance.from)
+					pet.deaths++
+					delete(pet.instances, contents.Victim)
+				}
+				return pet
+			})
+		}
+	case *abstract.IndirectDamage:
+		if owner, ok := petOwner(info, contents.Subject); ok {
+			p.updatePet(owner, contents.Subject, event.Time, func(pet petStats) petStats {
+				pet.damageTaken = pet.damageTaken.Add(contents.Damage.Abs())
+				return pet
+			})
+		}
+	case *abstract.Recovered:
+		if owner, ok := petOwner(info, contents.Subject); ok {
+			p.updatePet(owner, contents.Subject, event.Time, func(pet petStats) petStats {
+				pet.healingReceived = pet.healingReceived.Add(contents.Healed)
+				return pet
+			})
+		}
+	}
+
+	return nil
+}
+
+func (p *PetsCollector) TabName() string {
+	return "Pets"
+}
+
+func (p *PetsCollector) shownPets() []petStats {
+	if p.currentSubject == "" {
+		return p.pets
+	}
+
+	for _, pet := range p.pets {
+		if pet.name == p.currentSubject {
+			return []petStats{pet}
+		}
+	}
+
+	return nil
+}
+
+func (p *PetsCollector) maxDamage() int {
+	maxDamage := 0
+	for _, pet := range p.pets {
+		maxDamage = max(maxDamage, pet.damageDealt.Total())
+	}
+	return maxDamage
+}
+
+func addPetLabels[T any](pet *petStats, long bool, label func(format string, args ...any) T) []T {
+	owner := pet.owner
+	if owner == "" {
+		owner = "an unknown owner"
+	}
+
+	return []T{
+		label("Owned by %v, summoned %d times", owner, pet.summons()),
+		label("Took %v damage", pet.damageTaken.StringCL(long)),
+		label("Received %v healing", pet.healingReceived.StringCL(long)),
+		label("%d times died", pet.deaths),
+		label("Active for %v", pet.lifetime().Truncate(time.Second)),
+	}
+}
+
+func (p *PetsCollector) UI(state abstract.LayeredState) (layout.Widget, []layout.Widget) {
+	if p.subjectDropdown.Changed() {
+		p.currentSubject = string(p.subjectDropdown.Value.(subjectChoice))
+	}
+
+	topWidget := topBarSurface(func(gtx layout.Context) layout.Dimensions {
+		if p.longFormatBool.Update(gtx) {
+			gtx.Source.Execute(op.InvalidateCmd{})
+		}
+
+		return components.HorizontalWrap{
+			Alignment:   layout.Middle,
+			Spacing:     utils.CommonSpacing * 2,
+			LineSpacing: utils.CommonSpacing,
+		}.Layout(
+			gtx,
+			defaultDropdownStyle(state, p.subjectDropdown).Layout,
+			defaultCheckboxStyle(state, p.longFormatBool, "Use long numbers").Layout,
+		)
+	})
+
+	label := func(format string, args ...any) layout.Widget {
+		text := fmt.Sprintf(format, args...)
+
+		return func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{
+				Left:   utils.CommonSpacing * 2,
+				Bottom: utils.CommonSpacing,
+			}.Layout(gtx, defaultLabelStyle(state, text).Layout)
+		}
+	}
+
+	var widgets []layout.Widget
+
+	maxDamage := p.maxDamage()
+	for _, pet := range p.shownPets() {
+		widgets = append(widgets, drawUniversalBar(
+			state, pet.damageDealt,
+			pet.damageDealt.Total(), maxDamage, pet.attacks,
+			pet.name, "attacked %v times",
+			40, p.longFormatBool.Value,
+		))
+
+		widgets = append(widgets, addPetLabels(&pet, p.longFormatBool.Value, label)...)
+	}
+
+	return topWidget, widgets
+}
+
+func (p *PetsCollector) Export(state abstract.LayeredState) image.Image {
+	styledFonts := drawing.StyleFontPack(state.FontPack(), state.Theme().Fg)
+
+	var items []drawing.FlexChild
+
+	label := func(format string, args ...any) drawing.FlexChild {
+		text := fmt.Sprintf(format, args...)
+
+		return drawing.Rigid(drawing.UniformInset(drawing.CommonSpacing).Layout(
+			styledFonts.Smaller.Layout(text),
+		))
+	}
+
+	maxDamage := p.maxDamage()
+	for i, pet := range p.shownPets() {
+		if i != 0 {
+			items = append(items, drawing.FlexVSpacer(drawing.CommonSpacing))
+		}
+
+		items = append(items, drawing.Rigid(exportUniversalBar(
+			styledFonts, pet.damageDealt,
+			pet.damageDealt.Total(), maxDamage, pet.attacks,
+			pet.name, "attacked %v times",
+			p.longFormatBool.Value,
+		)))
+
+		items = append(items, addPetLabels(&pet, p.longFormatBool.Value, label)...)
+	}
+
+	body := drawing.Flex{
+		Axis:    layout.Vertical,
+		ExpandW: true,
+	}.Layout(
+		items...,
+	)
+
+	base := layoutTitle(
+		styledFonts,
+		p.TabName(),
+		drawing.HorizontalWrap{
+			Alignment:   layout.Middle,
+			Spacing:     drawing.CommonSpacing * 3,
+			LineSpacing: drawing.CommonSpacing,
+		}.Layout(
+			func(ltx drawing.Context) drawing.Result {
+				if p.currentSubject != "" {
+					return styledFonts.Smaller.Layout(fmt.Sprintf("Pet: %v", p.currentSubject))(ltx)
+				}
+
+				return styledFonts.Smaller.Layout("Pet: All")(ltx)
+			},
+		),
+		drawing.RoundedSurface(
+			utils.SecondBG,
+			body,
+		),
+	)
+
+	return drawing.ExportImage(state.Theme(), base, drawing.F64(800, 10000))
+}
+
+// textPets Shown pets with the most damage first
+func (p *PetsCollector) textPets() ([]petStats, int) {
+	pets := slices.Clone(p.shownPets())
+	slices.SortStableFunc(pets, func(a, b petStats) int {
+		return cmp.Compare(b.damageDealt.Total(), a.damageDealt.Total())
+	})
+
+	total := 0
+	for _, pet := range pets {
+		total += pet.damageDealt.Total()
+	}
+
+	return pets, total
+}
+
+func (p *PetsCollector) TextTable(state abstract.LayeredState) abstract.TextTable {
+	long := p.longFormatBool.Value
+	pets, total := p.textPets()
+
+	table := abstract.TextTable{
+		Title:      textTitle(p.TabName()),
+		Parameters: []string{fmt.Sprintf("Pet: %v", subjectChoice(p.currentSubject))},
+		Columns:    []string{"Pet", "Owner", "Attacks", "Damage", "Damage taken", "Deaths", "Share"},
+	}
+
+	for _, pet := range pets {
+		table.Rows = append(table.Rows, []string{
+			pet.name,
+			pet.owner,
+			strconv.Itoa(pet.attacks),
+			pet.damageDealt.StringCL(long),
+			pet.damageTaken.StringCL(long),
+			strconv.Itoa(pet.deaths),
+			textShare(pet.damageDealt.Total(), total),
+		})
+	}
+
+	return table
+}
+
+func (p *PetsCollector) ChatLine(state abstract.LayeredState) string {
+	pets, total := p.textPets()
+
+	top := ""
+	if len(pets) > 0 {
+		top = chatTop(pets[0].name, pets[0].damageDealt.Total(), total)
+	}
+
+	return chatLine(fmt.Sprintf("Pet damage: %v", utils.FormatNumber(total)), top)
+}
@@ -0,0 +1,384 @@
+package collectors
+
+import (
+	"PGCombatTracker/abstract"
+	"PGCombatTracker/ui/components"
+	"PGCombatTracker/utils"
+	"PGCombatTracker/utils/drawing"
+	"cmp"
+	"fmt"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/widget"
+	"image"
+	"slices"
+	"strconv"
+	"time"
+)
+
+type powerFlow struct {
+	name   string
+	amount int
+	power  abstract.Vitals
+}
+
+func updatePowerFlow(flows []powerFlow, name string, power int) []powerFlow {
+	flows = utils.CreateUpdate(
+		flows,
+		func(flow powerFlow) bool {
+			return flow.name == name
+		},
+		func() powerFlow {
+			return powerFlow{
+				name:   name,
+				amount: 1,
+				power:  abstract.Vitals{Power: power},
+			}
+		},
+		func(flow powerFlow) powerFlow {
+			flow.amount++
+			flow.power.Power += power
+			return flow
+		},
+	)
+
+	slices.SortFunc(flows, func(a, b powerFlow) int {
+		return cmp.Compare(b.power.Power, a.power.Power)
+	})
+
+	return flows
+}
+
+func NewPowerCollector(settings *abstract.Settings) *PowerCollector {
+	return &PowerCollector{
+		sources:        newHealingSources(settings),
+		netController:  components.NewTimeControllerOrCrash(components.NewTimeBasedChart("Net power per minute")),
+		restoredChart:  components.NewTimeBasedChart("Restored"),
+		drainedChart:   components.NewTimeBasedChart("Drained"),
+		longFormatBool: &widget.Bool{},
+	}
+}
+
+// PowerCollector Follows power of the character, where it came from and where it went
+type PowerCollector struct {
+	sources *healingSources
+
+	restored         abstract.Vitals
+	drained          abstract.Vitals
+	restoredBySource []powerFlow
+	drainedByEnemy   []powerFlow
+
+	netController *components.TimeController
+	restoredChart *components.TimeBasedChart
+	drainedChart  *components.TimeBasedChart
+	chartRange    components.DataRange
+
+	// Net power flow of the minute that is still going
+	minute    time.Time
+	minuteNet int
+
+	longFormatBool *widget.Bool
+}
+
+func (p *PowerCollector) Reset(info abstract.StatisticsInformation) {
+	p.sources = newHealingSources(info.Settings())
+	p.restored = abstract.Vitals{}
+	p.drained = abstract.Vitals{}
+	p.restoredBySource = nil
+	p.drainedByEnemy = nil
+	p.netController = components.NewTimeControllerOrCrash(components.NewTimeBasedChart("Net power per minute"))
+	p.restoredChart = components.NewTimeBasedChart("Restored")
+	p.drainedChart = components.NewTimeBasedChart("Drained")
+	p.chartRange = components.DataRange{}
+	p.minute = time.Time{}
+	p.minuteNet = 0
+}
+
+// advance Finishes every minute that passed before the time
+func (p *PowerCollector) advance(at time.Time) {
+	minute := at.Truncate(time.Minute)
+
+	if p.minute.IsZero() {
+		p.minute = minute
+		return
+	}
+
+	for p.minute.Before(minute) {
+		p.netController.Add(components.TimePoint{
+			Time:    p.minute,
+			Value:   p.minuteNet,
+			Details: abstract.Vitals{Power: p.minuteNet},
+		})
+
+		p.minuteNet = 0
+		p.minute = p.minute.Add(time.Minute)
+	}
+}
+
+// Finish Adds the minute that was still going when the file ended
+func (p *PowerCollector) Finish(info abstract.StatisticsInformation) {
+	if !p.minute.IsZero() {
+		p.advance(p.minute.Add(time.Minute))
+	}
+}
+
+func (p *PowerCollector) addPoints(at time.Time) {
+	p.restoredChart.Add(components.TimePoint{
+		Time:    at,
+		Value:   p.restored.Power,
+		Details: p.restored,
+	})
+	p.drainedChart.Add(components.TimePoint{
+		Time:    at,
+		Value:   p.drained.Power,
+		Details: p.drained,
+	})
+	p.chartRange = p.chartRange.Expand(max(p.restored.Power, p.drained.Power))
+}
+
+func (p *PowerCollector) Tick(info abstract.StatisticsInformation, at time.Time) {
+	p.advance(at)
+}
+
+func (p *PowerCollector) Collect(info abstract.StatisticsInformation, event *abstract.ChatEvent) error {
+	p.advance(event.Time)
+
+	switch contents := event.Contents.(type) {
+	case *abstract.Recovered:
+		if contents.Subject != info.CurrentUsername() || contents.Healed.Power <= 0 {
+			return nil
+		}
+
+		source := p.sources.Attribute(info, event)
+
+		p.restored.Power += contents.Healed.Power
+		p.minuteNet += contents.Healed.Power
+		p.restoredBySource = updatePowerFlow(p.restoredBySource, source.String(), contents.Healed.Power)
+	case *abstract.SkillUse:
+		p.sources.Observe(info, event)
+
+		if contents.Victim != info.CurrentUsername() || contents.Damage == nil || contents.Damage.Power <= 0 {
+			return nil
+		}
+
+		p.drained.Power += contents.Damage.Power
+		p.minuteNet -= contents.Damage.Power
+		p.drainedByEnemy = updatePowerFlow(p.drainedByEnemy, SplitOffId(contents.Subject), contents.Damage.Power)
+	case *abstract.IndirectDamage:
+		power := utils.AbsInt(contents.Damage.Power)
+		if contents.Subject != info.CurrentUsername() || power == 0 {
+			return nil
+		}
+
+		p.drained.Power += power
+		p.minuteNet -= power
+		p.drainedByEnemy = updatePowerFlow(p.drainedByEnemy, "Indirect Damage", power)
+	default:
+		return nil
+	}
+
+	p.addPoints(event.Time)
+
+	return nil
+}
+
+func (p *PowerCollector) TabName() string {
+	return "Power"
+}
+
+func (p *PowerCollector) UI(state abstract.LayeredState) (layout.Widget, []layout.Widget) {
+	long := p.longFormatBool.Value
+
+	topWidget := topBarSurface(func(gtx layout.Context) layout.Dimensions {
+		if p.longFormatBool.Update(gtx) {
+			gtx.Source.Execute(op.InvalidateCmd{})
+		}
+
+		return layout.Flex{
+			Axis: layout.Vertical,
+		}.Layout(
+			gtx,
+			layout.Rigid(defaultCheckboxStyle(state, p.longFormatBool, "Use long numbers").Layout),
+			utils.FlexSpacerH(utils.CommonSpacing),
+			layout.Rigid(components.StyleTimeController(state.Theme(), p.netController).Layout),
+		)
+	})
+
+	heading := func(text string) layout.Widget {
+		return func(gtx layout.Context) layout.Dimensions {
+			return layout.UniformInset(utils.CommonSpacing).Layout(
+				gtx,
+				func(gtx layout.Context) layout.Dimensions {
+					style := defaultLabelStyle(state, text)
+					style.TextSize = 14
+					return style.Layout(gtx)
+				},
+			)
+		}
+	}
+
+	chart := func(chart *components.TimeBasedChart, total abstract.Vitals) layout.Widget {
+		chart.DisplayTimeFrame = p.netController.CurrentTimeFrame
+		chart.DisplayValueRange = p.chartRange
+
+		style := components.StyleTimeBasedChart(state.Theme(), chart)
+		style.Color = components.StringToColor(chart.Name)
+		style.LongFormat = long
+
+		return drawUniversalStatsText(
+			state, total,
+			style.Layout, 0,
+			chart.Name, "",
+			100, long,
+		)
+	}
+
+	widgets := []layout.Widget{
+		chart(p.restoredChart, p.restored),
+		chart(p.drainedChart, p.drained),
+		heading(fmt.Sprintf("Net power: %v", abstract.Vitals{Power: p.restored.Power - p.drained.Power}.StringCL(long))),
+		heading("Restored by"),
+	}
+
+	for _, flow := range p.restoredBySource {
+		widgets = append(widgets, drawUniversalBar(
+			state, flow.power,
+			flow.power.Power, p.restored.Power, flow.amount,
+			flow.name, "restored %v times",
+			40, long,
+		))
+	}
+
+	widgets = append(widgets, heading("Drained by"))
+
+	for _, flow := range p.drainedByEnemy {
+		widgets = append(widgets, drawUniversalBar(
+			state, flow.power,
+			flow.power.Power, p.drained.Power, flow.amount,
+			flow.name, "drained %v times",
+			40, long,
+		))
+	}
+
+	return topWidget, widgets
+}
+
+func (p *PowerCollector) Export(state abstract.LayeredState) image.Image {
+	long := p.longFormatBool.Value
+	styledFonts := drawing.StyleFontPack(state.FontPack(), state.Theme().Fg)
+
+	heading := func(text string) drawing.FlexChild {
+		return drawing.Rigid(drawing.UniformInset(drawing.CommonSpacing).Layout(
+			styledFonts.Body.Layout(text),
+		))
+	}
+
+	chart := func(chart *components.TimeBasedChart, total abstract.Vitals) drawing.FlexChild {
+		chart.DisplayTimeFrame = p.netController.CurrentTimeFrame
+		chart.DisplayValueRange = p.chartRange
+
+		style := drawing.StyleAreaChart(chart, components.StringToColor(chart.Name))
+		style.MinHeight = 200
+
+		return drawing.Rigid(exportUniversalStatsTextAsStack(
+			styledFonts, total,
+			style.Layout(), 0,
+			chart.Name, "",
+			long,
+		))
+	}
+
+	items := []drawing.FlexChild{
+		drawing.Rigid(exportTimeFrame(styledFonts, p.netController.CurrentTimeFrame)),
+		drawing.FlexVSpacer(drawing.CommonSpacing),
+		chart(p.restoredChart, p.restored),
+		drawing.FlexVSpacer(drawing.CommonSpacing),
+		chart(p.drainedChart, p.drained),
+		heading(fmt.Sprintf("Net power: %v", abstract.Vitals{Power: p.restored.Power - p.drained.Power}.StringCL(long))),
+		heading("Restored by"),
+	}
+
+	flowBars := func(flows []powerFlow, total int, amountFormat string) {
+		for _, flow := range flows {
+			items = append(
+				items,
+				drawing.Rigid(exportUniversalBar(
+					styledFonts, flow.power,
+					flow.power.Power, total, flow.amount,
+					flow.name, amountFormat,
+					long,
+				)),
+				drawing.FlexVSpacer(drawing.CommonSpacing),
+			)
+		}
+	}
+
+	flowBars(p.restoredBySource, p.restored.Power, "restored %v times")
+	items = append(items, heading("Drained by"))
+	flowBars(p.drainedByEnemy, p.drained.Power, "drained %v times")
+
+	body := drawing.Flex{
+		Axis:    layout.Vertical,
+		ExpandW: true,
+	}.Layout(
+		items...,
+	)
+
+	base := layoutTitle(
+		styledFonts,
+		p.TabName(),
+		styledFonts.Smaller.Layout("Power of the current character"),
+		drawing.RoundedSurface(
+			utils.SecondBG,
+			body,
+		),
+	)
+
+	return drawing.ExportImage(state.Theme(), base, drawing.F64(800, 10000))
+}
+
+func (p *PowerCollector) TextTable(state abstract.LayeredState) abstract.TextTable {
+	long := p.longFormatBool.Value
+
+	table := abstract.TextTable{
+		Title: textTitle(p.TabName()),
+		Parameters: []string{
+			"Power of the current character",
+			fmt.Sprintf("Restored: %v", p.restored.StringCL(long)),
+			fmt.Sprintf("Drained: %v", p.drained.StringCL(long)),
+			fmt.Sprintf("Net power: %v", abstract.Vitals{Power: p.restored.Power - p.drained.Power}.StringCL(long)),
+		},
+		TimeFrame: textTimeFrame(p.netController.CurrentTimeFrame),
+		Columns:   []string{"Source", "Times", "Power", "Share"},
+	}
+
+	flowRows := func(heading string, flows []powerFlow, total int) {
+		table.Rows = append(table.Rows, []string{heading})
+		for _, flow := range flows {
+			table.Rows = append(table.Rows, []string{
+				flow.name,
+				strconv.Itoa(flow.amount),
+				flow.power.StringCL(long),
+				textShare(flow.power.Power, total),
+			})
+		}
+	}
+
+	flowRows("Restored by", p.restoredBySource, p.restored.Power)
+	flowRows("Drained by", p.drainedByEnemy, p.drained.Power)
+
+	return table
+}
+
+func (p *PowerCollector) ChatLine(state abstract.LayeredState) string {
+	top := ""
+	if len(p.restoredBySource) > 0 {
+		top = chatTop(p.restoredBySource[0].name, p.restoredBySource[0].power.Power, p.restored.Power)
+	}
+
+	return chatLine(
+		fmt.Sprintf("Power restored: %v", utils.FormatNumber(p.restored.Power)),
+		fmt.Sprintf("Drained: %v", utils.FormatNumber(p.drained.Power)),
+		top,
+	)
+}
@@ -0,0 +1,36 @@
+package collectors
+
+import "time"
+
+// streakIdleLimit Longer pauses between events are time spent offline or away, they don't count towards the deathless streak
+const streakIdleLimit = 10 * time.Minute
+
+type timedValue struct {
+	value int
+	at    time.Time
+}
+
+// slidingWindow Sum of values over the last window of time, keeps the best sum it ever reached
+type slidingWindow struct {
+	values []timedValue
+	sum    int
+	best   int
+	bestAt time.Time
+}
+
+func (w *slidingWindow) add(window time.Duration, value int, at time.Time) {
+	w.values = append(w.values, timedValue{value: value, at: at})
+	w.sum += value
+
+	expired := 0
+	for expired < len(w.values)-1 && at.Sub(w.values[expired].at) >= window {
+		w.sum -= w.values[expired].value
+		expired++
+	}
+	w.values = w.values[expired:]
+
+	if w.sum > w.best {
+		w.best = w.sum
+		w.bestAt = at
+	}
+}
@@ -0,0 +1,332 @@
+package collectors
+
+import (
+	"PGCombatTracker/abstract"
+	"PGCombatTracker/ui/components"
+	"PGCombatTracker/utils"
+	"PGCombatTracker/utils/drawing"
+	"cmp"
+	"fmt"
+	"gioui.org/layout"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	minSequenceLength = 2
+	maxSequenceLength = 4
+	shownSequences    = 15
+	shownGaps         = 10
+)
+
+// inferredCooldown Shortest time it took the character to use the skill again
+type inferredCooldown time.Duration
+
+func (c inferredCooldown) StringCL(long bool) string {
+	if c == 0 {
+		return "not reused"
+	}
+	return fmt.Sprintf("reused after %v", ttkValue(c).StringCL(long))
+}
+
+type sequenceCounter int
+
+func (counter sequenceCounter) StringCL(long bool) string {
+	if long {
+		return fmt.Sprintf("%d time(s)", counter)
+	}
+	return fmt.Sprintf("%v time(s)", utils.FormatNumber(int(counter)))
+}
+
+type rotationLane struct {
+	skill    string
+	casts    []components.TimeFrame
+	cooldown time.Duration
+}
+
+// rotationGap Pause in combat, after the skill that was used before it
+type rotationGap struct {
+	after string
+	span  components.TimeFrame
+}
+
+func (g rotationGap) Length() time.Duration {
+	return g.span.To.Sub(g.span.From)
+}
+
+func (g rotationGap) String() string {
+	return fmt.Sprintf("After %v at %v", g.after, g.span.From.Format(time.TimeOnly))
+}
+
+type rotationSequence struct {
+	skills string
+	amount int
+}
+
+// rotation Order in which the character used skills, to see what sequences and pauses a rotation has
+type rotation struct {
+	lanes     []rotationLane
+	sequences []rotationSequence
+	gaps      []rotationGap
+	gapSpans  []components.TimeFrame
+	idle      time.Duration
+
+	// Skills used in the current fight, no longer than the longest sequence
+	recent    []string
+	lastSkill string
+	lastUsed  time.Time
+
+	totalCasts     int
+	timeController *components.TimeController
+}
+
+func newRotation() *rotation {
+	return &rotation{
+		timeController: components.NewTimeControllerOrCrash(components.NewTimeBasedChart("Casts")),
+	}
+}
+
+// Add Skill uses that happen at the same time are the same cast hitting several targets
+func (r *rotation) Add(info abstract.StatisticsInformation, skill string, at time.Time) {
+	if skill == r.lastSkill && at.Equal(r.lastUsed) {
+		return
+	}
+
+	if !r.lastUsed.IsZero() {
+		pause := at.Sub(r.lastUsed)
+		combatWindow := time.Duration(info.Settings().SecondsUntilDPSReset) * time.Second
+		idleThreshold := time.Duration(info.Settings().SecondsToCountAsIdle) * time.Second
+
+		if pause > combatWindow {
+			r.recent = nil
+		} else if pause > idleThreshold {
+			gap := rotationGap{
+				after: r.lastSkill,
+				span: components.TimeFrame{
+					From: r.lastUsed,
+					To:   at,
+				},
+			}
+
+			r.gaps = append(r.gaps, gap)
+			r.gapSpans = append(r.gapSpans, gap.span)
+			r.idle += pause
+		}
+	}
+
+	r.lastSkill = skill
+	r.lastUsed = at
+
+	r.lanes = utils.CreateUpdate(
+		r.lanes,
+		func(lane rotationLane) bool {
+			return lane.skill == skill
+		},
+		func() rotationLane {
+			return rotationLane{
+				skill: skill,
+				casts: []components.TimeFrame{{From: at, To: at}},
+			}
+		},
+		func(lane rotationLane) rotationLane {
+			interval := at.Sub(lane.casts[len(lane.casts)-1].From)
+			if interval > 0 && (lane.cooldown == 0 || interval < lane.cooldown) {
+				lane.cooldown = interval
+			}
+
+			lane.casts = append(lane.casts, components.TimeFrame{From: at, To: at})
+			return lane
+		},
+	)
+	slices.SortStableFunc(r.lanes, func(a, b rotationLane) int {
+		return cmp.Compare(len(b.casts), len(a.casts))
+	})
+
+	r.recent = append(r.recent, skill)
+	if len(r.recent) > maxSequenceLength {
+		r.recent = r.recent[len(r.recent)-maxSequenceLength:]
+	}
+
+	for length := minSequenceLength; length <= len(r.recent); length++ {
+		skills := strings.Join(r.recent[len(r.recent)-length:], " → ")
+
+		r.sequences = utils.CreateUpdate(
+			r.sequences,
+			func(sequence rotationSequence) bool {
+				return sequence.skills == skills
+			},
+			func() rotationSequence {
+				return rotationSequence{
+					skills: skills,
+					amount: 1,
+				}
+			},
+			func(sequence rotationSequence) rotationSequence {
+				sequence.amount++
+				return sequence
+			},
+		)
+	}
+	slices.SortStableFunc(r.sequences, func(a, b rotationSequence) int {
+		return cmp.Compare(b.amount, a.amount)
+	})
+
+	r.totalCasts++
+	r.timeController.Add(components.TimePoint{
+		Time:    at,
+		Value:   r.totalCasts,
+		Details: skillUseCounter(r.totalCasts),
+	})
+}
+
+// longestGaps Gaps sorted from longest to shortest, limited to how many are shown
+func (r *rotation) longestGaps() []rotationGap {
+	gaps := slices.Clone(r.gaps)
+	slices.SortStableFunc(gaps, func(a, b rotationGap) int {
+		return cmp.Compare(b.Length(), a.Length())
+	})
+	return gaps[:min(len(gaps), shownGaps)]
+}
+
+func (r *rotation) topSequences() []rotationSequence {
+	return r.sequences[:min(len(r.sequences), shownSequences)]
+}
+
+func (r *rotation) UI(state abstract.LayeredState, long bool) []layout.Widget {
+	frame := r.timeController.CurrentTimeFrame
+
+	heading := func(text string) layout.Widget {
+		return func(gtx layout.Context) layout.Dimensions {
+			return layout.UniformInset(utils.CommonSpacing).Layout(
+				gtx,
+				func(gtx layout.Context) layout.Dimensions {
+					style := defaultLabelStyle(state, text)
+					style.TextSize = 14
+					return style.Layout(gtx)
+				},
+			)
+		}
+	}
+
+	widgets := []layout.Widget{
+		heading("Timeline"),
+	}
+
+	for _, lane := range r.lanes {
+		widgets = append(widgets, drawUniversalStatsText(
+			state, inferredCooldown(lane.cooldown),
+			components.TimelineLaneWidget(components.StringToColor(lane.skill), 40, frame, lane.casts...),
+			len(lane.casts),
+			lane.skill, "used %v times",
+			40, long,
+		))
+	}
+
+	widgets = append(widgets, drawUniversalStatsText(
+		state, ttkValue(r.idle),
+		components.TimelineLaneWidget(components.StringToColor("Idle in combat"), 40, frame, r.gapSpans...),
+		len(r.gaps),
+		"Idle in combat", "%v gaps",
+		40, long,
+	))
+
+	widgets = append(widgets, heading("Common sequences"))
+
+	sequences := r.topSequences()
+	for _, sequence := range sequences {
+		widgets = append(widgets, drawUniversalBar(
+			state, sequenceCounter(sequence.amount),
+			sequence.amount, sequences[0].amount, 0,
+			sequence.skills, "",
+			25, long,
+		))
+	}
+
+	widgets = append(widgets, heading("Longest gaps"))
+
+	gaps := r.longestGaps()
+	for _, gap := range gaps {
+		widgets = append(widgets, drawUniversalBar(
+			state, ttkValue(gap.Length()),
+			int(gap.Length()), int(gaps[0].Length()), 0,
+			gap.String(), "",
+			25, long,
+		))
+	}
+
+	return widgets
+}
+
+func (r *rotation) Export(styledFonts *drawing.StyledFontPack, long bool) []drawing.FlexChild {
+	frame := r.timeController.CurrentTimeFrame
+
+	heading := func(text string) drawing.FlexChild {
+		return drawing.Rigid(drawing.UniformInset(drawing.CommonSpacing).Layout(
+			styledFonts.Body.Layout(text),
+		))
+	}
+
+	items := []drawing.FlexChild{
+		drawing.Rigid(exportTimeFrame(styledFonts, frame)),
+		heading("Timeline"),
+	}
+
+	for _, lane := range r.lanes {
+		items = append(
+			items,
+			drawing.Rigid(exportUniversalStatsTextAsSurface(
+				styledFonts, inferredCooldown(lane.cooldown),
+				timelineLane(components.StringToColor(lane.skill), frame, lane.casts...),
+				len(lane.casts),
+				lane.skill, "used %v times",
+				long,
+			)),
+			drawing.FlexVSpacer(drawing.CommonSpacing),
+		)
+	}
+
+	items = append(
+		items,
+		drawing.Rigid(exportUniversalStatsTextAsSurface(
+			styledFonts, ttkValue(r.idle),
+			timelineLane(components.StringToColor("Idle in combat"), frame, r.gapSpans...),
+			len(r.gaps),
+			"Idle in combat", "%v gaps",
+			long,
+		)),
+		heading("Common sequences"),
+	)
+
+	sequences := r.topSequences()
+	for _, sequence := range sequences {
+		items = append(
+			items,
+			drawing.Rigid(exportUniversalBar(
+				styledFonts, sequenceCounter(sequence.amount),
+				sequence.amount, sequences[0].amount, 0,
+				sequence.skills, "",
+				long,
+			)),
+			drawing.FlexVSpacer(drawing.CommonSpacing),
+		)
+	}
+
+	items = append(items, heading("Longest gaps"))
+
+	gaps := r.longestGaps()
+	for _, gap := range gaps {
+		items = append(
+			items,
+			drawing.Rigid(exportUniversalBar(
+				styledFonts, ttkValue(gap.Length()),
+				int(gap.Length()), int(gaps[0].Length()), 0,
+				gap.String(), "",
+				long,
+			)),
+			drawing.FlexVSpacer(drawing.CommonSpacing),
+		)
+	}
+
+	return items
+}
@@ -0,0 +1,69 @@
+package collectors
+
+import (
+	"PGCombatTracker/abstract"
+	"time"
+)
+
+// characterTotals Everything the character did so far according to their own collectors, pets included
+func (stats *StatisticsCollector) characterTotals(character string) abstract.SessionSummary {
+	totals := abstract.SessionSummary{Character: character}
+
+	for _, set := range stats.characters {
+		if set.name != character {
+			continue
+		}
+
+		for _, collector := range set.collectors {
+			switch c := collector.(type) {
+			case *DamageDealtCollector:
+				totals.DamageDealt, totals.ActiveTime = c.dealtWithPets(stats, character)
+			case *DamageTakenCollector:
+				totals.DamageTaken = c.takenBy(character)
+			case *LevelingCollector:
+				totals.XPGained = c.totalXPOf(character)
+			case *MiscCollector:
+				totals.Deaths = c.deathsOf(character)
+				totals.Coins = c.coinsOf(character)
+			}
+		}
+	}
+
+	return totals
+}
+
+// startSummary Finishes the login that was summarized and starts a new one, totals of now are subtracted when it ends
+func (stats *StatisticsCollector) startSummary(character string, at time.Time) {
+	stats.finishSummary()
+
+	start := stats.characterTotals(character)
+	start.From = at
+	start.To = at
+	stats.summaryStart = &start
+}
+
+func (stats *StatisticsCollector) finishSummary() {
+	start := stats.summaryStart
+	if start == nil {
+		return
+	}
+
+	end := stats.characterTotals(start.Character)
+	stats.summaries = append(stats.summaries, abstract.SessionSummary{
+		Character:   start.Character,
+		From:        start.From,
+		To:          start.To,
+		ActiveTime:  end.ActiveTime - start.ActiveTime,
+		DamageDealt: end.DamageDealt - start.DamageDealt,
+		DamageTaken: end.DamageTaken - start.DamageTaken,
+		Deaths:      end.Deaths - start.Deaths,
+		XPGained:    end.XPGained - start.XPGained,
+		Coins:       end.Coins - start.Coins,
+	})
+	stats.summaryStart = nil
+}
+
+// SessionSummaries Summary of every login, the last one is only added once the file was read to the end
+func (stats *StatisticsCollector) SessionSummaries() []abstract.SessionSummary {
+	return stats.summaries
+}
@@ -14,7 +14,7 @@ import (
 	"image"
 	"log"
 	"slices"
-	"strings"
+	"strconv"
 	"time"
 )
 
@@ -33,6 +33,8 @@ type subjectiveSkillUses struct {
 	totalUsed      int
 	maxUsed        int
 	maxRange       components.DataRange
+	// zones Uses grouped by the zone the skills were used in, kept sorted by uses
+	zones []skillUse
 }
 
 type skillUseType int
@@ -67,6 +69,35 @@ func (s skillUseSubject) EqualTo(other skillUseSubject) bool {
 	return s.ty == other.ty && s.name == other.name
 }
 
+// addZoneUse Adds a skill use to the zone it happened in, zones are kept sorted by uses
+func addZoneUse(zones []skillUse, zone string, damage abstract.Vitals, at time.Time) []skillUse {
+	update := func(use skillUse) skillUse {
+		if use.lastUsed != at {
+			use.amount++
+		}
+		use.damage = use.damage.Add(damage)
+		use.lastUsed = at
+		return use
+	}
+
+	zones = utils.CreateUpdate(
+		zones,
+		func(use skillUse) bool {
+			return use.name == zone
+		},
+		func() skillUse {
+			return update(skillUse{name: zone})
+		},
+		update,
+	)
+
+	slices.SortFunc(zones, func(a, b skillUse) int {
+		return cmp.Compare(b.amount, a.amount)
+	})
+
+	return zones
+}
+
 func freshSubjectiveSkillUse() subjectiveSkillUses {
 	return subjectiveSkillUses{
 		timeController: components.NewTimeControllerOrCrash(components.NewTimeBasedChart("Total")),
@@ -95,6 +126,7 @@ func NewSkillsCollector() *SkillsCollector {
 		DisplayBars,
 		DisplayPie,
 		DisplayGraphs,
+		DisplayRotation,
 	)
 	if err != nil {
 		log.Fatalln(err)
@@ -105,8 +137,11 @@ func NewSkillsCollector() *SkillsCollector {
 		enemies: freshSubjectiveSkillUse(),
 		all:     freshSubjectiveSkillUse(),
 
+		rotation: newRotation(),
+
 		subjectDropdown: subjectDropdown,
 		displayDropdown: displayDropdown,
+		groupDropdown:   newGroupDropdown(GroupNone, GroupSkill, GroupCategory, GroupZone),
 		longFormatBool:  &widget.Bool{},
 	}
 }
@@ -117,10 +152,15 @@ type SkillsCollector struct {
 	all      subjectiveSkillUses
 	subjects []subjectiveSkillUses
 
+	// rotation Only skills of the current character
+	rotation *rotation
+
 	currentSubject  skillUseSubject
 	currentDisplay  displayChoice
+	currentGroup    groupChoice
 	subjectDropdown *components.Dropdown
 	displayDropdown *components.Dropdown
+	groupDropdown   *components.Dropdown
 	longFormatBool  *widget.Bool
 }
 
@@ -129,6 +169,7 @@ func (s *SkillsCollector) Reset(info abstract.StatisticsInformation) {
 	s.enemies = freshSubjectiveSkillUse()
 	s.all = freshSubjectiveSkillUse()
 	s.subjects = nil
+	s.rotation = newRotation()
 
 	s.currentSubject = skillUseSubject{}
 	s.subjectDropdown.SetOptions([]fmt.Stringer{
@@ -144,26 +185,6 @@ func (s *SkillsCollector) Reset(info abstract.StatisticsInformation) {
 	})
 }
 
-func (s *SkillsCollector) isAlly(info abstract.StatisticsInformation, subject, skill string) bool {
-	if subject == info.CurrentUsername() {
-		return true
-	}
-
-	if strings.Contains(skill, "(Pet)") {
-		return true
-	}
-
-	lowTrimmedSubject := strings.ToLower(strings.TrimSpace(SplitOffId(subject)))
-
-	for _, expectedName := range info.Settings().EntitiesThatCountAsPets {
-		if lowTrimmedSubject == strings.ToLower(strings.TrimSpace(expectedName)) {
-			return true
-		}
-	}
-
-	return false
-}
-
 type skillUseCounter int
 
 func (counter skillUseCounter) StringCL(long bool) string {
@@ -249,6 +270,7 @@ func (s *SkillsCollector) ingestSkillUse(info abstract.StatisticsInformation, ev
 			updateSkillUse,
 		)
 		stats.totalUsed += 1
+		stats.zones = addZoneUse(stats.zones, zoneOf(info), damage, event.Time)
 		stats.timeController.Add(components.TimePoint{
 			Time:  event.Time,
 			Value: stats.totalUsed,
@@ -260,7 +282,11 @@ func (s *SkillsCollector) ingestSkillUse(info abstract.StatisticsInformation, ev
 
 	processSubjectiveSkillUses(&s.all)
 
-	isAlly := s.isAlly(info, skill.Subject, skill.Skill)
+	if skill.Subject == info.CurrentUsername() {
+		s.rotation.Add(info, skillName, event.Time)
+	}
+
+	isAlly := info.IsAlly(skill.Subject, skill.Skill)
 	if isAlly {
 		processSubjectiveSkillUses(&s.allies)
 	} else {
@@ -292,6 +318,7 @@ func (s *SkillsCollector) ingestSkillUse(info abstract.StatisticsInformation, ev
 				},
 				timeController: timeController,
 				maxRange:       components.DataRange{Max: 1},
+				zones:          addZoneUse(nil, zoneOf(info), damage, event.Time),
 			}
 		},
 		func(uses subjectiveSkillUses) subjectiveSkillUses {
@@ -336,6 +363,80 @@ func (s *SkillsCollector) TabName() string {
 	return "Skill Uses"
 }
 
+func (s *SkillsCollector) currentUses() subjectiveSkillUses {
+	var uses subjectiveSkillUses
+	switch s.currentSubject.ty {
+	case UseAllies:
+		uses = s.allies
+	case UseEnemies:
+		uses = s.enemies
+	case UseAll:
+		uses = s.all
+	case UseCustom:
+		for _, potentialUses := range s.subjects {
+			if potentialUses.name == s.currentSubject.name {
+				uses = potentialUses
+				break
+			}
+		}
+	}
+
+	return uses
+}
+
+func (s *SkillsCollector) ComparisonRows() []abstract.ComparisonRow {
+	uses := s.currentUses()
+
+	rows := []abstract.ComparisonRow{
+		{Metric: "Uses", Name: "Total", Value: float64(uses.totalUsed)},
+	}
+
+	for _, skill := range uses.skills {
+		rows = append(rows, abstract.ComparisonRow{
+			Metric: "Uses",
+			Name:   skill.name,
+			Value:  float64(skill.amount),
+		})
+	}
+
+	return rows
+}
+
+// grouped Skills merged by the current grouping, used by bars and pie only
+func (s *SkillsCollector) grouped(state abstract.GlobalState, uses subjectiveSkillUses) ([]skillUse, int) {
+	if s.currentGroup == GroupZone {
+		if len(uses.zones) == 0 {
+			return uses.zones, 0
+		}
+
+		return uses.zones, uses.zones[0].amount
+	}
+
+	skills := groupItems(
+		state.SkillCategories(),
+		s.currentGroup,
+		uses.skills,
+		func(use skillUse) string {
+			return use.name
+		},
+		func(group string, grouped skillUse, use skillUse) skillUse {
+			grouped.name = group
+			grouped.amount += use.amount
+			grouped.damage = grouped.damage.Add(use.damage)
+			return grouped
+		},
+		func(a, b skillUse) int {
+			return cmp.Compare(b.amount, a.amount)
+		},
+	)
+
+	if len(skills) == 0 {
+		return skills, uses.maxUsed
+	}
+
+	return skills, skills[0].amount
+}
+
 func (s *SkillsCollector) drawWidget(state abstract.LayeredState, skill skillUse, widget layout.Widget, size unit.Dp) layout.Widget {
 	return drawUniversalStatsText(
 		state, skill.damage,
@@ -363,23 +464,12 @@ func (s *SkillsCollector) UI(state abstract.LayeredState) (layout.Widget, []layo
 		s.currentDisplay = s.displayDropdown.Value.(displayChoice)
 	}
 
-	var uses subjectiveSkillUses
-	switch s.currentSubject.ty {
-	case UseAllies:
-		uses = s.allies
-	case UseEnemies:
-		uses = s.enemies
-	case UseAll:
-		uses = s.all
-	case UseCustom:
-		for _, potentialUses := range s.subjects {
-			if potentialUses.name == s.currentSubject.name {
-				uses = potentialUses
-				break
-			}
-		}
+	if s.groupDropdown.Changed() {
+		s.currentGroup = s.groupDropdown.Value.(groupChoice)
 	}
 
+	uses := s.currentUses()
+
 	topWidget := topBarSurface(func(gtx layout.Context) layout.Dimensions {
 		if s.longFormatBool.Update(gtx) {
 			gtx.Source.Execute(op.InvalidateCmd{})
@@ -399,6 +489,12 @@ func (s *SkillsCollector) UI(state abstract.LayeredState) (layout.Widget, []layo
 					defaultDropdownStyle(state, s.subjectDropdown).Layout,
 					defaultCheckboxStyle(state, s.longFormatBool, "Use long numbers").Layout,
 					defaultDropdownStyle(state, s.displayDropdown).Layout,
+					func(gtx layout.Context) layout.Dimensions {
+						if s.currentDisplay != DisplayBars && s.currentDisplay != DisplayPie {
+							return layout.Dimensions{}
+						}
+						return defaultDropdownStyle(state, s.groupDropdown).Layout(gtx)
+					},
 				)
 			}),
 			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
@@ -412,6 +508,16 @@ func (s *SkillsCollector) UI(state abstract.LayeredState) (layout.Widget, []layo
 					)
 				}
 
+				if s.currentDisplay == DisplayRotation {
+					return layout.Flex{
+						Axis: layout.Vertical,
+					}.Layout(
+						gtx,
+						utils.FlexSpacerH(utils.CommonSpacing),
+						layout.Rigid(components.StyleTimeController(state.Theme(), s.rotation.timeController).Layout),
+					)
+				}
+
 				return layout.Dimensions{}
 			}),
 		)
@@ -421,14 +527,16 @@ func (s *SkillsCollector) UI(state abstract.LayeredState) (layout.Widget, []layo
 
 	switch s.currentDisplay {
 	case DisplayBars:
-		for _, skill := range uses.skills {
-			widgets = append(widgets, s.drawBar(state, skill, uses.maxUsed, 40))
+		skills, maxUsed := s.grouped(state, uses)
+		for _, skill := range skills {
+			widgets = append(widgets, s.drawBar(state, skill, maxUsed, 40))
 		}
 	case DisplayPie:
+		skills, _ := s.grouped(state, uses)
 		widgets = append(widgets, func(gtx layout.Context) layout.Dimensions {
 			var totalValue int
-			pieItems := make([]components.PieChartItem, 0, max(1, len(uses.skills)))
-			for _, skill := range uses.skills {
+			pieItems := make([]components.PieChartItem, 0, max(1, len(skills)))
+			for _, skill := range skills {
 				pieItems = append(pieItems, components.PieChartItem{
 					Name:    skill.name,
 					Value:   skill.amount,
@@ -459,6 +567,8 @@ func (s *SkillsCollector) UI(state abstract.LayeredState) (layout.Widget, []layo
 
 			widgets = append(widgets, s.drawWidget(state, skill, chartStyle.Layout, 100))
 		}
+	case DisplayRotation:
+		widgets = s.rotation.UI(state, s.longFormatBool.Value)
 	}
 
 	return topWidget, widgets
@@ -483,22 +593,7 @@ func (s *SkillsCollector) exportBar(styledFonts *drawing.StyledFontPack, skill s
 }
 
 func (s *SkillsCollector) Export(state abstract.LayeredState) image.Image {
-	var uses subjectiveSkillUses
-	switch s.currentSubject.ty {
-	case UseAllies:
-		uses = s.allies
-	case UseEnemies:
-		uses = s.enemies
-	case UseAll:
-		uses = s.all
-	case UseCustom:
-		for _, potentialUses := range s.subjects {
-			if potentialUses.name == s.currentSubject.name {
-				uses = potentialUses
-				break
-			}
-		}
-	}
+	uses := s.currentUses()
 
 	styledFonts := drawing.StyleFontPack(state.FontPack(), state.Theme().Fg)
 
@@ -506,14 +601,15 @@ func (s *SkillsCollector) Export(state abstract.LayeredState) image.Image {
 
 	switch s.currentDisplay {
 	case DisplayBars:
-		items := make([]drawing.FlexChild, 0, len(uses.skills)*2-1)
+		skills, maxUsed := s.grouped(state, uses)
+		items := make([]drawing.FlexChild, 0, len(skills)*2-1)
 
-		for i, skill := range uses.skills {
+		for i, skill := range skills {
 			if i != 0 {
 				items = append(items, drawing.FlexVSpacer(drawing.CommonSpacing))
 			}
 
-			items = append(items, drawing.Rigid(s.exportBar(styledFonts, skill, uses.maxUsed)))
+			items = append(items, drawing.Rigid(s.exportBar(styledFonts, skill, maxUsed)))
 		}
 
 		body = drawing.Flex{
@@ -523,10 +619,11 @@ func (s *SkillsCollector) Export(state abstract.LayeredState) image.Image {
 			items...,
 		)
 	case DisplayPie:
+		skills, _ := s.grouped(state, uses)
 		var totalValue int
 
-		pieItems := make([]drawing.PieChartItem, 0, max(1, len(uses.skills)))
-		for _, skill := range uses.skills {
+		pieItems := make([]drawing.PieChartItem, 0, max(1, len(skills)))
+		for _, skill := range skills {
 			pieItems = append(pieItems, drawing.PieChartItem{
 				Name:    skill.name,
 				Value:   skill.amount,
@@ -569,6 +666,13 @@ func (s *SkillsCollector) Export(state abstract.LayeredState) image.Image {
 		}.Layout(
 			items...,
 		)
+	case DisplayRotation:
+		body = drawing.Flex{
+			ExpandW: true,
+			Axis:    layout.Vertical,
+		}.Layout(
+			s.rotation.Export(styledFonts, s.longFormatBool.Value)...,
+		)
 	}
 
 	base := layoutTitle(
@@ -581,6 +685,13 @@ func (s *SkillsCollector) Export(state abstract.LayeredState) image.Image {
 		}.Layout(
 			styledFonts.Smaller.Layout(fmt.Sprintf("Subject: %v", s.currentSubject)),
 			styledFonts.Smaller.Layout(fmt.Sprintf("Display: %v", s.currentDisplay)),
+			func(ltx drawing.Context) drawing.Result {
+				if s.currentDisplay == DisplayBars || s.currentDisplay == DisplayPie {
+					return styledFonts.Smaller.Layout(fmt.Sprintf("Grouped by: %v", s.currentGroup))(ltx)
+				}
+
+				return drawing.Empty(ltx)
+			},
 		),
 		drawing.RoundedSurface(
 			utils.SecondBG,
@@ -590,3 +701,44 @@ func (s *SkillsCollector) Export(state abstract.LayeredState) image.Image {
 
 	return drawing.ExportImage(state.Theme(), base, drawing.F64(800, 10000))
 }
+
+func (s *SkillsCollector) TextTable(state abstract.LayeredState) abstract.TextTable {
+	uses := s.currentUses()
+	skills, _ := s.grouped(state, uses)
+	long := s.longFormatBool.Value
+
+	table := abstract.TextTable{
+		Title: textTitle(s.TabName()),
+		Parameters: []string{
+			fmt.Sprintf("Subject: %v", s.currentSubject),
+			fmt.Sprintf("Grouped by: %v", s.currentGroup),
+			fmt.Sprintf("Total uses: %v", uses.totalUsed),
+		},
+		Columns: []string{"Skill", "Uses", "Damage", "Share"},
+	}
+	if uses.timeController != nil {
+		table.TimeFrame = textTimeFrame(uses.timeController.CurrentTimeFrame)
+	}
+
+	for _, skill := range skills {
+		table.Rows = append(table.Rows, []string{
+			skill.name,
+			strconv.Itoa(skill.amount),
+			skill.damage.StringCL(long),
+			textShare(skill.amount, uses.totalUsed),
+		})
+	}
+
+	return table
+}
+
+func (s *SkillsCollector) ChatLine(state abstract.LayeredState) string {
+	uses := s.currentUses()
+
+	top := ""
+	if skills, _ := s.grouped(state, uses); len(skills) > 0 {
+		top = chatTop(skills[0].name, skills[0].amount, uses.totalUsed)
+	}
+
+	return chatLine(fmt.Sprintf("Skill uses: %v", utils.FormatNumber(uses.totalUsed)), top)
+}
@@ -3,6 +3,7 @@ package collectors
 import (
 	"PGCombatTracker/abstract"
 	"PGCombatTracker/parser"
+	"PGCombatTracker/utils"
 	"bufio"
 	"io"
 	"log"
@@ -13,12 +14,29 @@ import (
 	"time"
 )
 
+// recordHarvestInterval How often records are checked while watching a file
+const recordHarvestInterval = 5 * time.Second
+
+type characterCollectors struct {
+	name       string
+	collectors []abstract.Collector
+}
+
 type StatisticsCollector struct {
 	settings   *abstract.Settings
 	username   string
+	area       string
 	timeFrames []abstract.MarkerTimeFrame
 	dead       *atomic.Bool
 	collectors []abstract.Collector
+	characters []characterCollectors
+	sessions   []abstract.CharacterSession
+	selected   *atomic.Pointer[string]
+	identity   abstract.FileIdentity
+	allegiance *Allegiance
+	health     *enemyHealthTracker
+	healthDB   abstract.EnemyHealthBearer
+	xpTables   abstract.XPTableBearer
 	quit       chan bool
 	watch      bool
 	fullPath   string
@@ -27,6 +45,22 @@ type StatisticsCollector struct {
 	lock       *sync.RWMutex
 	locked     bool
 	notify     chan bool
+
+	records abstract.RecordBearer
+	toasts  abstract.ToastBearer
+	// live Set once a watched file was read up to the end, from then on events are happening right now
+	live bool
+	// recordsDirty Set when events came in since records were last offered
+	recordsDirty bool
+	lastHarvest  time.Time
+
+	reports abstract.WebhookBearer
+	// inEncounter Whether a fight was going on when last checked, reports are sent when it's over
+	inEncounter bool
+
+	summaries []abstract.SessionSummary
+	// summaryStart Totals of the character when the current login started, nil before anything was read
+	summaryStart *abstract.SessionSummary
 }
 
 func NewStatisticsCollector(state abstract.GlobalState, path string, watchFile bool, timeFrames []abstract.MarkerTimeFrame) (*StatisticsCollector, error) {
@@ -36,18 +70,25 @@ func NewStatisticsCollector(state abstract.GlobalState, path string, watchFile b
 		return nil, err
 	}
 
+	identity, _ := state.FindIdentity(path)
+	if identity.Source != abstract.IdentityManual {
+		identity = abstract.FileIdentity{Path: path}
+	}
+
 	return &StatisticsCollector{
-		settings: state.Settings(),
-		collectors: []abstract.Collector{
-			NewDamageDealtCollector(state.Settings()),
-			NewDamageTakenCollector(),
-			NewHealingCollector(),
-			NewSkillsCollector(),
-			NewLevelingCollector(),
-			NewMiscCollector(),
-		},
+		settings:   state.Settings(),
+		identity:   identity,
+		allegiance: NewAllegiance(state.Settings(), state),
+		health:     newEnemyHealthTracker(),
+		healthDB:   state,
+		xpTables:   state,
+		records:    state,
+		toasts:     state,
+		reports:    state,
+		collectors: newCollectorSet(state.Settings(), false),
 		timeFrames: timeFrames,
 		dead:       &atomic.Bool{},
+		selected:   &atomic.Pointer[string]{},
 		quit:       make(chan bool, 1),
 		watch:      watchFile,
 		fullPath:   path,
@@ -58,6 +99,29 @@ func NewStatisticsCollector(state abstract.GlobalState, path string, watchFile b
 	}, nil
 }
 
+// newCollectorSet Alerts are raised by the set of the character that is being played
+func newCollectorSet(settings *abstract.Settings, characterSet bool) []abstract.Collector {
+	damageTaken := NewDamageTakenCollector()
+	enemies := NewEnemiesCollector()
+	leveling := NewLevelingCollector()
+	misc := NewMiscCollector()
+
+	return []abstract.Collector{
+		NewDamageDealtCollector(settings),
+		damageTaken,
+		NewHealingCollector(settings),
+		NewPowerCollector(settings),
+		NewPetsCollector(),
+		enemies,
+		NewKillsCollector(),
+		NewActivityCollector(),
+		NewSkillsCollector(),
+		leveling,
+		misc,
+		NewGoalsCollector(settings, characterSet, leveling, misc, enemies, damageTaken),
+	}
+}
+
 func (stats *StatisticsCollector) SaveMarker(state abstract.GlobalState, name string) {
 	stats.lock.Lock()
 	state.SaveMarker(stats.fullPath, name, stats.username)
@@ -72,10 +136,257 @@ func (stats *StatisticsCollector) CurrentUsername() string {
 	return stats.username
 }
 
+func (stats *StatisticsCollector) CurrentArea() string {
+	return stats.area
+}
+
+func (stats *StatisticsCollector) IsAlly(name, skill string) bool {
+	return stats.allegiance.IsAlly(stats.CurrentUsername(), name, skill)
+}
+
+func (stats *StatisticsCollector) IsPet(name string) bool {
+	return stats.allegiance.IsPet(stats.CurrentUsername(), name)
+}
+
+func (stats *StatisticsCollector) OwnerOf(name string) string {
+	return stats.allegiance.OwnerOf(name)
+}
+
+func (stats *StatisticsCollector) EnemyHealth(enemyType string) (abstract.EnemyHealthEstimate, bool) {
+	return stats.healthDB.EnemyHealth(enemyType)
+}
+
+func (stats *StatisticsCollector) XPTable() *abstract.XPTable {
+	return stats.xpTables.XPTable()
+}
+
+func (stats *StatisticsCollector) Alert(text string) {
+	if stats.live {
+		stats.toasts.ShowAlert(text)
+	}
+}
+
 func (stats *StatisticsCollector) Collectors() []abstract.Collector {
+	selected := stats.SelectedCharacter()
+	for _, character := range stats.characters {
+		if character.name == selected {
+			return character.collectors
+		}
+	}
+
 	return stats.collectors
 }
 
+// Identity Character name that was detected for the file, or the one that was set manually
+func (stats *StatisticsCollector) Identity() abstract.FileIdentity {
+	return stats.identity
+}
+
+// detectIdentity Remembers the first character that was found, manually set one is never replaced
+func (stats *StatisticsCollector) detectIdentity(name string, source abstract.IdentitySource) {
+	detected := abstract.FileIdentity{
+		Path:   stats.fullPath,
+		Name:   name,
+		Source: source,
+	}
+
+	if stats.identity.Name == "" || (stats.identity.Name == name && detected.BetterThan(stats.identity)) {
+		stats.identity = detected
+	}
+}
+
+func (stats *StatisticsCollector) Characters() []string {
+	names := make([]string, len(stats.characters))
+	for i, character := range stats.characters {
+		names[i] = character.name
+	}
+	return names
+}
+
+func (stats *StatisticsCollector) Sessions() []abstract.CharacterSession {
+	return stats.sessions
+}
+
+// SelectedCharacter Can be called from any goroutine, the UI and the local API both read it
+func (stats *StatisticsCollector) SelectedCharacter() string {
+	if selected := stats.selected.Load(); selected != nil {
+		return *selected
+	}
+
+	return ""
+}
+
+// SelectCharacter Changes which character's collectors are returned by Collectors
+func (stats *StatisticsCollector) SelectCharacter(name string) {
+	stats.selected.Store(&name)
+}
+
+// allCollectorSets Collectors for all characters, and collectors for each character separately
+func (stats *StatisticsCollector) allCollectorSets() [][]abstract.Collector {
+	sets := make([][]abstract.Collector, 0, len(stats.characters)+1)
+	sets = append(sets, stats.collectors)
+	for _, character := range stats.characters {
+		sets = append(sets, character.collectors)
+	}
+	return sets
+}
+
+// switchCharacter Starts a new character session if the character is different from current one
+func (stats *StatisticsCollector) switchCharacter(name string, at time.Time) {
+	if name == "" {
+		return
+	}
+
+	if len(stats.sessions) > 0 {
+		last := &stats.sessions[len(stats.sessions)-1]
+
+		if last.Name == name {
+			return
+		}
+
+		if stats.live && stats.settings.WebhookOnSessionEnd {
+			stats.queueReport(abstract.ReportSessionEnd, last.Name)
+		}
+
+		if last.To.Before(at) {
+			last.To = at
+		}
+	}
+
+	stats.username = name
+	stats.sessions = append(stats.sessions, abstract.CharacterSession{
+		Name: name,
+		From: at,
+		To:   at,
+	})
+}
+
+// finishCollectors Time doesn't move on after the end of a file that isn't watched, leaves the lock locked
+func (stats *StatisticsCollector) finishCollectors() {
+	stats.lockTheLock()
+
+	for _, set := range stats.allCollectorSets() {
+		for _, collector := range set {
+			if finishing, ok := collector.(abstract.FinishingCollector); ok {
+				finishing.Finish(stats)
+			}
+		}
+	}
+}
+
+// collectorsForCurrentCharacter Finds or creates collectors that only receive events of current character
+func (stats *StatisticsCollector) collectorsForCurrentCharacter() []abstract.Collector {
+	if stats.username == "" {
+		return nil
+	}
+
+	var found []abstract.Collector
+
+	stats.characters = utils.CreateUpdate(
+		stats.characters,
+		func(character characterCollectors) bool {
+			return character.name == stats.username
+		},
+		func() characterCollectors {
+			found = newCollectorSet(stats.settings, true)
+			return characterCollectors{
+				name:       stats.username,
+				collectors: found,
+			}
+		},
+		func(character characterCollectors) characterCollectors {
+			found = character.collectors
+			return character
+		},
+	)
+
+	return found
+}
+
+// harvestRecords Offers records of every character, records broken while watching get announced
+func (stats *StatisticsCollector) harvestRecords(announce bool) {
+	if !stats.recordsDirty || (announce && time.Since(stats.lastHarvest) < recordHarvestInterval) {
+		return
+	}
+
+	var records []abstract.PersonalRecord
+
+	stats.lock.RLock()
+	for _, character := range stats.characters {
+		for _, collector := range character.collectors {
+			source, ok := collector.(abstract.RecordSource)
+			if !ok {
+				continue
+			}
+
+			for _, record := range source.Records(character.name) {
+				record.Character = character.name
+				record.Path = stats.fullPath
+				records = append(records, record)
+			}
+		}
+	}
+	stats.lock.RUnlock()
+
+	stats.recordsDirty = false
+	stats.lastHarvest = time.Now()
+	stats.records.OfferRecords(records, announce)
+}
+
+// queueReport Reports only go out while a watched file is followed live, older sessions were reported when they happened,
+// the UI exports them later while holding the read lock
+func (stats *StatisticsCollector) queueReport(trigger abstract.ReportTrigger, character string) {
+	collectors := stats.collectors
+	for _, set := range stats.characters {
+		if set.name == character {
+			collectors = set.collectors
+		}
+	}
+
+	stats.reports.SendReport(trigger, character, stats.fullPath, collectors)
+}
+
+// checkEncounter Queues a report once the fight that was going on is over
+func (stats *StatisticsCollector) checkEncounter() {
+	inEncounter := false
+	for _, collector := range stats.collectors {
+		if source, ok := collector.(abstract.EncounterSource); ok && source.InEncounter() {
+			inEncounter = true
+		}
+	}
+
+	if stats.inEncounter && !inEncounter && stats.live && stats.settings.WebhookOnEncounterEnd {
+		stats.queueReport(abstract.ReportEncounterEnd, stats.username)
+	}
+	stats.inEncounter = inEncounter
+}
+
+func (stats *StatisticsCollector) Metrics() []abstract.Metric {
+	var metrics []abstract.Metric
+
+	stats.lock.RLock()
+	defer stats.lock.RUnlock()
+
+	for _, character := range stats.characters {
+		for _, collector := range character.collectors {
+			source, ok := collector.(abstract.MetricSource)
+			if !ok {
+				continue
+			}
+
+			for _, metric := range source.Metrics(character.name) {
+				metric.Labels = append([]abstract.MetricLabel{
+					{Name: "character", Value: character.name},
+					{Name: "collector", Value: collector.TabName()},
+				}, metric.Labels...)
+				metrics = append(metrics, metric)
+			}
+		}
+	}
+
+	return metrics
+}
+
 func (stats *StatisticsCollector) Mutex() *sync.RWMutex {
 	return stats.lock
 }
@@ -102,8 +413,10 @@ func (stats *StatisticsCollector) unlockTheLock() {
 func (stats *StatisticsCollector) Reset() {
 	stats.lock.Lock()
 
-	for _, collector := range stats.collectors {
-		collector.Reset(stats)
+	for _, set := range stats.allCollectorSets() {
+		for _, collector := range set {
+			collector.Reset(stats)
+		}
 	}
 
 	stats.lock.Unlock()
@@ -147,12 +460,16 @@ func (stats *StatisticsCollector) Run() {
 		for nextTick.Before(at) {
 			stats.lockTheLock()
 
-			for _, collector := range stats.collectors {
-				collector.Tick(stats, nextTick)
+			for _, set := range stats.allCollectorSets() {
+				for _, collector := range set {
+					collector.Tick(stats, nextTick)
+				}
 			}
 
 			nextTick = nextTick.Add(tickIntervalDuration)
 		}
+
+		stats.checkEncounter()
 	}
 
 	go func() {
@@ -169,14 +486,19 @@ func (stats *StatisticsCollector) Run() {
 				// Don't act on the error, just log it
 				if err != nil {
 					stats.unlockTheLock()
+					stats.health.Flush(stats.healthDB, stats.fullPath)
 
 					if err == io.EOF {
+						stats.harvestRecords(stats.watch && !firstRead)
+
 						if stats.watch {
 							tickIfNeeded(time.Now())
 							firstRead = false
+							stats.live = true
 							time.Sleep(100 * time.Millisecond)
 							continue infinite
 						} else {
+							stats.finishCollectors()
 							break infinite
 						}
 					} else {
@@ -195,13 +517,21 @@ func (stats *StatisticsCollector) Run() {
 					nextTick = event.Time
 				}
 
+				// Keep track of the area even outside of time frames, so area filtering works
+				if areaChange, ok := event.Contents.(*abstract.AreaChange); ok && areaChange != nil {
+					log.Printf("Detected area change to %v\n", areaChange.Area)
+					stats.lockTheLock()
+					stats.area = areaChange.Area
+					continue infinite
+				}
+
 				// Check timeframe stuff
 				within, timeFrameUser := abstract.WithinTimeFrames(stats.timeFrames, event.Time)
 
 				if firstRead {
 					if (within != lastWithin) && within {
 						stats.lockTheLock()
-						stats.username = timeFrameUser
+						stats.switchCharacter(timeFrameUser, event.Time)
 						stats.unlockTheLock()
 					}
 
@@ -215,35 +545,86 @@ func (stats *StatisticsCollector) Run() {
 				// Grab username from login if detected
 				if login, ok := event.Contents.(*abstract.Login); ok && login != nil {
 					log.Printf("Detected login as %v\n", login.Name)
-					stats.username = login.Name
+					stats.lockTheLock()
+
+					// Name that was set manually wins over logins, the user corrected whoever the file says it was
+					name := login.Name
+					if stats.identity.Source == abstract.IdentityManual {
+						name = stats.identity.Name
+					} else {
+						stats.detectIdentity(login.Name, abstract.IdentityLogin)
+					}
+
+					stats.switchCharacter(name, event.Time)
+					stats.startSummary(name, event.Time)
 					continue infinite
 				}
 
-				// If username is still empty, try to find it
+				// Logins are handled before this, same as area changes, so characters switch even outside of filtered areas
+				if !abstract.WithinAreas(stats.timeFrames, stats.area) {
+					continue infinite
+				}
+
+				stats.lockTheLock()
+
+				// If username is still empty, use the one that was set manually or try to find it
 				if stats.username == "" {
-					stats.username = stats.FindUsername(event)
+					if stats.identity.Source == abstract.IdentityManual {
+						stats.switchCharacter(stats.identity.Name, event.Time)
+					} else {
+						guessed := stats.FindUsername(event)
+						stats.detectIdentity(guessed, abstract.IdentityHeuristic)
+						stats.switchCharacter(guessed, event.Time)
+					}
+				}
+
+				if len(stats.sessions) > 0 {
+					stats.sessions[len(stats.sessions)-1].To = event.Time
 				}
 
+				// Events before the first login, or after switching time frames, are summarized for whoever is current
+				if stats.username != "" && (stats.summaryStart == nil || stats.summaryStart.Character != stats.username) {
+					stats.startSummary(stats.username, event.Time)
+				}
+				if stats.summaryStart != nil {
+					stats.summaryStart.To = event.Time
+				}
+
+				stats.allegiance.Learn(stats.username, event)
+				stats.health.Track(stats, event)
+
 				//log.Println(event)
 
-				stats.lockTheLock()
 				tickIfNeeded(event.Time)
 
-				for _, collector := range stats.collectors {
-					err = collector.Collect(stats, event)
+				collect := func(collectors []abstract.Collector) {
+					for _, collector := range collectors {
+						err := collector.Collect(stats, event)
 
-					if err != nil {
-						log.Printf(
-							"Collector '%v' encountered an error while ingesting line: %v\n",
-							collector.TabName(),
-							err,
-						)
+						if err != nil {
+							log.Printf(
+								"Collector '%v' encountered an error while ingesting line: %v\n",
+								collector.TabName(),
+								err,
+							)
+						}
 					}
 				}
+
+				collect(stats.collectors)
+				collect(stats.collectorsForCurrentCharacter())
+				stats.recordsDirty = true
 			}
 		}
 
+		stats.lockTheLock()
+		stats.finishSummary()
 		stats.unlockTheLock()
+		stats.health.Flush(stats.healthDB, stats.fullPath)
+
+		if stats.live && stats.settings.WebhookOnSessionEnd {
+			stats.queueReport(abstract.ReportSessionEnd, stats.username)
+		}
 
 		log.Printf("Closing file at '%v'\n", fileName)
 		stats.dead.Store(true)
@@ -12,6 +12,7 @@ import (
 	"image"
 	"math"
 	"strings"
+	"time"
 	"unicode"
 )
 
@@ -169,6 +170,10 @@ const (
 	DisplayBars displayChoice = iota
 	DisplayPie
 	DisplayGraphs
+	DisplayAbilities
+	DisplayVitals
+	DisplayRotation
+	DisplayProgress
 )
 
 func (d displayChoice) String() string {
@@ -179,6 +184,14 @@ func (d displayChoice) String() string {
 		return "Pie"
 	case DisplayGraphs:
 		return "Graphs"
+	case DisplayAbilities:
+		return "Abilities"
+	case DisplayVitals:
+		return "Vitals Split"
+	case DisplayRotation:
+		return "Rotation"
+	case DisplayProgress:
+		return "Progress"
 	}
 
 	return ""
@@ -247,3 +260,26 @@ func (l limitChoice) Int() int {
 	}
 	return 0
 }
+
+// markActive Adds a moment of fighting to the spans, a span goes on while moments keep coming within the combat window,
+// but it ends at its last moment, reports if a new span was started
+func markActive(spans []components.TimeFrame, at time.Time, window time.Duration) ([]components.TimeFrame, bool) {
+	if len(spans) > 0 && !at.After(spans[len(spans)-1].To.Add(window)) {
+		last := &spans[len(spans)-1]
+		if at.After(last.To) {
+			last.To = at
+		}
+		return spans, false
+	}
+
+	return append(spans, components.TimeFrame{From: at, To: at}), true
+}
+
+// activeTime Time spent fighting, every tab that shows active DPS uses spans from markActive
+func activeTime(spans []components.TimeFrame) time.Duration {
+	var total time.Duration
+	for _, span := range spans {
+		total += span.To.Sub(span.From)
+	}
+	return total
+}
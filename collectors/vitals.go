@@ -0,0 +1,177 @@
+package collectors
+
+import (
+	"PGCombatTracker/abstract"
+	"PGCombatTracker/ui/components"
+	"PGCombatTracker/utils"
+	"PGCombatTracker/utils/drawing"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"time"
+)
+
+var vitalNames = [3]string{"Health", "Armor", "Power"}
+
+// splitVitals Separates vitals into their components, in the same order as vitalNames
+func splitVitals(vitals abstract.Vitals) [3]abstract.Vitals {
+	return [3]abstract.Vitals{
+		{Health: vitals.Health},
+		{Armor: vitals.Armor},
+		{Power: vitals.Power},
+	}
+}
+
+func vitalSegments(vitals abstract.Vitals, maxValue int) []components.BarSegment {
+	segments := make([]components.BarSegment, 0, len(vitalNames))
+	for i, component := range splitVitals(vitals) {
+		progress := 0.0
+		if maxValue != 0 {
+			progress = float64(component.Total()) / float64(maxValue)
+		}
+
+		segments = append(segments, components.BarSegment{
+			Color:    components.StringToColor(vitalNames[i]),
+			Progress: progress,
+		})
+	}
+	return segments
+}
+
+// vitalCharts Cumulative charts of every vital type, stacked on top of each other
+type vitalCharts struct {
+	charts  [3]*components.TimeBasedChart
+	stacked *components.StackedTimeBasedChart
+}
+
+func newVitalCharts() *vitalCharts {
+	charts := &vitalCharts{
+		stacked: components.NewStackedTimeBasedChart(),
+	}
+
+	for i, name := range vitalNames {
+		charts.charts[i] = components.NewTimeBasedChart(name)
+		charts.stacked.Add(charts.charts[i], name)
+	}
+
+	return charts
+}
+
+// Add Expects running total of the vitals
+func (v *vitalCharts) Add(at time.Time, total abstract.Vitals) {
+	for i, component := range splitVitals(total) {
+		v.charts[i].Add(components.TimePoint{
+			Time:    at,
+			Value:   component.Total(),
+			Details: component,
+		})
+	}
+}
+
+func drawVitalsBar(
+	state abstract.LayeredState,
+	vitals abstract.Vitals,
+	maxValue, amount int,
+	name, amountFormat string,
+	size unit.Dp,
+	long bool,
+) layout.Widget {
+	return drawUniversalStatsText(
+		state,
+		vitals,
+		components.SegmentedBarWidget(size, vitalSegments(vitals, maxValue)...),
+		amount,
+		name, amountFormat,
+		size,
+		long,
+	)
+}
+
+// drawVitalsSplit Stacked chart of vitals over time, followed by a bar for every vital type
+func drawVitalsSplit(
+	state abstract.LayeredState,
+	charts *vitalCharts,
+	controller *components.TimeController,
+	total abstract.Vitals,
+	long bool,
+) []layout.Widget {
+	charts.stacked.DisplayTimeFrame = controller.CurrentTimeFrame
+	charts.stacked.DisplayValueRange = controller.FullValueRange
+
+	stackedStyle := components.StyleStackedTimeBasedChart(state.Theme(), charts.stacked)
+	stackedStyle.Alpha = 255
+	stackedStyle.MinHeight = 150
+	stackedStyle.TextSize = 12
+	stackedStyle.LongFormat = long
+
+	widgets := []layout.Widget{
+		func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{
+				Axis: layout.Vertical,
+			}.Layout(
+				gtx,
+				layout.Rigid(stackedStyle.Layout),
+				utils.FlexSpacerH(utils.CommonSpacing),
+			)
+		},
+	}
+
+	for i, component := range splitVitals(total) {
+		widgets = append(widgets, drawUniversalBar(
+			state, component,
+			component.Total(), total.Total(), 0,
+			vitalNames[i], "",
+			25, long,
+		))
+	}
+
+	return widgets
+}
+
+func exportVitalsBar(
+	styledFonts *drawing.StyledFontPack,
+	vitals abstract.Vitals,
+	maxValue, amount int,
+	name, amountFormat string,
+	long bool,
+) drawing.Widget {
+	return exportUniversalStatsTextAsSurface(
+		styledFonts,
+		vitals,
+		segmentedPercentBar(vitalSegments(vitals, maxValue)...),
+		amount,
+		name, amountFormat,
+		long,
+	)
+}
+
+func exportVitalsSplit(
+	styledFonts *drawing.StyledFontPack,
+	charts *vitalCharts,
+	controller *components.TimeController,
+	total abstract.Vitals,
+	long bool,
+) []drawing.FlexChild {
+	charts.stacked.DisplayTimeFrame = controller.CurrentTimeFrame
+	charts.stacked.DisplayValueRange = controller.FullValueRange
+
+	items := []drawing.FlexChild{
+		drawing.Rigid(exportTimeFrame(styledFonts, controller.CurrentTimeFrame)),
+		drawing.FlexVSpacer(drawing.CommonSpacing),
+		drawing.Rigid(drawing.StyleStackedAreaChart(styledFonts, charts.stacked).Layout()),
+	}
+
+	for i, component := range splitVitals(total) {
+		items = append(
+			items,
+			drawing.FlexVSpacer(drawing.CommonSpacing),
+			drawing.Rigid(exportUniversalBar(
+				styledFonts, component,
+				component.Total(), total.Total(), 0,
+				vitalNames[i], "",
+				long,
+			)),
+		)
+	}
+
+	return items
+}
@@ -0,0 +1,158 @@
+package collectors
+
+import (
+	"PGCombatTracker/abstract"
+	"PGCombatTracker/utils"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type levelGoal struct {
+	current int
+	target  int
+}
+
+// parseLevelGoal Goals look like "Skill Name=target" or "Skill Name=current>target"
+func parseLevelGoal(rule string) (string, levelGoal, bool) {
+	skill, levels, found := strings.Cut(rule, "=")
+	skill = strings.TrimSpace(skill)
+	if !found || skill == "" {
+		log.Printf("Ignoring level goal '%v', expected 'Skill Name=target'\n", rule)
+		return "", levelGoal{}, false
+	}
+
+	var goal levelGoal
+	var err error
+
+	current, target, hasCurrent := strings.Cut(levels, ">")
+	if hasCurrent {
+		goal.current, err = strconv.Atoi(strings.TrimSpace(current))
+	} else {
+		target = current
+	}
+
+	if err == nil {
+		goal.target, err = strconv.Atoi(strings.TrimSpace(target))
+	}
+
+	if err != nil {
+		log.Printf("Ignoring level goal '%v': %v\n", rule, err)
+		return "", levelGoal{}, false
+	}
+
+	return skill, goal, true
+}
+
+func findLevelGoal(settings *abstract.Settings, skill string) (levelGoal, bool) {
+	for _, rule := range settings.LevelGoals {
+		if name, goal, ok := parseLevelGoal(rule); ok && name == skill {
+			return goal, true
+		}
+	}
+
+	return levelGoal{}, false
+}
+
+func perHour(amount int, length time.Duration) float64 {
+	return float64(amount) / max(length, time.Minute).Hours()
+}
+
+type xpRate struct {
+	overall float64
+	recent  float64
+	window  time.Duration
+}
+
+func (r xpRate) StringCL(long bool) string {
+	format := func(rate float64) string {
+		if long {
+			return fmt.Sprintf("%.0f", rate)
+		}
+		return utils.FormatNumber(int(rate))
+	}
+
+	return fmt.Sprintf("%v XP/h, %v XP/h last %v", format(r.overall), format(r.recent), r.window)
+}
+
+// current Recent rate says more about what's happening now, overall is used when nothing was gained recently
+func (r xpRate) current() float64 {
+	if r.recent > 0 {
+		return r.recent
+	}
+	return r.overall
+}
+
+func skillRate(skill skillXP, now time.Time, window time.Duration) xpRate {
+	rate := xpRate{
+		window: window,
+	}
+
+	if len(skill.gains) == 0 {
+		return rate
+	}
+
+	rate.overall = perHour(skill.xp, now.Sub(skill.gains[0].at))
+
+	var recentXP int
+	for i := len(skill.gains) - 1; i >= 0 && now.Sub(skill.gains[i].at) <= window; i-- {
+		recentXP += skill.gains[i].xp
+	}
+	rate.recent = perHour(recentXP, window)
+
+	return rate
+}
+
+func etaText(xpLeft int, rate float64) string {
+	if xpLeft <= 0 {
+		return "any moment now"
+	}
+
+	if rate <= 0 {
+		return "never at this rate"
+	}
+
+	return fmt.Sprintf("in about %v", ttkValue(time.Duration(float64(xpLeft)/rate*float64(time.Hour))).StringCL(false))
+}
+
+// progressTexts Captions about the next level and the goal, empty if the level of the skill isn't known
+func progressTexts(state abstract.GlobalState, skill skillXP, rate xpRate) []string {
+	table := state.XPTable()
+	goal, hasGoal := findLevelGoal(state.Settings(), skill.name)
+
+	level := skill.level
+	if level == 0 && hasGoal {
+		level = goal.current
+	}
+
+	if level == 0 {
+		return nil
+	}
+
+	toNext, ok := table.ToNextLevel(skill.name, level)
+	if !ok {
+		return []string{fmt.Sprintf("Level %v, XP table doesn't have this level", level)}
+	}
+
+	texts := []string{fmt.Sprintf(
+		"Level %v: %v / %v XP, next level %v",
+		level, skill.levelXP, toNext,
+		etaText(toNext-skill.levelXP, rate.current()),
+	)}
+
+	if hasGoal && goal.target > level {
+		if toTarget, ok := table.ToLevel(skill.name, level, goal.target); ok {
+			texts = append(texts, fmt.Sprintf(
+				"Goal %v: %.0f%% there, %v XP left, reached %v",
+				goal.target,
+				float64(skill.levelXP)/float64(toTarget)*100,
+				toTarget-skill.levelXP,
+				etaText(toTarget-skill.levelXP, rate.current()),
+			))
+		}
+	}
+
+	return texts
+}
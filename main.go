@@ -3,6 +3,7 @@ package main
 import (
 	"PGCombatTracker/abstract"
 	"PGCombatTracker/collectors"
+	"PGCombatTracker/server"
 	"PGCombatTracker/ui"
 	"PGCombatTracker/utils"
 	"gioui.org/app"
@@ -52,6 +53,9 @@ func run(window *app.Window) error {
 
 	state.SwitchPage(ui.NewFileSelectionPage())
 
+	apiServer := server.NewServer(state)
+	go apiServer.Run()
+
 	go func() {
 		for {
 			stats := state.StatisticsCollector()
@@ -59,6 +63,7 @@ func run(window *app.Window) error {
 			if stats != nil && stats.IsAlive() {
 				<-stats.Notify()
 				window.Invalidate()
+				apiServer.Broadcast()
 				continue
 			}
 
@@ -75,10 +80,12 @@ func run(window *app.Window) error {
 			// This graphics context is used for managing the rendering state.
 			gtx := app.NewContext(&ops, e)
 
+			state.SendQueuedReports()
+
 			layout.Background{}.Layout(
 				gtx,
 				utils.MakeColoredAndOptionalDragBG(state.Theme().Bg, state.CanBeDragged()),
-				func(gtx layout.Context) layout.Dimensions {
+				state.Toaster().Overlay(state.Theme(), func(gtx layout.Context) layout.Dimensions {
 					if state.Page != nil {
 						err := state.Page().Layout(gtx, state)
 						if err != nil {
@@ -87,7 +94,7 @@ func run(window *app.Window) error {
 					}
 
 					return layout.Dimensions{Size: gtx.Constraints.Min}
-				},
+				}),
 			)
 
 			// Pass the drawing operations to the GPU.
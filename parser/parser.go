@@ -89,6 +89,10 @@ func ParseLine(line string) *abstract.ChatEvent {
 	} else if strings.HasPrefix(rest, "[Status] You receive ") {
 		return parseReceivedCoins(timeValue, rest)
 	} else if strings.HasPrefix(rest, "***") {
+		if strings.Contains(rest, "Entering Area: ") {
+			return parseAreaChange(timeValue, rest)
+		}
+
 		return parseLogin(timeValue, rest)
 	} else if strings.HasPrefix(rest, "[Error]") {
 		_, rest, _ := strings.Cut(rest, "[Error] ")
@@ -142,6 +146,27 @@ func parseLogin(timeValue time.Time, rest string) *abstract.ChatEvent {
 	}
 }
 
+func parseAreaChange(timeValue time.Time, rest string) *abstract.ChatEvent {
+	_, rest, found := strings.Cut(rest, "Entering Area: ")
+
+	if !found {
+		return nil
+	}
+
+	area := strings.TrimSpace(rest)
+
+	if area == "" {
+		return nil
+	}
+
+	return &abstract.ChatEvent{
+		Time: timeValue,
+		Contents: &abstract.AreaChange{
+			Area: area,
+		},
+	}
+}
+
 func parseSkillUse(subject, skill, rest string) *abstract.SkillUse {
 	var (
 		victim string
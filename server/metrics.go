@@ -0,0 +1,77 @@
+package server
+
+import (
+	"PGCombatTracker/abstract"
+	"bytes"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const openMetricsType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// writeMetrics Writes samples as OpenMetrics text, samples of a family are kept together in the order they first showed up
+func writeMetrics(buffer *bytes.Buffer, metrics []abstract.Metric) {
+	var names []string
+	families := make(map[string][]abstract.Metric)
+
+	for _, metric := range metrics {
+		if _, ok := families[metric.Name]; !ok {
+			names = append(names, metric.Name)
+		}
+		families[metric.Name] = append(families[metric.Name], metric)
+	}
+
+	for _, name := range names {
+		family := families[name]
+		first := family[0]
+
+		buffer.WriteString("# TYPE " + name + " " + first.Type.String() + "\n")
+		buffer.WriteString("# HELP " + name + " " + labelEscaper.Replace(first.Help) + "\n")
+
+		sampleName := name
+		if first.Type == abstract.MetricCounter {
+			sampleName += "_total"
+		}
+
+		for _, metric := range family {
+			buffer.WriteString(sampleName)
+
+			if len(metric.Labels) > 0 {
+				buffer.WriteByte('{')
+				for i, label := range metric.Labels {
+					if i > 0 {
+						buffer.WriteByte(',')
+					}
+					buffer.WriteString(label.Name + `="` + labelEscaper.Replace(label.Value) + `"`)
+				}
+				buffer.WriteByte('}')
+			}
+
+			buffer.WriteString(" " + strconv.FormatFloat(metric.Value, 'g', -1, 64) + "\n")
+		}
+	}
+
+	buffer.WriteString("# EOF\n")
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	var metrics []abstract.Metric
+
+	stats := s.state.StatisticsCollector()
+	if stats != nil {
+		metrics = stats.Metrics()
+	}
+
+	var buffer bytes.Buffer
+	writeMetrics(&buffer, metrics)
+
+	w.Header().Set("Content-Type", openMetricsType)
+	_, err := w.Write(buffer.Bytes())
+	if err != nil {
+		log.Printf("Error writing metrics: %v\n", err)
+	}
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"PGCombatTracker/abstract"
+	"bytes"
+	"testing"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	metrics := []abstract.Metric{
+		{
+			Name:   "pgct_damage_dealt",
+			Help:   "Damage dealt by a skill",
+			Type:   abstract.MetricCounter,
+			Labels: []abstract.MetricLabel{{Name: "character", Value: "Tester"}, {Name: "skill", Value: "Fire Bolt"}},
+			Value:  120,
+		},
+		{
+			Name:   "pgct_dps",
+			Help:   "Current damage per second",
+			Type:   abstract.MetricGauge,
+			Labels: []abstract.MetricLabel{{Name: "character", Value: "Tester"}},
+			Value:  12.5,
+		},
+		{
+			Name:   "pgct_damage_dealt",
+			Help:   "Damage dealt by a skill",
+			Type:   abstract.MetricCounter,
+			Labels: []abstract.MetricLabel{{Name: "character", Value: "Tester"}, {Name: "skill", Value: "Odd \"Skill\" \\ with\nlines"}},
+			Value:  3,
+		},
+	}
+
+	var buffer bytes.Buffer
+	writeMetrics(&buffer, metrics)
+
+	expected := `# TYPE pgct_damage_dealt counter
+# HELP pgct_damage_dealt Damage dealt by a skill
+pgct_damage_dealt_total{character="Tester",skill="Fire Bolt"} 120
+pgct_damage_dealt_total{character="Tester",skill="Odd \"Skill\" \\ with\nlines"} 3
+# TYPE pgct_dps gauge
+# HELP pgct_dps Current damage per second
+pgct_dps{character="Tester"} 12.5
+# EOF
+`
+
+	if got := buffer.String(); got != expected {
+		t.Errorf("unexpected output:\n%v\nexpected:\n%v", got, expected)
+	}
+}
+
+func TestWriteMetricsWithoutSamples(t *testing.T) {
+	var buffer bytes.Buffer
+	writeMetrics(&buffer, nil)
+
+	if got := buffer.String(); got != "# EOF\n" {
+		t.Errorf("expected only the end marker, got %q", got)
+	}
+}
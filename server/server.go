@@ -0,0 +1,470 @@
+package server
+
+import (
+	"PGCombatTracker/abstract"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed overlay.html
+var overlayPage []byte
+
+const (
+	defaultBindAddress = "127.0.0.1"
+	defaultPort        = 8765
+	// settingsInterval How often settings are checked for the server being turned on, off or moved
+	settingsInterval = time.Second
+	// broadcastInterval Updates that come faster than this are merged into one
+	broadcastInterval = 250 * time.Millisecond
+)
+
+func NewServer(state abstract.GlobalState) *Server {
+	return &Server{
+		state:   state,
+		pending: make(chan struct{}, 1),
+		clients: make(map[*client]bool),
+	}
+}
+
+// Server Optional local API with the current file's data as JSON, live updates over a WebSocket, metrics
+// and an overlay page, started and stopped by settings
+type Server struct {
+	state   abstract.GlobalState
+	pending chan struct{}
+
+	lock    sync.Mutex
+	running *http.Server
+	address string
+	// failedAddress Address that couldn't be listened on, so the error is only logged once
+	failedAddress string
+	clients       map[*client]bool
+}
+
+// client WebSocket connection, updates are dropped while the client is still busy with an older one
+type client struct {
+	ws        *websocketConn
+	writeLock sync.Mutex
+	updates   chan []byte
+}
+
+func (c *client) write(opcode byte, payload []byte) error {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	return c.ws.writeFrame(opcode, payload)
+}
+
+func (c *client) send(update []byte) {
+	select {
+	case <-c.updates:
+	default:
+	}
+
+	select {
+	case c.updates <- update:
+	default:
+	}
+}
+
+// Run Follows settings until the app exits, has to be called in its own goroutine
+func (s *Server) Run() {
+	go s.broadcastLoop()
+
+	for {
+		s.applySettings()
+		time.Sleep(settingsInterval)
+	}
+}
+
+func (s *Server) bindHost() string {
+	host := strings.TrimSpace(s.state.SavedSettings().APIBindAddress)
+	if host == "" {
+		return defaultBindAddress
+	}
+	return host
+}
+
+func (s *Server) wantedAddress() string {
+	settings := s.state.SavedSettings()
+	if !settings.APIEnabled && !settings.MetricsEnabled {
+		return ""
+	}
+
+	host := s.bindHost()
+
+	port := settings.APIPort
+	if port <= 0 {
+		port = defaultPort
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+func (s *Server) applySettings() {
+	address := s.wantedAddress()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if address == s.address && (s.running != nil || address == s.failedAddress) {
+		return
+	}
+
+	s.stop()
+	s.address = address
+
+	if address == "" {
+		s.failedAddress = ""
+		return
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		if s.failedAddress != address {
+			log.Printf("Can't start local API on %v: %v\n", address, err)
+		}
+		s.failedAddress = address
+		return
+	}
+
+	s.failedAddress = ""
+	s.running = &http.Server{
+		Handler:           s.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	log.Printf("Local API is listening on http://%v\n", address)
+
+	go func(running *http.Server) {
+		err := running.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Local API stopped: %v\n", err)
+		}
+	}(s.running)
+}
+
+// stop Closes the server and WebSockets, which the server doesn't track after upgrading them
+func (s *Server) stop() {
+	if s.running == nil {
+		return
+	}
+
+	err := s.running.Close()
+	if err != nil {
+		log.Printf("Error stopping local API: %v\n", err)
+	}
+	s.running = nil
+
+	for c := range s.clients {
+		c.ws.Close()
+		delete(s.clients, c)
+	}
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	api := func(handler http.HandlerFunc) http.HandlerFunc {
+		return s.enabledBy(func(settings abstract.Settings) bool { return settings.APIEnabled }, handler)
+	}
+
+	mux.HandleFunc("GET /api/status", api(s.handleStatus))
+	mux.HandleFunc("GET /api/collectors", api(s.handleCollectors))
+	mux.HandleFunc("GET /api/collectors/{name}", api(s.handleCollector))
+	mux.HandleFunc("GET /api/markers", api(s.handleMarkers))
+	mux.HandleFunc("GET /ws", api(s.handleWebsocket))
+	mux.HandleFunc("GET /overlay", api(s.handleOverlay))
+	mux.HandleFunc("GET /{$}", api(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/overlay", http.StatusFound)
+	}))
+	mux.HandleFunc("GET /metrics", s.enabledBy(func(settings abstract.Settings) bool { return settings.MetricsEnabled }, s.handleMetrics))
+
+	return s.sameOrigin(mux)
+}
+
+// allowedHost Host has to be the bind address, so pages on other sites can't reach the API by rebinding their domain to it
+func (s *Server) allowedHost(hostPort string) bool {
+	host, _, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		host = hostPort
+	}
+	host = strings.Trim(host, "[]")
+
+	bind := s.bindHost()
+	if strings.EqualFold(host, bind) {
+		return true
+	}
+
+	bindIP := net.ParseIP(bind)
+	if bindIP == nil {
+		return false
+	}
+
+	if bindIP.IsLoopback() {
+		return strings.EqualFold(host, "localhost") || net.ParseIP(host).IsLoopback()
+	}
+
+	// Listening on every interface, any address of this machine can be used, but not a name
+	return bindIP.IsUnspecified() && net.ParseIP(host) != nil
+}
+
+// sameOrigin Browsers send Origin with WebSocket and cross site requests, only the API's own pages are let through
+func (s *Server) sameOrigin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.allowedHost(r.Host) {
+			http.Error(w, "Unexpected host", http.StatusForbidden)
+			return
+		}
+
+		if origin := r.Header.Get("Origin"); origin != "" {
+			parsed, err := url.Parse(origin)
+			if err != nil || !strings.EqualFold(parsed.Host, r.Host) {
+				http.Error(w, "Requests from other sites aren't allowed", http.StatusForbidden)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// enabledBy Server runs if either the API or metrics are enabled, the other one's endpoints stay hidden
+func (s *Server) enabledBy(enabled func(settings abstract.Settings) bool, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !enabled(s.state.SavedSettings()) {
+			http.NotFound(w, r)
+			return
+		}
+
+		handler(w, r)
+	}
+}
+
+// Broadcast Sends current data to WebSocket clients, can be called as often as the collector notifies
+func (s *Server) Broadcast() {
+	select {
+	case s.pending <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Server) broadcastLoop() {
+	for range s.pending {
+		s.lock.Lock()
+		clients := make([]*client, 0, len(s.clients))
+		for c := range s.clients {
+			clients = append(clients, c)
+		}
+		s.lock.Unlock()
+
+		if len(clients) > 0 {
+			update, err := json.Marshal(s.update())
+			if err != nil {
+				log.Printf("Error encoding update: %v\n", err)
+			} else {
+				for _, c := range clients {
+					c.send(update)
+				}
+			}
+		}
+
+		time.Sleep(broadcastInterval)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(w).Encode(value)
+	if err != nil {
+		log.Printf("Error writing API response: %v\n", err)
+	}
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.status())
+}
+
+func (s *Server) handleCollectors(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.collectors())
+}
+
+func (s *Server) handleCollector(w http.ResponseWriter, r *http.Request) {
+	name := collectorKey(r.PathValue("name"))
+
+	for tabName, snapshot := range s.collectors() {
+		if collectorKey(tabName) == name {
+			writeJSON(w, snapshot)
+			return
+		}
+	}
+
+	http.Error(w, "No such collector", http.StatusNotFound)
+}
+
+func (s *Server) handleMarkers(w http.ResponseWriter, r *http.Request) {
+	stats := s.state.StatisticsCollector()
+	if stats == nil {
+		writeJSON(w, []abstract.Marker{})
+		return
+	}
+
+	markers, err := s.state.FindMarkers(stats.Identity().Path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if markers == nil {
+		markers = []abstract.Marker{}
+	}
+
+	writeJSON(w, markers)
+}
+
+func (s *Server) handleOverlay(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(overlayPage)
+}
+
+func (s *Server) handleWebsocket(w http.ResponseWriter, r *http.Request) {
+	ws, err := upgradeWebsocket(w, r)
+	if err != nil {
+		return
+	}
+
+	c := &client{
+		ws:      ws,
+		updates: make(chan []byte, 1),
+	}
+
+	s.lock.Lock()
+	s.clients[c] = true
+	s.lock.Unlock()
+
+	// Client gets the current state right away instead of waiting for the next change
+	update, err := json.Marshal(s.update())
+	if err == nil {
+		c.send(update)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ws.readLoop(c.write)
+		close(done)
+	}()
+
+	defer func() {
+		s.lock.Lock()
+		delete(s.clients, c)
+		s.lock.Unlock()
+		ws.Close()
+	}()
+
+	for {
+		select {
+		case <-done:
+			return
+		case update := <-c.updates:
+			if c.write(opText, update) != nil {
+				return
+			}
+		}
+	}
+}
+
+// collectorKey Collectors can be asked for by tab name in any case, with spaces written as dashes or left out
+func collectorKey(name string) string {
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(name))
+}
+
+type statusSnapshot struct {
+	Open           bool
+	Alive          bool
+	Path           string
+	Character      string
+	IdentitySource string
+	Selected       string
+	Characters     []string
+	Sessions       []abstract.CharacterSession
+}
+
+type updateSnapshot struct {
+	Status     statusSnapshot
+	Collectors map[string]any
+}
+
+func (s *Server) status() statusSnapshot {
+	stats := s.state.StatisticsCollector()
+	if stats == nil {
+		return statusSnapshot{}
+	}
+
+	stats.Mutex().RLock()
+	defer stats.Mutex().RUnlock()
+
+	return readStatus(stats)
+}
+
+func readStatus(stats abstract.StatisticsCollector) statusSnapshot {
+	identity := stats.Identity()
+
+	return statusSnapshot{
+		Open:           true,
+		Alive:          stats.IsAlive(),
+		Path:           identity.Path,
+		Character:      identity.Name,
+		IdentitySource: identity.Source.String(),
+		Selected:       stats.SelectedCharacter(),
+		Characters:     stats.Characters(),
+		Sessions:       stats.Sessions(),
+	}
+}
+
+// collectors Data of the collectors for the character selected in the UI, keyed by tab name
+func (s *Server) collectors() map[string]any {
+	stats := s.state.StatisticsCollector()
+	if stats == nil {
+		return map[string]any{}
+	}
+
+	stats.Mutex().RLock()
+	defer stats.Mutex().RUnlock()
+
+	return readCollectors(stats)
+}
+
+func readCollectors(stats abstract.StatisticsCollector) map[string]any {
+	snapshots := make(map[string]any)
+
+	for _, collector := range stats.Collectors() {
+		if source, ok := collector.(abstract.SnapshotCollector); ok {
+			snapshots[collector.TabName()] = source.Snapshot()
+		}
+	}
+
+	return snapshots
+}
+
+func (s *Server) update() updateSnapshot {
+	stats := s.state.StatisticsCollector()
+	if stats == nil {
+		return updateSnapshot{Collectors: map[string]any{}}
+	}
+
+	stats.Mutex().RLock()
+	defer stats.Mutex().RUnlock()
+
+	return updateSnapshot{
+		Status:     readStatus(stats),
+		Collectors: readCollectors(stats),
+	}
+}
@@ -0,0 +1,695 @@
+package ui
+
+import (
+	"PGCombatTracker/abstract"
+	"PGCombatTracker/ui/components"
+	"PGCombatTracker/utils"
+	"fmt"
+	"gioui.org/app"
+	"gioui.org/layout"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/samber/lo"
+	"golang.org/x/exp/shiny/materialdesign/icons"
+	"image"
+	"image/color"
+	"image/draw"
+	"log"
+	"math"
+	"path/filepath"
+	"time"
+)
+
+// comparisonBaselineKey Storage key of the side that was picked on markers page to be compared against later
+const comparisonBaselineKey = "comparisonBaseline"
+
+const shownComparedFights = 20
+
+var firstSideColor = color.NRGBA{R: 80, G: 160, B: 255, A: 255}
+var secondSideColor = color.NRGBA{R: 255, G: 140, B: 40, A: 255}
+
+// comparisonSide File and time frames that one side of the comparison reads
+type comparisonSide struct {
+	path       string
+	timeFrames []abstract.MarkerTimeFrame
+}
+
+func (c comparisonSide) String() string {
+	name := filepath.Base(c.path)
+
+	if len(c.timeFrames) == 0 || c.timeFrames[0].From.IsZero() {
+		return name
+	}
+
+	return fmt.Sprintf("%v from %v", name, c.timeFrames[0].From.Format(time.DateTime))
+}
+
+type comparisonView uint8
+
+const (
+	ViewSideBySide comparisonView = iota
+	ViewDifferences
+	ViewFights
+)
+
+func (c comparisonView) String() string {
+	switch c {
+	case ViewSideBySide:
+		return "Side by Side"
+	case ViewDifferences:
+		return "Differences"
+	case ViewFights:
+		return "Fight DPS"
+	}
+
+	return ""
+}
+
+// comparisonDelta Row that is present on at least one side, missing side has zero
+type comparisonDelta struct {
+	metric        string
+	name          string
+	first         float64
+	second        float64
+	lowerIsBetter bool
+}
+
+func compareRows(first, second []abstract.ComparisonRow) []comparisonDelta {
+	var deltas []comparisonDelta
+
+	add := func(row abstract.ComparisonRow, update func(delta comparisonDelta) comparisonDelta) {
+		deltas = utils.CreateUpdate(
+			deltas,
+			func(delta comparisonDelta) bool {
+				return delta.metric == row.Metric && delta.name == row.Name
+			},
+			func() comparisonDelta {
+				return update(comparisonDelta{
+					metric:        row.Metric,
+					name:          row.Name,
+					lowerIsBetter: row.LowerIsBetter,
+				})
+			},
+			update,
+		)
+	}
+
+	for _, row := range first {
+		add(row, func(delta comparisonDelta) comparisonDelta {
+			delta.first = row.Value
+			return delta
+		})
+	}
+
+	for _, row := range second {
+		add(row, func(delta comparisonDelta) comparisonDelta {
+			delta.second = row.Value
+			return delta
+		})
+	}
+
+	return deltas
+}
+
+func formatComparisonValue(value float64) string {
+	if value != math.Trunc(value) {
+		return fmt.Sprintf("%.1f", value)
+	}
+	return utils.FormatNumber(int(value))
+}
+
+func (d comparisonDelta) difference() string {
+	change := d.second - d.first
+
+	sign := ""
+	if change > 0 {
+		sign = "+"
+	} else if change < 0 {
+		sign = "-"
+	}
+
+	if d.first == 0 {
+		return fmt.Sprintf("%v%v (new)", sign, formatComparisonValue(math.Abs(change)))
+	}
+
+	return fmt.Sprintf("%v%v (%+.1f%%)", sign, formatComparisonValue(math.Abs(change)), change/d.first*100)
+}
+
+func (d comparisonDelta) color() color.NRGBA {
+	change := d.second - d.first
+
+	if change == 0 {
+		return utils.GrayText
+	}
+
+	if (change > 0) != d.lowerIsBetter {
+		return utils.GreenText
+	}
+
+	return utils.RedText
+}
+
+type fightPointDetail struct {
+	offset time.Duration
+	dps    int
+}
+
+func (f fightPointDetail) StringCL(long bool) string {
+	if long {
+		return fmt.Sprintf("%v in: %d DPS", f.offset, f.dps)
+	}
+	return fmt.Sprintf("%v in: %v DPS", f.offset.Round(time.Second), utils.FormatNumber(f.dps))
+}
+
+func (f fightPointDetail) Interpolate(other utils.Interpolatable, t float64) utils.Interpolatable {
+	otherDetail, ok := other.(fightPointDetail)
+	if !ok {
+		return other
+	}
+
+	return fightPointDetail{
+		offset: time.Duration(utils.LerpInt(int(f.offset), int(otherDetail.offset), t)),
+		dps:    utils.LerpInt(f.dps, otherDetail.dps, t),
+	}
+}
+
+func (f fightPointDetail) InterpolateILF(other utils.InterpolatableLongFormatable, t float64) utils.InterpolatableLongFormatable {
+	return f.Interpolate(other, t).(utils.InterpolatableLongFormatable)
+}
+
+// fightOverlay DPS of the same fight on both sides, moved so both fights start at zero
+type fightOverlay struct {
+	first  *components.TimeBasedChart
+	second *components.TimeBasedChart
+}
+
+func alignedFightChart(name string, points []components.TimePoint) *components.TimeBasedChart {
+	chart := components.NewTimeBasedChart(name)
+	start := points[0].Time
+
+	for _, point := range points {
+		offset := point.Time.Sub(start)
+		chart.DataPoints = append(chart.DataPoints, components.TimePoint{
+			Time:    time.Time{}.Add(offset),
+			Value:   point.Value,
+			Details: fightPointDetail{offset: offset, dps: point.Value},
+		})
+	}
+
+	return chart
+}
+
+func newFightOverlay(first, second []components.TimePoint) fightOverlay {
+	overlay := fightOverlay{
+		first:  alignedFightChart("First", first),
+		second: alignedFightChart("Second", second),
+	}
+
+	var frame components.TimeFrame
+	var valueRange components.DataRange
+	for _, chart := range []*components.TimeBasedChart{overlay.first, overlay.second} {
+		for _, point := range chart.DataPoints {
+			frame = frame.Expand(point.Time)
+			valueRange = valueRange.Expand(point.Value)
+		}
+	}
+
+	for _, chart := range []*components.TimeBasedChart{overlay.first, overlay.second} {
+		chart.DisplayTimeFrame = frame
+		chart.DisplayValueRange = valueRange
+	}
+
+	return overlay
+}
+
+type ComparisonPage struct {
+	sides            [2]comparisonSide
+	stats            [2]abstract.StatisticsCollector
+	currentCollector int
+	currentView      comparisonView
+	fights           []fightOverlay
+	fightCounts      [2]int
+	fightsBuilt      bool
+
+	modalLayer        *components.ModalLayer
+	backIcon          *widget.Icon
+	backButton        *widget.Clickable
+	copyIcon          *widget.Icon
+	copyButton        *widget.Clickable
+	collectorDropdown *components.Dropdown
+	viewDropdown      *components.Dropdown
+	sideBodies        [2]*widget.List
+	differenceList    *widget.List
+	fightList         *widget.List
+}
+
+// NewComparisonPage Reads both sides with their own collectors, they don't replace the collector of statistics page
+func NewComparisonPage(state abstract.GlobalState, first, second comparisonSide) (*ComparisonPage, error) {
+	backIcon, err := widget.NewIcon(icons.NavigationArrowBack)
+	if err != nil {
+		return nil, err
+	}
+
+	copyIcon, err := widget.NewIcon(icons.ContentContentCopy)
+	if err != nil {
+		return nil, err
+	}
+
+	viewDropdown, err := components.NewDropdown("View", ViewSideBySide, ViewDifferences, ViewFights)
+	if err != nil {
+		return nil, err
+	}
+
+	collectorDropdown, err := components.NewDropdown("Page", CollectorPageIndex{})
+	if err != nil {
+		return nil, err
+	}
+
+	page := &ComparisonPage{
+		sides:             [2]comparisonSide{first, second},
+		modalLayer:        components.NewModalLayer(),
+		backIcon:          backIcon,
+		backButton:        &widget.Clickable{},
+		copyIcon:          copyIcon,
+		copyButton:        &widget.Clickable{},
+		collectorDropdown: collectorDropdown,
+		viewDropdown:      viewDropdown,
+		sideBodies:        [2]*widget.List{getFreshCollectorBody(), getFreshCollectorBody()},
+		differenceList:    getFreshCollectorBody(),
+		fightList:         getFreshCollectorBody(),
+	}
+
+	for i, side := range page.sides {
+		stats, err := state.CreateStatisticsCollector(side.path, side.timeFrames)
+		if err != nil {
+			page.close()
+			return nil, err
+		}
+
+		stats.Run()
+		page.stats[i] = stats
+
+		go func() {
+			for range stats.Notify() {
+				state.Window().Invalidate()
+			}
+		}()
+	}
+
+	lock := page.stats[0].Mutex()
+	lock.RLock()
+	options := lo.Map(
+		page.stats[0].Collectors(),
+		func(item abstract.Collector, index int) fmt.Stringer {
+			return CollectorPageIndex{
+				index: index,
+				name:  item.TabName(),
+			}
+		},
+	)
+	lock.RUnlock()
+
+	collectorDropdown.Value = options[0]
+	collectorDropdown.SetOptions(options)
+
+	return page, nil
+}
+
+func (p *ComparisonPage) close() {
+	for _, stats := range p.stats {
+		if stats != nil && stats.IsAlive() {
+			stats.Close()
+		}
+	}
+}
+
+func (p *ComparisonPage) goBack(state abstract.GlobalState) {
+	p.close()
+
+	path := p.sides[1].path
+	markers, err := state.FindMarkers(path)
+	if err != nil {
+		log.Printf("Failed to open markers page: %v\n", err)
+		state.SwitchPage(NewFileSelectionPage())
+		return
+	}
+
+	state.SwitchPage(NewMarkersPage(path, markers))
+}
+
+// withCollector Runs the function with current tab of the side while the side is locked
+func (p *ComparisonPage) withCollector(side int, function func(collector abstract.Collector)) {
+	stats := p.stats[side]
+	lock := stats.Mutex()
+
+	lock.RLock()
+	defer lock.RUnlock()
+
+	collectors := stats.Collectors()
+	if p.currentCollector < len(collectors) {
+		function(collectors[p.currentCollector])
+	}
+}
+
+func (p *ComparisonPage) exportToClipboard(state abstract.LayeredState) {
+	var images []image.Image
+	for side := range p.stats {
+		p.withCollector(side, func(collector abstract.Collector) {
+			images = append(images, collector.Export(state))
+		})
+	}
+
+	var size image.Point
+	for _, img := range images {
+		size.X += img.Bounds().Dx()
+		size.Y = max(size.Y, img.Bounds().Dy())
+	}
+
+	combined := image.NewRGBA(image.Rectangle{Max: size})
+	var offset int
+	for _, img := range images {
+		bounds := img.Bounds()
+		draw.Draw(combined, bounds.Sub(bounds.Min).Add(image.Point{X: offset}), img, bounds.Min, draw.Src)
+		offset += bounds.Dx()
+	}
+
+	copyImageToClipboard(combined)
+}
+
+func (p *ComparisonPage) navBar(state abstract.LayeredState) layout.Widget {
+	return func(gtx layout.Context) layout.Dimensions {
+		if p.collectorDropdown.Changed() {
+			p.currentCollector = p.collectorDropdown.Value.(CollectorPageIndex).index
+			p.sideBodies = [2]*widget.List{getFreshCollectorBody(), getFreshCollectorBody()}
+			p.differenceList = getFreshCollectorBody()
+		}
+
+		if p.viewDropdown.Changed() {
+			p.currentView = p.viewDropdown.Value.(comparisonView)
+
+			// Subject picked in damage tab decides whose fights are shown, so they are built again
+			p.fights = nil
+			p.fightsBuilt = false
+		}
+
+		dropdown := func(dropdown *components.Dropdown) layout.Widget {
+			style := components.StyleDropdown(state.Theme(), state.ModalLayer(), dropdown)
+			style.NoLabel = true
+			style.Inset = layout.UniformInset(7)
+			style.TextSize = 12
+			style.DialogTextSize = 12
+			return style.Layout
+		}
+
+		return layout.Background{}.Layout(
+			gtx,
+			utils.MakeColoredBG(utils.SecondBG),
+			func(gtx layout.Context) layout.Dimensions {
+				return utils.LayoutDefinedHeight(gtx, gtx.Dp(40), func(gtx layout.Context) layout.Dimensions {
+					return layout.Flex{
+						Axis: layout.Horizontal,
+					}.Layout(
+						gtx,
+						layout.Rigid(navIconButton(state, p.backButton, p.backIcon, "Back").Layout),
+						utils.FlexSpacerW(utils.CommonSpacing),
+						layout.Rigid(navIconButton(state, p.copyButton, p.copyIcon, "Copy").Layout),
+						utils.FlexSpacerW(utils.CommonSpacing),
+						layout.Flexed(1, dropdown(p.collectorDropdown)),
+						utils.FlexSpacerW(utils.CommonSpacing),
+						layout.Flexed(1, dropdown(p.viewDropdown)),
+					)
+				})
+			},
+		)
+	}
+}
+
+func (p *ComparisonPage) sideLabel(state abstract.GlobalState, side int) layout.Widget {
+	return func(gtx layout.Context) layout.Dimensions {
+		sideColor := firstSideColor
+		prefix := "First"
+		if side == 1 {
+			sideColor = secondSideColor
+			prefix = "Second"
+		}
+
+		return layout.UniformInset(utils.CommonSpacing).Layout(
+			gtx,
+			utils.WithColor(
+				material.Label(state.Theme(), 14, fmt.Sprintf("%v: %v", prefix, p.sides[side])),
+				sideColor,
+			).Layout,
+		)
+	}
+}
+
+func (p *ComparisonPage) sideUI(state abstract.LayeredState, side int) layout.Widget {
+	return func(gtx layout.Context) layout.Dimensions {
+		var dims layout.Dimensions
+
+		p.withCollector(side, func(collector abstract.Collector) {
+			top, body := collector.UI(state)
+
+			dims = layout.Flex{
+				Axis: layout.Vertical,
+			}.Layout(
+				gtx,
+				layout.Rigid(p.sideLabel(state, side)),
+				layout.Rigid(top),
+				utils.FlexSpacerH(utils.CommonSpacing),
+				layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+					return layout.Inset{
+						Left: utils.CommonSpacing, Right: utils.CommonSpacing,
+					}.Layout(
+						gtx,
+						func(gtx layout.Context) layout.Dimensions {
+							return material.List(state.Theme(), p.sideBodies[side]).Layout(
+								gtx,
+								len(body),
+								func(gtx layout.Context, index int) layout.Dimensions {
+									return body[index](gtx)
+								},
+							)
+						},
+					)
+				}),
+			)
+		})
+
+		return dims
+	}
+}
+
+func (p *ComparisonPage) differencesUI(state abstract.LayeredState) layout.Widget {
+	return func(gtx layout.Context) layout.Dimensions {
+		var rows [2][]abstract.ComparisonRow
+		comparable := true
+
+		for side := range p.stats {
+			p.withCollector(side, func(collector abstract.Collector) {
+				if comparableCollector, ok := collector.(abstract.ComparableCollector); ok {
+					rows[side] = comparableCollector.ComparisonRows()
+				} else {
+					comparable = false
+				}
+			})
+		}
+
+		widgets := []layout.Widget{p.sideLabel(state, 0), p.sideLabel(state, 1)}
+
+		if !comparable {
+			widgets = append(widgets, func(gtx layout.Context) layout.Dimensions {
+				return layout.UniformInset(utils.CommonSpacing).Layout(
+					gtx,
+					material.Label(state.Theme(), 14, "This page can't be compared row by row, use side by side view").Layout,
+				)
+			})
+		}
+
+		var lastMetric string
+		for _, delta := range compareRows(rows[0], rows[1]) {
+			if delta.metric != lastMetric {
+				lastMetric = delta.metric
+				widgets = append(widgets, func(gtx layout.Context) layout.Dimensions {
+					return layout.UniformInset(utils.CommonSpacing).Layout(
+						gtx,
+						material.Label(state.Theme(), 14, delta.metric).Layout,
+					)
+				})
+			}
+
+			widgets = append(widgets, func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{
+					Left: utils.CommonSpacing * 2, Right: utils.CommonSpacing * 2,
+					Bottom: utils.CommonSpacing,
+				}.Layout(
+					gtx,
+					func(gtx layout.Context) layout.Dimensions {
+						return layout.Flex{
+							Axis:      layout.Horizontal,
+							Alignment: layout.Middle,
+						}.Layout(
+							gtx,
+							layout.Flexed(1, material.Label(state.Theme(), 12, delta.name).Layout),
+							layout.Rigid(utils.WithColor(
+								material.Label(state.Theme(), 12, formatComparisonValue(delta.first)),
+								firstSideColor,
+							).Layout),
+							layout.Rigid(material.Label(state.Theme(), 12, " → ").Layout),
+							layout.Rigid(utils.WithColor(
+								material.Label(state.Theme(), 12, formatComparisonValue(delta.second)),
+								secondSideColor,
+							).Layout),
+							utils.FlexSpacerW(utils.CommonSpacing*4),
+							layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+								gtx.Constraints.Min.X = gtx.Dp(140)
+								return utils.WithColor(
+									material.Label(state.Theme(), 12, delta.difference()),
+									delta.color(),
+								).Layout(gtx)
+							}),
+						)
+					},
+				)
+			})
+		}
+
+		return material.List(state.Theme(), p.differenceList).Layout(
+			gtx,
+			len(widgets),
+			func(gtx layout.Context, index int) layout.Dimensions {
+				return widgets[index](gtx)
+			},
+		)
+	}
+}
+
+// buildFights Charts are made once both sides are read completely, so they don't have to be rebuilt every frame
+func (p *ComparisonPage) buildFights() {
+	var fights [2][][]components.TimePoint
+
+	for side, stats := range p.stats {
+		if stats.IsAlive() {
+			return
+		}
+
+		for _, collector := range stats.Collectors() {
+			if source, ok := collector.(abstract.FightSource); ok {
+				fights[side] = source.FightDPS()
+				break
+			}
+		}
+	}
+
+	p.fightCounts = [2]int{len(fights[0]), len(fights[1])}
+	for i := 0; i < min(len(fights[0]), len(fights[1]), shownComparedFights); i++ {
+		p.fights = append(p.fights, newFightOverlay(fights[0][i], fights[1][i]))
+	}
+	p.fightsBuilt = true
+}
+
+func (p *ComparisonPage) fightsUI(state abstract.LayeredState) layout.Widget {
+	return func(gtx layout.Context) layout.Dimensions {
+		if !p.fightsBuilt {
+			p.buildFights()
+		}
+
+		text := func(text string) layout.Widget {
+			return func(gtx layout.Context) layout.Dimensions {
+				return layout.UniformInset(utils.CommonSpacing).Layout(
+					gtx,
+					material.Label(state.Theme(), 14, text).Layout,
+				)
+			}
+		}
+
+		widgets := []layout.Widget{p.sideLabel(state, 0), p.sideLabel(state, 1)}
+
+		if !p.fightsBuilt {
+			widgets = append(widgets, text("Still reading the files..."))
+		} else {
+			widgets = append(widgets, text(fmt.Sprintf(
+				"First has %d fights, second has %d, fights are matched in order and aligned to their start",
+				p.fightCounts[0], p.fightCounts[1],
+			)))
+		}
+
+		for i, fight := range p.fights {
+			widgets = append(
+				widgets,
+				text(fmt.Sprintf("Fight %d", i+1)),
+				func(gtx layout.Context) layout.Dimensions {
+					first := components.StyleTimeBasedChart(state.Theme(), fight.first)
+					first.Color = firstSideColor
+					first.Alpha = 100
+
+					second := components.StyleTimeBasedChart(state.Theme(), fight.second)
+					second.Color = secondSideColor
+					second.Alpha = 100
+					second.BackgroundAlpha = 0
+
+					return layout.Stack{}.Layout(
+						gtx,
+						layout.Stacked(first.Layout),
+						layout.Expanded(second.Layout),
+					)
+				},
+			)
+		}
+
+		return material.List(state.Theme(), p.fightList).Layout(
+			gtx,
+			len(widgets),
+			func(gtx layout.Context, index int) layout.Dimensions {
+				return widgets[index](gtx)
+			},
+		)
+	}
+}
+
+func (p *ComparisonPage) Layout(ctx layout.Context, state abstract.GlobalState) error {
+	if p.backButton.Clicked(ctx) {
+		p.goBack(state)
+	}
+
+	layeredState := NewLayeredState(state, p.modalLayer)
+
+	if p.copyButton.Clicked(ctx) {
+		p.exportToClipboard(layeredState)
+	}
+
+	p.modalLayer.Overlay(func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{
+			Axis: layout.Vertical,
+		}.Layout(
+			gtx,
+			layout.Rigid(p.navBar(layeredState)),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				switch p.currentView {
+				case ViewDifferences:
+					return p.differencesUI(layeredState)(gtx)
+				case ViewFights:
+					return p.fightsUI(layeredState)(gtx)
+				}
+
+				return layout.Flex{
+					Axis: layout.Horizontal,
+				}.Layout(
+					gtx,
+					layout.Flexed(1, p.sideUI(layeredState, 0)),
+					layout.Rigid(utils.MakeVerticalSeparator(2, utils.CommonSpacing)),
+					layout.Flexed(1, p.sideUI(layeredState, 1)),
+				)
+			}),
+		)
+	})(ctx)
+
+	return nil
+}
+
+func (p *ComparisonPage) SetupWindow(state abstract.GlobalState) {
+	state.Window().Option(
+		app.MinSize(1000, 600),
+		app.Decorated(true),
+	)
+}
@@ -59,3 +59,79 @@ func BarWidget(color color.NRGBA, height unit.Dp, progress float64) layout.Widge
 		}
 	}
 }
+
+type BarSegment struct {
+	Color    color.NRGBA
+	Progress float64
+}
+
+// SegmentedBarWidget Bar made out of several segments placed one after another
+func SegmentedBarWidget(height unit.Dp, segments ...BarSegment) layout.Widget {
+	return func(gtx layout.Context) layout.Dimensions {
+		maxWidth := gtx.Constraints.Max.X
+		height := gtx.Dp(height)
+
+		var total float64
+		for _, segment := range segments {
+			total += segment.Progress
+		}
+		fullWidth := int(math.Floor(float64(maxWidth) * min(1, total)))
+
+		defer clip.UniformRRect(image.Rectangle{
+			Max: image.Point{X: fullWidth, Y: height},
+		}, min(5, fullWidth)).Push(gtx.Ops).Pop()
+
+		x := 0
+		for _, segment := range segments {
+			width := int(math.Floor(float64(maxWidth) * segment.Progress))
+
+			rect := clip.Rect{
+				Min: image.Point{X: x},
+				Max: image.Point{X: min(x+width, fullWidth), Y: height},
+			}.Push(gtx.Ops)
+			paint.Fill(gtx.Ops, segment.Color)
+			rect.Pop()
+
+			x += width
+		}
+
+		return layout.Dimensions{
+			Size: image.Point{
+				X: maxWidth,
+				Y: height,
+			},
+		}
+	}
+}
+
+// TimelineLaneWidget Lane that marks spans of time within the time frame, spans without length are drawn as thin marks
+func TimelineLaneWidget(color color.NRGBA, height unit.Dp, frame TimeFrame, spans ...TimeFrame) layout.Widget {
+	return func(gtx layout.Context) layout.Dimensions {
+		maxWidth := gtx.Constraints.Max.X
+		height := gtx.Dp(height)
+		markWidth := max(1, gtx.Dp(2))
+
+		for _, span := range spans {
+			if span.To.Before(frame.From) || span.From.After(frame.To) {
+				continue
+			}
+
+			from := int(math.Floor(float64(maxWidth) * frame.ProportionOfTarget(span.From)))
+			to := int(math.Ceil(float64(maxWidth) * frame.ProportionOfTarget(span.To)))
+
+			rect := clip.Rect{
+				Min: image.Point{X: min(from, maxWidth-markWidth)},
+				Max: image.Point{X: max(to, from+markWidth), Y: height},
+			}.Push(gtx.Ops)
+			paint.Fill(gtx.Ops, color)
+			rect.Pop()
+		}
+
+		return layout.Dimensions{
+			Size: image.Point{
+				X: maxWidth,
+				Y: height,
+			},
+		}
+	}
+}
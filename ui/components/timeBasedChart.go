@@ -127,6 +127,8 @@ type TimeBasedChart struct {
 	DisplayTimeFrame  TimeFrame
 	DisplayValueRange DataRange
 	Name              string
+	// Marks Moments that get a vertical line across the chart, like level ups
+	Marks []time.Time
 
 	CalculatedPoints []f32.Point
 	dirty            bool
@@ -341,6 +343,7 @@ func StyleTimeBasedChart(theme *material.Theme, chart *TimeBasedChart) TimeBased
 		Alpha:           140,
 		BackgroundAlpha: 40,
 		TooltipTextSize: 10,
+		MarkColor:       utils.ChartMarkColor,
 		MinWidth:        300,
 		MinHeight:       100,
 		Inset:           layout.UniformInset(5),
@@ -356,6 +359,7 @@ type TimeBasedChartStyle struct {
 
 	TooltipTextSize unit.Sp
 	LongFormat      bool
+	MarkColor       color.NRGBA
 
 	MinWidth  unit.Dp
 	MinHeight unit.Dp
@@ -573,6 +577,24 @@ func (ts TimeBasedChartStyle) Layout(gtx layout.Context) layout.Dimensions {
 		Path: path.End(),
 	}.Op())
 
+	for _, mark := range ts.chart.Marks {
+		if !ts.chart.DisplayTimeFrame.Within(mark) {
+			continue
+		}
+
+		markX := floatingSizeX * float32(ts.chart.DisplayTimeFrame.ProportionOfTarget(mark))
+
+		markLine := clip.Path{}
+		markLine.Begin(gtx.Ops)
+		markLine.MoveTo(f32.Point{X: markX})
+		markLine.LineTo(f32.Point{X: markX, Y: floatingSizeY})
+
+		paint.FillShape(gtx.Ops, ts.MarkColor, clip.Stroke{
+			Path:  markLine.End(),
+			Width: 2,
+		}.Op())
+	}
+
 	if ts.chart.hoveredPoint != nil {
 		spacing := gtx.Dp(utils.CommonSpacing)
 		hoverPosition := ts.chart.hoverPosition
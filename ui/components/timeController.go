@@ -105,6 +105,9 @@ type TimeController struct {
 
 	LookBackSeconds int
 
+	// Activity Spans of time that get highlighted in a band under the overview chart
+	Activity []TimeFrame
+
 	overviewIcon   *widget.Icon
 	realTimeIcon   *widget.Icon
 	overviewFramer *overviewTimeFramer
@@ -158,6 +161,8 @@ func StyleTimeController(theme *material.Theme, controller *TimeController) Time
 		OverviewChartStyle:   StyleTimeBasedChart(theme, controller.BaseChart),
 		PixelUpdateThreshold: 3,
 		TextSize:             12,
+		ActivityColor:        color.NRGBA{G: 255, B: 255, A: 150},
+		ActivityHeight:       6,
 		theme:                theme,
 		controller:           controller,
 	}
@@ -171,6 +176,8 @@ type TimeControllerStyle struct {
 	OverviewChartStyle   TimeBasedChartStyle
 	PixelUpdateThreshold int
 	TextSize             unit.Sp
+	ActivityColor        color.NRGBA
+	ActivityHeight       unit.Dp
 
 	lastRequestTime time.Time
 	theme           *material.Theme
@@ -206,7 +213,32 @@ func (tcs TimeControllerStyle) Layout(gtx layout.Context) layout.Dimensions {
 		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
 			switch tcs.controller.CurrentMode {
 			case OverviewTimeMode:
-				return tcs.controller.overviewFramer.Layout(gtx, tcs)
+				if len(tcs.controller.Activity) == 0 {
+					return tcs.controller.overviewFramer.Layout(gtx, tcs)
+				}
+
+				return layout.Flex{
+					Axis: layout.Vertical,
+				}.Layout(
+					gtx,
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return tcs.controller.overviewFramer.Layout(gtx, tcs)
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return layout.Inset{
+							Left:  tcs.HandleThickness / 2,
+							Right: tcs.HandleThickness / 2,
+						}.Layout(
+							gtx,
+							TimelineLaneWidget(
+								tcs.ActivityColor,
+								tcs.ActivityHeight,
+								tcs.controller.FullTimeFrame,
+								tcs.controller.Activity...,
+							),
+						)
+					}),
+				)
 			case RealTimeMode:
 				flexItems := make([]layout.FlexChild, 0, len(tcs.controller.realTimeOptions)*2-1)
 
@@ -0,0 +1,178 @@
+package components
+
+import (
+	"PGCombatTracker/utils"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/unit"
+	"gioui.org/widget/material"
+	"image"
+	"slices"
+	"sync"
+	"time"
+)
+
+type toast struct {
+	text  string
+	until time.Time
+	// alert Shown as a banner across the top instead of in the corner
+	alert bool
+}
+
+func NewToaster() *Toaster {
+	return &Toaster{
+		Duration:      8 * time.Second,
+		AlertDuration: 20 * time.Second,
+		MaxWidth:      400,
+		lock:          new(sync.Mutex),
+	}
+}
+
+// Toaster Short messages that show up in a corner, and alerts that show up as banners,
+// both disappear on their own and can be fed from any goroutine
+type Toaster struct {
+	Duration      time.Duration
+	AlertDuration time.Duration
+	MaxWidth      unit.Dp
+
+	lock   *sync.Mutex
+	toasts []toast
+}
+
+func (t *Toaster) Show(text string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.toasts = append(t.toasts, toast{
+		text:  text,
+		until: time.Now().Add(t.Duration),
+	})
+}
+
+func (t *Toaster) Alert(text string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.toasts = append(t.toasts, toast{
+		text:  text,
+		until: time.Now().Add(t.AlertDuration),
+		alert: true,
+	})
+}
+
+// active Removes toasts that timed out and returns the rest
+func (t *Toaster) active(now time.Time) []toast {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.toasts = slices.DeleteFunc(t.toasts, func(item toast) bool {
+		return !item.until.After(now)
+	})
+
+	return slices.Clone(t.toasts)
+}
+
+func layoutToasts(toasts []toast, alert bool, layoutOne func(gtx layout.Context, item toast) layout.Dimensions) layout.Widget {
+	return func(gtx layout.Context) layout.Dimensions {
+		var children []layout.FlexChild
+		for _, item := range toasts {
+			if item.alert != alert {
+				continue
+			}
+
+			children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutOne(gtx, item)
+			}))
+		}
+
+		if len(children) == 0 {
+			return layout.Dimensions{}
+		}
+
+		return layout.Flex{
+			Axis:      layout.Vertical,
+			Alignment: layout.End,
+		}.Layout(gtx, children...)
+	}
+}
+
+// Overlay Draws alerts along the top and toasts in the bottom right corner on top of the widget
+func (t *Toaster) Overlay(theme *material.Theme, bottom layout.Widget) layout.Widget {
+	return func(gtx layout.Context) layout.Dimensions {
+		toasts := t.active(gtx.Now)
+
+		if len(toasts) > 0 {
+			nextExpiry := slices.MinFunc(toasts, func(a, b toast) int {
+				return a.until.Compare(b.until)
+			}).until
+			gtx.Execute(op.InvalidateCmd{At: nextExpiry})
+		}
+
+		// Stack only passes size of the toasts down, bottom should keep the size it was given
+		outerMin := gtx.Constraints.Min
+
+		withToasts := func(gtx layout.Context) layout.Dimensions {
+			return layout.Stack{Alignment: layout.SE}.Layout(
+				gtx,
+				layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+					gtx.Constraints.Min = image.Point{
+						X: max(gtx.Constraints.Min.X, outerMin.X),
+						Y: max(gtx.Constraints.Min.Y, outerMin.Y),
+					}
+					return bottom(gtx)
+				}),
+				layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+					gtx.Constraints.Min.X = 0
+					gtx.Constraints.Max.X = min(gtx.Constraints.Max.X, gtx.Dp(t.MaxWidth))
+
+					return layoutToasts(toasts, false, func(gtx layout.Context, item toast) layout.Dimensions {
+						return layout.UniformInset(utils.CommonSpacing).Layout(
+							gtx,
+							func(gtx layout.Context) layout.Dimensions {
+								return layout.Background{}.Layout(
+									gtx,
+									utils.MakeRoundedBG(10, utils.SecondBG),
+									func(gtx layout.Context) layout.Dimensions {
+										return layout.UniformInset(utils.CommonSpacing*2).Layout(
+											gtx,
+											material.Body1(theme, item.text).Layout,
+										)
+									},
+								)
+							},
+						)
+					})(gtx)
+				}),
+			)
+		}
+
+		return layout.Stack{Alignment: layout.N}.Layout(
+			gtx,
+			layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+				gtx.Constraints.Min = image.Point{
+					X: max(gtx.Constraints.Min.X, outerMin.X),
+					Y: max(gtx.Constraints.Min.Y, outerMin.Y),
+				}
+				return withToasts(gtx)
+			}),
+			layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+				gtx.Constraints.Min.X = gtx.Constraints.Max.X
+
+				return layoutToasts(toasts, true, func(gtx layout.Context, item toast) layout.Dimensions {
+					return layout.Background{}.Layout(
+						gtx,
+						utils.MakeColoredBG(utils.AlertBG),
+						func(gtx layout.Context) layout.Dimensions {
+							gtx.Constraints.Min.X = gtx.Constraints.Max.X
+
+							return layout.UniformInset(utils.CommonSpacing*2).Layout(
+								gtx,
+								material.Body1(theme, item.text).Layout,
+							)
+						},
+					)
+				})(gtx)
+			}),
+		)
+	}
+}
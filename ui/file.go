@@ -3,12 +3,15 @@ package ui
 import (
 	"PGCombatTracker/abstract"
 	"PGCombatTracker/parser"
+	"PGCombatTracker/utils"
 	"bufio"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
 const SettingsLocation = "settings.json"
@@ -63,6 +66,176 @@ func SaveMarkersFile(markers *abstract.Markers) error {
 	return os.WriteFile(MarkersLocation, bs, 0666)
 }
 
+const IdentitiesLocation = "identities.json"
+
+func LoadIdentitiesFile(identities *abstract.Identities) error {
+	data, err := os.ReadFile(IdentitiesLocation)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(data, identities)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func SaveIdentitiesFile(identities *abstract.Identities) error {
+	bs, err := json.Marshal(identities)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(IdentitiesLocation, bs, 0666)
+}
+
+const PetsLocation = "pets.json"
+
+func LoadPetsFile(pets *abstract.LearnedPets) error {
+	data, err := os.ReadFile(PetsLocation)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(data, pets)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func SavePetsFile(pets *abstract.LearnedPets) error {
+	bs, err := json.Marshal(pets)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(PetsLocation, bs, 0666)
+}
+
+const EnemyHealthLocation = "enemyHealth.json"
+
+func LoadEnemyHealthFile(database *abstract.EnemyHealthDatabase) error {
+	data, err := os.ReadFile(EnemyHealthLocation)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(data, database)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func SaveEnemyHealthFile(database *abstract.EnemyHealthDatabase) error {
+	bs, err := json.Marshal(database)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(EnemyHealthLocation, bs, 0666)
+}
+
+const CategoriesLocation = "categories.json"
+
+func LoadCategoriesFile(categories *abstract.SkillCategories) error {
+	data, err := os.ReadFile(CategoriesLocation)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(data, categories)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func SaveCategoriesFile(categories *abstract.SkillCategories) error {
+	bs, err := json.MarshalIndent(categories, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(CategoriesLocation, bs, 0666)
+}
+
+const LibraryLocation = "library.json"
+
+func LoadLibraryFile(library *abstract.SessionLibrary) error {
+	data, err := os.ReadFile(LibraryLocation)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(data, library)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func SaveLibraryFile(library *abstract.SessionLibrary) error {
+	bs, err := json.Marshal(library)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(LibraryLocation, bs, 0666)
+}
+
+const RecordsLocation = "records.json"
+
+func LoadRecordsFile(book *abstract.RecordBook) error {
+	data, err := os.ReadFile(RecordsLocation)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(data, book)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func SaveRecordsFile(book *abstract.RecordBook) error {
+	bs, err := json.Marshal(book)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(RecordsLocation, bs, 0666)
+}
+
+// LoadXPTableFile Reads the table the user supplied, format is picked by file extension
+func LoadXPTableFile(path string) (*abstract.XPTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return abstract.ParseXPTableJSON(data)
+	}
+
+	return abstract.ParseXPTableCSV(data)
+}
+
 func PrereadLogsFile(path string) ([]abstract.Marker, error) {
 	file, err := os.Open(path)
 	defer func(file *os.File) {
@@ -77,6 +250,7 @@ func PrereadLogsFile(path string) ([]abstract.Marker, error) {
 	}
 
 	var markers []abstract.Marker
+	var currentUser string
 	reader := bufio.NewReader(file)
 
 	for {
@@ -97,6 +271,7 @@ func PrereadLogsFile(path string) ([]abstract.Marker, error) {
 		}
 
 		if login, ok := event.Contents.(*abstract.Login); ok {
+			currentUser = login.Name
 			markers = append(markers, abstract.Marker{
 				Time: event.Time,
 				Name: fmt.Sprintf("Logged in as %v", login.Name),
@@ -104,6 +279,15 @@ func PrereadLogsFile(path string) ([]abstract.Marker, error) {
 			})
 		}
 
+		if areaChange, ok := event.Contents.(*abstract.AreaChange); ok {
+			markers = append(markers, abstract.Marker{
+				Time: event.Time,
+				Name: fmt.Sprintf("Entered %v", areaChange.Area),
+				User: currentUser,
+				Area: areaChange.Area,
+			})
+		}
+
 		if markerLine, ok := event.Contents.(*abstract.MarkerLine); ok {
 			markers = append(markers, abstract.Marker{
 				Time: event.Time,
@@ -115,3 +299,58 @@ func PrereadLogsFile(path string) ([]abstract.Marker, error) {
 
 	return markers, nil
 }
+
+// SummarizeCharacters Adds up sessions of the file into a summary for every character that logged in
+func SummarizeCharacters(sessions []abstract.SessionSummary) []abstract.CharacterSummary {
+	var summaries []abstract.CharacterSummary
+
+	for _, session := range sessions {
+		summaries = utils.CreateUpdate(
+			summaries,
+			func(summary abstract.CharacterSummary) bool {
+				return summary.Name == session.Character
+			},
+			func() abstract.CharacterSummary {
+				return abstract.CharacterSummary{
+					Name:        session.Character,
+					Sessions:    1,
+					PlayTime:    session.Duration(),
+					DamageDealt: session.DamageDealt,
+					XPGained:    session.XPGained,
+					Deaths:      session.Deaths,
+				}
+			},
+			func(summary abstract.CharacterSummary) abstract.CharacterSummary {
+				summary.Sessions++
+				summary.PlayTime += session.Duration()
+				summary.DamageDealt += session.DamageDealt
+				summary.XPGained += session.XPGained
+				summary.Deaths += session.Deaths
+				return summary
+			},
+		)
+	}
+
+	return summaries
+}
+
+// SummarizeSessions Reads the file with the collectors and creates a summary for every login,
+// events before the first login belong to the file's saved identity
+func SummarizeSessions(state abstract.StatisticsBearer, path string) ([]abstract.SessionSummary, error) {
+	stats, err := state.CreateStatisticsCollector(path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.Run()
+
+	// Notify is closed once the whole file was read
+	for range stats.Notify() {
+	}
+
+	lock := stats.Mutex()
+	lock.RLock()
+	defer lock.RUnlock()
+
+	return stats.SessionSummaries(), nil
+}
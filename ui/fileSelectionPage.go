@@ -33,6 +33,12 @@ type FileSelectionPage struct {
 	exitButton       *widget.Clickable
 	settingsIcon     *widget.Icon
 	settingsButton   *widget.Clickable
+	trendsIcon       *widget.Icon
+	trendsButton     *widget.Clickable
+	recordsIcon      *widget.Icon
+	recordsButton    *widget.Clickable
+	webhooksIcon     *widget.Icon
+	webhooksButton   *widget.Clickable
 
 	modalLayer *components.ModalLayer
 	dropdown   *components.Dropdown
@@ -54,6 +60,21 @@ func NewFileSelectionPage() *FileSelectionPage {
 		log.Fatalln(err)
 	}
 
+	trendsIcon, err := widget.NewIcon(icons.ActionTrendingUp)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	recordsIcon, err := widget.NewIcon(icons.ActionGrade)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	webhooksIcon, err := widget.NewIcon(icons.ContentSend)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
 	return &FileSelectionPage{
 		dirty: true,
 
@@ -71,6 +92,15 @@ func NewFileSelectionPage() *FileSelectionPage {
 
 		settingsIcon:   settingsIcon,
 		settingsButton: &widget.Clickable{},
+
+		trendsIcon:   trendsIcon,
+		trendsButton: &widget.Clickable{},
+
+		recordsIcon:   recordsIcon,
+		recordsButton: &widget.Clickable{},
+
+		webhooksIcon:   webhooksIcon,
+		webhooksButton: &widget.Clickable{},
 	}
 }
 
@@ -133,6 +163,18 @@ func (p *FileSelectionPage) Layout(ctx layout.Context, state abstract.GlobalStat
 		state.SwitchPage(NewSettingsPage())
 	}
 
+	if p.trendsButton.Clicked(ctx) {
+		state.SwitchPage(NewTrendsPage())
+	}
+
+	if p.recordsButton.Clicked(ctx) {
+		state.SwitchPage(NewRecordsPage())
+	}
+
+	if p.webhooksButton.Clicked(ctx) {
+		state.SwitchPage(NewWebhooksPage())
+	}
+
 	layout.Flex{
 		Axis: layout.Vertical,
 	}.Layout(
@@ -209,6 +251,12 @@ func (p *FileSelectionPage) sidePanelUI(state abstract.GlobalState) layout.Widge
 					layout.Rigid(material.IconButton(state.Theme(), p.browseFileButton, p.browseFileIcon, "Browse File").Layout),
 					utils.FlexSpacerW(utils.CommonSpacing),
 					layout.Flexed(1, layout.Spacer{}.Layout),
+					layout.Rigid(material.IconButton(state.Theme(), p.webhooksButton, p.webhooksIcon, "Webhook Log").Layout),
+					utils.FlexSpacerW(utils.CommonSpacing),
+					layout.Rigid(material.IconButton(state.Theme(), p.recordsButton, p.recordsIcon, "Records").Layout),
+					utils.FlexSpacerW(utils.CommonSpacing),
+					layout.Rigid(material.IconButton(state.Theme(), p.trendsButton, p.trendsIcon, "Trends").Layout),
+					utils.FlexSpacerW(utils.CommonSpacing),
 					layout.Rigid(material.IconButton(state.Theme(), p.settingsButton, p.settingsIcon, "Settings").Layout),
 				)
 			},
@@ -223,15 +271,70 @@ func (p *FileSelectionPage) SetupWindow(state abstract.GlobalState) {
 	)
 }
 
+// fileSummary Character summaries of a file, taken from the library once its background scan read the file
+type fileSummary struct {
+	scanned   bool
+	done      bool
+	summaries []abstract.CharacterSummary
+}
+
+func (f *fileSummary) load(state abstract.GlobalState, fullPath string) {
+	if f.done {
+		return
+	}
+
+	sessions, ok := state.LibraryFileSessions(fullPath)
+	if ok {
+		f.summaries = SummarizeCharacters(sessions)
+		f.done = true
+		return
+	}
+
+	// Files that showed up after the last scan are picked up by another one
+	if !f.scanned && !state.LibraryScanning() {
+		f.scanned = true
+		state.ScanLibrary()
+	}
+}
+
+func (f *fileSummary) lines() []string {
+	if !f.done {
+		return []string{"Looking for characters..."}
+	}
+
+	return lo.Map(f.summaries, func(item abstract.CharacterSummary, _ int) string {
+		return item.String()
+	})
+}
+
 type FileButton struct {
 	file       os.FileInfo
 	openButton *widget.Clickable
+	summary    *fileSummary
 }
 
 func NewFileButton(fileInfo os.FileInfo, _ int) FileButton {
 	return FileButton{
 		file:       fileInfo,
 		openButton: &widget.Clickable{},
+		summary:    &fileSummary{},
+	}
+}
+
+func (b FileButton) summaryWidget(state abstract.GlobalState) layout.Widget {
+	return func(gtx layout.Context) layout.Dimensions {
+		b.summary.load(state, path.Join(state.GorgonFolder(), b.file.Name()))
+
+		lines := b.summary.lines()
+		children := make([]layout.FlexChild, len(lines))
+
+		for i, line := range lines {
+			children[i] = layout.Rigid(utils.WithColor(material.Caption(state.Theme(), line), utils.GrayText).Layout)
+		}
+
+		return layout.Flex{
+			Axis: layout.Vertical,
+		}.Layout(gtx, children...)
 	}
 }
 
@@ -258,6 +361,10 @@ func (b FileButton) Layout(gtx layout.Context, state abstract.GlobalState) layou
 								fmt.Sprintf("Modified at %v", b.file.ModTime().Format(time.DateTime)),
 							), utils.GrayText).Layout,
 						},
+						components.CanvasItem{
+							Offset: image.Point{X: gtx.Dp(0), Y: gtx.Dp(40)},
+							Widget: b.summaryWidget(state),
+						},
 						components.CanvasItem{
 							Anchor: layout.E,
 							Widget: material.Button(state.Theme(), b.openButton, "Open").Layout,
@@ -31,6 +31,40 @@ func mapMarkerToSelectable(item abstract.Marker, _ int) selectableMarker {
 	}
 }
 
+type areaChoice string
+
+func (a areaChoice) String() string {
+	if a == "" {
+		return "All areas"
+	}
+	return string(a)
+}
+
+func areaOptions(markers []abstract.Marker) []fmt.Stringer {
+	options := []fmt.Stringer{areaChoice("")}
+
+	for _, marker := range markers {
+		if marker.Area == "" {
+			continue
+		}
+
+		options = utils.CreateUpdate(
+			options,
+			func(item fmt.Stringer) bool {
+				return string(item.(areaChoice)) == marker.Area
+			},
+			func() fmt.Stringer {
+				return areaChoice(marker.Area)
+			},
+			func(stringer fmt.Stringer) fmt.Stringer {
+				return stringer
+			},
+		)
+	}
+
+	return options
+}
+
 func NewMarkersPage(filePath string, markers []abstract.Marker) *MarkersPage {
 	backIcon, err := widget.NewIcon(icons.NavigationArrowBack)
 	if err != nil {
@@ -42,9 +76,16 @@ func NewMarkersPage(filePath string, markers []abstract.Marker) *MarkersPage {
 		log.Fatalln(err)
 	}
 
+	areaDropdown, err := components.NewDropdown("Area", areaChoice(""))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	areaDropdown.SetOptions(areaOptions(markers))
+
 	return &MarkersPage{
-		filePath: filePath,
-		markers:  lo.Map(markers, mapMarkerToSelectable),
+		filePath:   filePath,
+		modalLayer: components.NewModalLayer(),
+		markers:    lo.Map(markers, mapMarkerToSelectable),
 		markerList: &widget.List{
 			List: layout.List{
 				Axis: layout.Vertical,
@@ -57,10 +98,13 @@ func NewMarkersPage(filePath string, markers []abstract.Marker) *MarkersPage {
 		timeFromEditor:      &widget.Editor{},
 		timeToEditor:        &widget.Editor{},
 		usernameEditor:      &widget.Editor{},
+		areaDropdown:        areaDropdown,
 		backIcon:            backIcon,
 		backButton:          &widget.Clickable{},
 		openButton:          &widget.Clickable{},
 		exportButton:        &widget.Clickable{},
+		baselineButton:      &widget.Clickable{},
+		compareButton:       &widget.Clickable{},
 		watchFileCheckbox: &widget.Bool{
 			Value: parser.IsFileMostRecent(filePath),
 		},
@@ -69,6 +113,7 @@ func NewMarkersPage(filePath string, markers []abstract.Marker) *MarkersPage {
 
 type MarkersPage struct {
 	filePath            string
+	modalLayer          *components.ModalLayer
 	markers             []selectableMarker
 	markerList          *widget.List
 	markerButtons       []*widget.Clickable
@@ -82,10 +127,15 @@ type MarkersPage struct {
 	timeToEditor        *widget.Editor
 	timeToInvalid       bool
 	usernameEditor      *widget.Editor
+	identityLoaded      bool
+	areaDropdown        *components.Dropdown
+	currentArea         string
 	backIcon            *widget.Icon
 	backButton          *widget.Clickable
 	openButton          *widget.Clickable
 	exportButton        *widget.Clickable
+	baselineButton      *widget.Clickable
+	compareButton       *widget.Clickable
 	watchFileCheckbox   *widget.Bool
 }
 
@@ -291,6 +341,16 @@ func figureOutTimeFrames(markers []selectableMarker) []abstract.MarkerTimeFrame
 }
 
 func (m *MarkersPage) getTimeFrames() []abstract.MarkerTimeFrame {
+	timeFrames := m.getUnfilteredTimeFrames()
+
+	for i := range timeFrames {
+		timeFrames[i].Area = m.currentArea
+	}
+
+	return timeFrames
+}
+
+func (m *MarkersPage) getUnfilteredTimeFrames() []abstract.MarkerTimeFrame {
 	switch m.overrideChoice.Value {
 	case "selection":
 		return figureOutTimeFrames(m.markers)
@@ -447,6 +507,10 @@ func (m *MarkersPage) sidePanelUI(state abstract.GlobalState) layout.Widget {
 		processDateEditor(gtx, m.timeFromEditor, &m.timeFromInvalid, &m.timeFrom)
 		processDateEditor(gtx, m.timeToEditor, &m.timeToInvalid, &m.timeTo)
 
+		if m.areaDropdown.Changed() {
+			m.currentArea = string(m.areaDropdown.Value.(areaChoice))
+		}
+
 		if m.exportButton.Clicked(gtx) {
 			err := m.exportWithMarkers()
 			if err != nil {
@@ -454,9 +518,46 @@ func (m *MarkersPage) sidePanelUI(state abstract.GlobalState) layout.Widget {
 			}
 		}
 
+		if m.baselineButton.Clicked(gtx) {
+			state.Storage()[comparisonBaselineKey] = comparisonSide{
+				path:       m.filePath,
+				timeFrames: m.getTimeFrames(),
+			}
+		}
+
+		baseline, hasBaseline := state.Storage()[comparisonBaselineKey].(comparisonSide)
+
+		if m.compareButton.Clicked(gtx) && hasBaseline {
+			page, err := NewComparisonPage(state, baseline, comparisonSide{
+				path:       m.filePath,
+				timeFrames: m.getTimeFrames(),
+			})
+
+			if err != nil {
+				log.Printf("Failed to open comparison page: %v\n", err)
+			} else {
+				state.SwitchPage(page)
+			}
+		}
+
+		identity, _ := state.FindIdentity(m.filePath)
+
+		if !m.identityLoaded {
+			m.identityLoaded = true
+			m.usernameEditor.SetText(identity.Name)
+		}
+
 		if m.openButton.Clicked(gtx) {
+			if name := m.usernameEditor.Text(); m.overrideChoice.Value == "custom" && name != "" && name != identity.Name {
+				state.SaveIdentity(abstract.FileIdentity{
+					Path:   m.filePath,
+					Name:   name,
+					Source: abstract.IdentityManual,
+				})
+			}
+
 			if state.OpenFile(m.filePath, m.watchFileCheckbox.Value, m.getTimeFrames()) {
-				page, err := NewStatisticsPage(state, m.filePath)
+				page, err := NewStatisticsPage(state, m.filePath, m.watchFileCheckbox.Value, m.getTimeFrames())
 
 				if err != nil {
 					log.Printf("Failed to open statistics page: %v\n", err)
@@ -479,6 +580,8 @@ func (m *MarkersPage) sidePanelUI(state abstract.GlobalState) layout.Widget {
 							Axis: layout.Vertical,
 						}.Layout(
 							cgtx,
+							layout.Rigid(material.Body2(state.Theme(), fmt.Sprintf("Character: %v", identity)).Layout),
+							utils.FlexSpacerH(utils.CommonSpacing*2),
 							layout.Rigid(material.CheckBox(
 								state.Theme(),
 								m.watchFileCheckbox,
@@ -502,7 +605,53 @@ func (m *MarkersPage) sidePanelUI(state abstract.GlobalState) layout.Widget {
 							layout.Rigid(material.Body2(state.Theme(), "Read until:").Layout),
 							utils.FlexSpacerH(utils.CommonSpacing),
 							layout.Rigid(textEditor(state, m.timeToEditor, afterDateHint, m.timeToInvalid)),
+							utils.FlexSpacerH(utils.CommonSpacing*2),
+							layout.Rigid(material.Body2(state.Theme(), "Only load data from area:").Layout),
+							utils.FlexSpacerH(utils.CommonSpacing),
+							layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+								style := components.StyleDropdown(state.Theme(), m.modalLayer, m.areaDropdown)
+								style.NoLabel = true
+								style.TextSize = 14
+								style.DialogTextSize = 14
+								style.MaxWidth = 300
+
+								return style.Layout(gtx)
+							}),
 							layout.Flexed(1, layout.Spacer{}.Layout),
+							layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+								if !hasBaseline {
+									return layout.Dimensions{}
+								}
+
+								return layout.Inset{Bottom: utils.CommonSpacing}.Layout(
+									gtx,
+									material.Body2(state.Theme(), fmt.Sprintf("Saved for comparison: %v", baseline)).Layout,
+								)
+							}),
+							layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+								button := func(clickable *widget.Clickable, text string) layout.Widget {
+									style := material.Button(state.Theme(), clickable, text)
+									style.Inset = layout.UniformInset(5)
+									style.TextSize = 14
+									return style.Layout
+								}
+
+								return layout.Flex{
+									Axis: layout.Horizontal,
+								}.Layout(
+									gtx,
+									layout.Flexed(1, button(m.baselineButton, "Save for Comparison")),
+									utils.FlexSpacerW(utils.CommonSpacing),
+									layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+										if !hasBaseline {
+											return layout.Dimensions{}
+										}
+
+										return button(m.compareButton, "Compare to Saved")(gtx)
+									}),
+								)
+							}),
+							utils.FlexSpacerH(utils.CommonSpacing),
 							layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 								style := material.Button(state.Theme(), m.exportButton, "Export File with Markers")
 								const horizontalInset = 78
@@ -537,13 +686,15 @@ func (m *MarkersPage) sidePanelUI(state abstract.GlobalState) layout.Widget {
 }
 
 func (m *MarkersPage) Layout(ctx layout.Context, state abstract.GlobalState) error {
-	layout.Flex{
-		Axis: layout.Horizontal,
-	}.Layout(
-		ctx,
-		layout.Flexed(1, m.markerListUI(state)),
-		layout.Rigid(m.sidePanelUI(state)),
-	)
+	m.modalLayer.Overlay(func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{
+			Axis: layout.Horizontal,
+		}.Layout(
+			gtx,
+			layout.Flexed(1, m.markerListUI(state)),
+			layout.Rigid(m.sidePanelUI(state)),
+		)
+	})(ctx)
 
 	return nil
 }
@@ -0,0 +1,207 @@
+package ui
+
+import (
+	"PGCombatTracker/abstract"
+	"PGCombatTracker/ui/components"
+	"PGCombatTracker/utils"
+	"fmt"
+	"gioui.org/app"
+	"gioui.org/font"
+	"gioui.org/layout"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"golang.org/x/exp/shiny/materialdesign/icons"
+	"log"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+func NewRecordsPage() *RecordsPage {
+	backIcon, err := widget.NewIcon(icons.NavigationArrowBack)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	characterDropdown, err := components.NewDropdown("Character", characterChoice(""))
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	return &RecordsPage{
+		modalLayer:        components.NewModalLayer(),
+		backIcon:          backIcon,
+		backButton:        &widget.Clickable{},
+		characterDropdown: characterDropdown,
+		recordList: &widget.List{
+			List: layout.List{
+				Axis: layout.Vertical,
+			},
+		},
+	}
+}
+
+// RecordsPage Personal bests of a character across every file that was opened
+type RecordsPage struct {
+	character  string
+	characters []string
+
+	modalLayer        *components.ModalLayer
+	backIcon          *widget.Icon
+	backButton        *widget.Clickable
+	characterDropdown *components.Dropdown
+	recordList        *widget.List
+}
+
+// updateCharacters Records are found in background, so new characters can show up while the page is open
+func (r *RecordsPage) updateCharacters(state abstract.GlobalState) {
+	characters := state.RecordCharacters()
+	if slices.Equal(characters, r.characters) {
+		return
+	}
+
+	r.characters = characters
+
+	options := make([]fmt.Stringer, len(characters))
+	for i, character := range characters {
+		options[i] = characterChoice(character)
+	}
+	r.characterDropdown.SetOptions(options)
+
+	if len(characters) > 0 && !slices.Contains(characters, r.character) {
+		r.character = characters[0]
+	}
+	r.characterDropdown.SetValue(characterChoice(r.character))
+}
+
+func (r *RecordsPage) navBar(state abstract.LayeredState) layout.Widget {
+	return func(gtx layout.Context) layout.Dimensions {
+		if r.backButton.Clicked(gtx) {
+			state.SwitchPage(NewFileSelectionPage())
+		}
+
+		if r.characterDropdown.Changed() {
+			r.character = string(r.characterDropdown.Value.(characterChoice))
+		}
+
+		return layout.Background{}.Layout(
+			gtx,
+			utils.MakeColoredBG(utils.SecondBG),
+			func(gtx layout.Context) layout.Dimensions {
+				return layout.UniformInset(utils.CommonSpacing).Layout(
+					gtx,
+					func(gtx layout.Context) layout.Dimensions {
+						return layout.Flex{
+							Axis:      layout.Horizontal,
+							Alignment: layout.Middle,
+						}.Layout(
+							gtx,
+							layout.Rigid(material.IconButton(state.Theme(), r.backButton, r.backIcon, "Back").Layout),
+							utils.FlexSpacerW(utils.CommonSpacing*2),
+							layout.Rigid(material.H4(state.Theme(), "Records").Layout),
+							utils.FlexSpacerW(utils.CommonSpacing*2),
+							layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+								if len(r.characters) == 0 {
+									return layout.Dimensions{}
+								}
+
+								return components.StyleDropdown(state.Theme(), state.ModalLayer(), r.characterDropdown).Layout(gtx)
+							}),
+						)
+					},
+				)
+			},
+		)
+	}
+}
+
+func recordRow(state abstract.LayeredState, record abstract.PersonalRecord) layout.Widget {
+	name := record.Name
+	if name == "" {
+		name = record.Kind.String()
+	}
+
+	return func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{
+			Axis:      layout.Horizontal,
+			Alignment: layout.Baseline,
+		}.Layout(
+			gtx,
+			layout.Flexed(1, material.Body1(state.Theme(), name).Layout),
+			utils.FlexSpacerW(utils.CommonSpacing*2),
+			layout.Rigid(material.Body1(state.Theme(), record.FormattedValue()).Layout),
+			utils.FlexSpacerW(utils.CommonSpacing*2),
+			layout.Rigid(utils.WithColor(material.Body2(state.Theme(), fmt.Sprintf(
+				"%v in %v",
+				record.At.Format(time.DateTime),
+				filepath.Base(record.Path),
+			)), utils.GrayText).Layout),
+		)
+	}
+}
+
+func (r *RecordsPage) body(state abstract.LayeredState) layout.Widget {
+	return func(gtx layout.Context) layout.Dimensions {
+		records := state.Records(r.character)
+
+		if len(records) == 0 {
+			return layout.UniformInset(utils.CommonSpacing*2).Layout(
+				gtx,
+				material.Body1(state.Theme(), "No records yet, they are found in files as they get opened").Layout,
+			)
+		}
+
+		var widgets []layout.Widget
+		for i, record := range records {
+			if i == 0 || records[i-1].Kind != record.Kind {
+				heading := material.Body1(state.Theme(), record.Kind.String())
+				heading.TextSize = 14
+				heading.Font.Weight = font.Bold
+
+				widgets = append(widgets, func(gtx layout.Context) layout.Dimensions {
+					return layout.Inset{Top: utils.CommonSpacing}.Layout(gtx, heading.Layout)
+				})
+			}
+
+			widgets = append(widgets, recordRow(state, record))
+		}
+
+		return layout.UniformInset(utils.CommonSpacing).Layout(
+			gtx,
+			func(gtx layout.Context) layout.Dimensions {
+				return material.List(state.Theme(), r.recordList).Layout(
+					gtx,
+					len(widgets),
+					func(gtx layout.Context, index int) layout.Dimensions {
+						return layout.UniformInset(utils.CommonSpacing/2).Layout(gtx, widgets[index])
+					},
+				)
+			},
+		)
+	}
+}
+
+func (r *RecordsPage) Layout(ctx layout.Context, state abstract.GlobalState) error {
+	layeredState := NewLayeredState(state, r.modalLayer)
+
+	r.updateCharacters(state)
+
+	r.modalLayer.Overlay(func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{
+			Axis: layout.Vertical,
+		}.Layout(
+			gtx,
+			layout.Rigid(r.navBar(layeredState)),
+			layout.Flexed(1, r.body(layeredState)),
+		)
+	})(ctx)
+
+	return nil
+}
+
+func (r *RecordsPage) SetupWindow(state abstract.GlobalState) {
+	state.Window().Option(
+		app.MinSize(800, 600),
+		app.Decorated(true),
+	)
+}
@@ -5,23 +5,54 @@ import (
 	"PGCombatTracker/parser"
 	"PGCombatTracker/ui/components"
 	"PGCombatTracker/utils"
+	"PGCombatTracker/webhooks"
+	"bytes"
+	"fmt"
 	"gioui.org/app"
 	"gioui.org/widget/material"
 	"github.com/samber/lo"
+	"image"
 	"image/color"
+	"image/png"
 	"log"
 	"os"
 	"path"
+	"path/filepath"
 	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type GlobalState struct {
 	settings            *abstract.Settings
+	savedSettings       abstract.Settings
+	settingsLock        *sync.Mutex
 	markers             *abstract.Markers
+	identities          *abstract.Identities
+	identitiesLock      *sync.Mutex
+	learnedPets         *abstract.LearnedPets
+	petsLock            *sync.Mutex
+	enemyHealth         *abstract.EnemyHealthDatabase
+	enemyHealthLock     *sync.Mutex
+	xpTable             *abstract.XPTable
+	xpTablePath         string
+	xpTableLock         *sync.Mutex
+	categories          *abstract.SkillCategories
+	library             *abstract.SessionLibrary
+	libraryLock         *sync.Mutex
+	libraryScanning     *atomic.Bool
+	records             *abstract.RecordBook
+	recordsLock         *sync.Mutex
+	toaster             *components.Toaster
+	webhooks            *webhooks.Sender
+	reportsLock         *sync.Mutex
+	queuedReports       []queuedReport
 	gorgonFolder        string
 	statisticsFactory   abstract.StatisticsFactory
 	statisticsCollector abstract.StatisticsCollector
+	statisticsLock      *sync.Mutex
 	page                abstract.Page
 	storage             map[string]any
 	window              *app.Window
@@ -69,14 +100,68 @@ func NewGlobalState(window *app.Window, factory abstract.StatisticsFactory) (abs
 		log.Printf("Failed to load %v, starting fresh. Reason: %v\n", MarkersLocation, err)
 	}
 
+	identities := abstract.NewIdentities()
+	if err := LoadIdentitiesFile(identities); err != nil {
+		log.Printf("Failed to load %v, starting fresh. Reason: %v\n", IdentitiesLocation, err)
+	}
+
+	learnedPets := abstract.NewLearnedPets()
+	if err := LoadPetsFile(learnedPets); err != nil {
+		log.Printf("Failed to load %v, starting fresh. Reason: %v\n", PetsLocation, err)
+	}
+
+	enemyHealth := abstract.NewEnemyHealthDatabase()
+	if err := LoadEnemyHealthFile(enemyHealth); err != nil {
+		log.Printf("Failed to load %v, starting fresh. Reason: %v\n", EnemyHealthLocation, err)
+	}
+
+	categories := abstract.DefaultSkillCategories()
+	if err := LoadCategoriesFile(categories); err != nil {
+		log.Printf("Failed to load %v, writing defaults so they can be edited. Reason: %v\n", CategoriesLocation, err)
+
+		if err := SaveCategoriesFile(categories); err != nil {
+			log.Printf("Failed to save %v: %v\n", CategoriesLocation, err)
+		}
+	}
+
+	library := abstract.NewSessionLibrary()
+	if err := LoadLibraryFile(library); err != nil {
+		log.Printf("Failed to load %v, starting fresh. Reason: %v\n", LibraryLocation, err)
+	}
+
+	records := abstract.NewRecordBook()
+	if err := LoadRecordsFile(records); err != nil {
+		log.Printf("Failed to load %v, starting fresh. Reason: %v\n", RecordsLocation, err)
+	}
+
 	fonts, err := abstract.LoadFontPack()
 	if err != nil {
 		log.Fatalln(err)
 	}
 
-	return &GlobalState{
+	state := &GlobalState{
 		settings:          sett,
+		savedSettings:     *sett,
+		settingsLock:      new(sync.Mutex),
 		markers:           markers,
+		identities:        identities,
+		identitiesLock:    new(sync.Mutex),
+		learnedPets:       learnedPets,
+		petsLock:          new(sync.Mutex),
+		enemyHealth:       enemyHealth,
+		enemyHealthLock:   new(sync.Mutex),
+		xpTable:           loadXPTable(sett.XPTableFile),
+		xpTablePath:       sett.XPTableFile,
+		xpTableLock:       new(sync.Mutex),
+		statisticsLock:    new(sync.Mutex),
+		categories:        categories,
+		library:           library,
+		libraryLock:       new(sync.Mutex),
+		libraryScanning:   &atomic.Bool{},
+		records:           records,
+		recordsLock:       new(sync.Mutex),
+		reportsLock:       new(sync.Mutex),
+		toaster:           components.NewToaster(),
 		gorgonFolder:      gorgonFolder,
 		statisticsFactory: factory,
 		storage:           make(map[string]any),
@@ -84,7 +169,13 @@ func NewGlobalState(window *app.Window, factory abstract.StatisticsFactory) (abs
 		theme:             theme,
 		fonts:             fonts,
 		draggable:         true,
-	}, nil
+	}
+
+	state.webhooks = webhooks.NewSender(state.announceDelivery)
+
+	state.ScanLibrary()
+
+	return state, nil
 }
 
 func (g *GlobalState) FindMarkers(fullPath string) ([]abstract.Marker, error) {
@@ -163,15 +254,432 @@ func (g *GlobalState) SaveMarker(path, name, user string) {
 	}
 }
 
+func (g *GlobalState) FindIdentity(path string) (abstract.FileIdentity, bool) {
+	g.identitiesLock.Lock()
+	defer g.identitiesLock.Unlock()
+
+	for _, identity := range g.identities.Files {
+		if identity.Path == path {
+			return identity, true
+		}
+	}
+
+	return abstract.FileIdentity{}, false
+}
+
+func (g *GlobalState) SaveIdentity(identity abstract.FileIdentity) {
+	g.identitiesLock.Lock()
+	defer g.identitiesLock.Unlock()
+
+	g.identities.Files = utils.CreateUpdate(
+		g.identities.Files,
+		func(file abstract.FileIdentity) bool {
+			return file.Path == identity.Path
+		},
+		func() abstract.FileIdentity {
+			return identity
+		},
+		func(file abstract.FileIdentity) abstract.FileIdentity {
+			return identity
+		},
+	)
+
+	err := SaveIdentitiesFile(g.identities)
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+func (g *GlobalState) KnownPets(character string) []string {
+	g.petsLock.Lock()
+	defer g.petsLock.Unlock()
+
+	return slices.Clone(g.learnedPets.PetsOf(character))
+}
+
+func (g *GlobalState) LearnPet(character, pet string) {
+	g.petsLock.Lock()
+	defer g.petsLock.Unlock()
+
+	g.learnedPets.Characters = utils.CreateUpdate(
+		g.learnedPets.Characters,
+		func(characterPets abstract.CharacterPets) bool {
+			return characterPets.Character == character
+		},
+		func() abstract.CharacterPets {
+			return abstract.CharacterPets{
+				Character: character,
+				Pets:      []string{pet},
+			}
+		},
+		func(characterPets abstract.CharacterPets) abstract.CharacterPets {
+			if !slices.Contains(characterPets.Pets, pet) {
+				characterPets.Pets = append(characterPets.Pets, pet)
+			}
+			return characterPets
+		},
+	)
+
+	err := SavePetsFile(g.learnedPets)
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+func (g *GlobalState) EnemyHealth(enemyType string) (abstract.EnemyHealthEstimate, bool) {
+	g.enemyHealthLock.Lock()
+	defer g.enemyHealthLock.Unlock()
+
+	return g.enemyHealth.Find(enemyType)
+}
+
+func (g *GlobalState) LearnEnemyHealth(path string, kills []abstract.EnemyKill) {
+	g.enemyHealthLock.Lock()
+	defer g.enemyHealthLock.Unlock()
+
+	changed := false
+	for _, kill := range kills {
+		if g.enemyHealth.Learn(path, kill) {
+			changed = true
+		}
+	}
+
+	if !changed {
+		return
+	}
+
+	err := SaveEnemyHealthFile(g.enemyHealth)
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+func loadXPTable(path string) *abstract.XPTable {
+	if path == "" {
+		return nil
+	}
+
+	table, err := LoadXPTableFile(path)
+	if err != nil {
+		log.Printf("Failed to load XP table %v: %v\n", path, err)
+		return nil
+	}
+
+	return table
+}
+
+func (g *GlobalState) XPTable() *abstract.XPTable {
+	g.xpTableLock.Lock()
+	defer g.xpTableLock.Unlock()
+
+	return g.xpTable
+}
+
+// reloadXPTable Only reads the file again if the path has changed, settings are saved on every keystroke
+func (g *GlobalState) reloadXPTable() {
+	g.xpTableLock.Lock()
+	defer g.xpTableLock.Unlock()
+
+	if g.xpTablePath == g.settings.XPTableFile {
+		return
+	}
+
+	g.xpTablePath = g.settings.XPTableFile
+	g.xpTable = loadXPTable(g.xpTablePath)
+}
+
+func (g *GlobalState) ScanLibrary() {
+	if !g.libraryScanning.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		defer g.libraryScanning.Store(false)
+
+		files, err := parser.GetSortedLogFiles(g.gorgonFolder)
+		if err != nil {
+			log.Printf("Failed to scan logs for the library: %v\n", err)
+			return
+		}
+
+		for _, file := range files {
+			fullPath := path.Join(g.gorgonFolder, file.Name())
+
+			g.libraryLock.Lock()
+			current := g.library.IsCurrent(fullPath, file.Size(), file.ModTime())
+			g.libraryLock.Unlock()
+
+			if current {
+				continue
+			}
+
+			sessions, err := SummarizeSessions(summaryState{g}, fullPath)
+			if err != nil {
+				log.Printf("Failed to summarize '%v' for the library: %v\n", fullPath, err)
+				continue
+			}
+
+			g.libraryLock.Lock()
+			g.library.Store(abstract.LibraryFile{
+				Path:     fullPath,
+				Size:     file.Size(),
+				ModTime:  file.ModTime(),
+				Sessions: sessions,
+			})
+			err = SaveLibraryFile(g.library)
+			g.libraryLock.Unlock()
+
+			if err != nil {
+				log.Printf("Failed to save %v: %v\n", LibraryLocation, err)
+			}
+
+			g.window.Invalidate()
+		}
+	}()
+}
+
+// summaryState Files are summarized in background, whatever their collectors learn along the way isn't kept
+type summaryState struct {
+	*GlobalState
+}
+
+func (s summaryState) CreateStatisticsCollector(path string, timeFrames []abstract.MarkerTimeFrame) (abstract.StatisticsCollector, error) {
+	return s.statisticsFactory(s, path, false, timeFrames)
+}
+
+func (s summaryState) LearnPet(string, string) {}
+
+func (s summaryState) LearnEnemyHealth(string, []abstract.EnemyKill) {}
+
+func (s summaryState) OfferRecords([]abstract.PersonalRecord, bool) {}
+
+func (s summaryState) SendReport(abstract.ReportTrigger, string, string, []abstract.Collector) {}
+
+func (s summaryState) ShowAlert(string) {}
+
+func (g *GlobalState) LibraryScanning() bool {
+	return g.libraryScanning.Load()
+}
+
+func (g *GlobalState) LibrarySessions(character string) []abstract.SessionSummary {
+	g.libraryLock.Lock()
+	defer g.libraryLock.Unlock()
+
+	return g.library.Sessions(character)
+}
+
+func (g *GlobalState) LibraryFileSessions(path string) ([]abstract.SessionSummary, bool) {
+	g.libraryLock.Lock()
+	defer g.libraryLock.Unlock()
+
+	return g.library.FileSessions(path)
+}
+
+func (g *GlobalState) LibraryCharacters() []string {
+	g.libraryLock.Lock()
+	defer g.libraryLock.Unlock()
+
+	return g.library.Characters()
+}
+
+func (g *GlobalState) OfferRecords(records []abstract.PersonalRecord, announce bool) {
+	g.recordsLock.Lock()
+	defer g.recordsLock.Unlock()
+
+	changed := false
+	for _, record := range records {
+		previous, stored, broken := g.records.Offer(record)
+		if stored {
+			changed = true
+		}
+
+		if broken && announce {
+			g.ShowToast(abstract.BrokenRecord{Record: record, Previous: previous}.String())
+		}
+	}
+
+	if !changed {
+		return
+	}
+
+	err := SaveRecordsFile(g.records)
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+func (g *GlobalState) Records(character string) []abstract.PersonalRecord {
+	g.recordsLock.Lock()
+	defer g.recordsLock.Unlock()
+
+	return g.records.Records(character)
+}
+
+func (g *GlobalState) RecordCharacters() []string {
+	g.recordsLock.Lock()
+	defer g.recordsLock.Unlock()
+
+	return g.records.Characters()
+}
+
+func (g *GlobalState) ShowToast(text string) {
+	g.toaster.Show(text)
+	g.window.Invalidate()
+}
+
+func (g *GlobalState) ShowAlert(text string) {
+	g.toaster.Alert(text)
+	g.window.Invalidate()
+}
+
+func (g *GlobalState) Toaster() *components.Toaster {
+	return g.toaster
+}
+
+// announceDelivery Failures are always shown, successes only for reports that were asked for
+func (g *GlobalState) announceDelivery(delivery abstract.WebhookDelivery) {
+	if !delivery.Succeeded() {
+		g.ShowToast(fmt.Sprintf("%v failed to send to %v: %v", delivery.Trigger, delivery.Webhook, delivery.Error))
+	} else if delivery.Trigger == abstract.ReportOnDemand {
+		g.ShowToast(fmt.Sprintf("Report sent to %v", delivery.Webhook))
+	}
+}
+
+// queuedReport Report that waits for the UI goroutine to export its collectors
+type queuedReport struct {
+	trigger    abstract.ReportTrigger
+	character  string
+	path       string
+	collectors []abstract.Collector
+}
+
+func (g *GlobalState) SendReport(trigger abstract.ReportTrigger, character, path string, collectors []abstract.Collector) {
+	g.reportsLock.Lock()
+	g.queuedReports = append(g.queuedReports, queuedReport{
+		trigger:    trigger,
+		character:  character,
+		path:       path,
+		collectors: collectors,
+	})
+	g.reportsLock.Unlock()
+
+	g.window.Invalidate()
+}
+
+func (g *GlobalState) SendQueuedReports() {
+	g.reportsLock.Lock()
+	queued := g.queuedReports
+	g.queuedReports = nil
+	g.reportsLock.Unlock()
+
+	if len(queued) == 0 {
+		return
+	}
+
+	if stats := g.StatisticsCollector(); stats != nil {
+		stats.Mutex().RLock()
+		defer stats.Mutex().RUnlock()
+	}
+
+	for _, report := range queued {
+		g.exportReport(report)
+	}
+}
+
+// exportReport Renders the tabs while collectors can't change, encoding and posting happens in background
+func (g *GlobalState) exportReport(queued queuedReport) {
+	hooks := webhooks.Parse(g.settings.Webhooks)
+	if len(hooks) == 0 {
+		if queued.trigger == abstract.ReportOnDemand {
+			g.ShowToast("No webhooks yet, add them in settings")
+		}
+		return
+	}
+
+	report := webhooks.Report{
+		Trigger:   queued.trigger,
+		Character: queued.character,
+		File:      filepath.Base(queued.path),
+		At:        time.Now(),
+	}
+	if report.Character == "" {
+		report.Character = "all characters"
+	}
+
+	// Exports don't use the modal layer, it's only there to satisfy the interface
+	layeredState := NewLayeredState(g, components.NewModalLayer())
+
+	var summary []string
+	var names []string
+	var images []image.Image
+	for _, collector := range queued.collectors {
+		if source, ok := collector.(abstract.SummaryCollector); ok {
+			if line := source.Summary(queued.character); line != "" {
+				summary = append(summary, abstract.TruncateChatLine(line))
+			}
+		}
+
+		if slices.Contains(g.settings.WebhookTabs, collector.TabName()) {
+			names = append(names, collector.TabName())
+			images = append(images, collector.Export(layeredState))
+		}
+	}
+	report.Summary = strings.Join(summary, "\n")
+
+	go func() {
+		for i, img := range images {
+			buf := &bytes.Buffer{}
+			err := png.Encode(buf, img)
+			if err != nil {
+				log.Printf("Failed to export %v for webhooks: %v\n", names[i], err)
+				continue
+			}
+
+			report.Images = append(report.Images, webhooks.Image{
+				Name: names[i],
+				PNG:  buf.Bytes(),
+			})
+		}
+
+		g.webhooks.Send(hooks, report)
+	}()
+}
+
+func (g *GlobalState) WebhookDeliveries() []abstract.WebhookDelivery {
+	return g.webhooks.Deliveries()
+}
+
+func (g *GlobalState) SkillCategories() *abstract.SkillCategories {
+	return g.categories
+}
+
 func (g *GlobalState) Settings() *abstract.Settings {
 	return g.settings
 }
 
+func (g *GlobalState) SavedSettings() abstract.Settings {
+	g.settingsLock.Lock()
+	defer g.settingsLock.Unlock()
+
+	return g.savedSettings
+}
+
+// keepSavedSettings Settings are changed in place on the UI goroutine, others get the copy taken here
+func (g *GlobalState) keepSavedSettings() {
+	g.settingsLock.Lock()
+	defer g.settingsLock.Unlock()
+
+	g.savedSettings = *g.settings
+}
+
 func (g *GlobalState) ReloadSettings() {
 	err := LoadSettings(g.settings)
 	if err != nil {
 		log.Printf("Failed to load %v: %v\n", SettingsLocation, err)
 	}
+
+	g.keepSavedSettings()
+	g.reloadXPTable()
 }
 
 func (g *GlobalState) SaveSettings() {
@@ -179,6 +687,9 @@ func (g *GlobalState) SaveSettings() {
 	if err != nil {
 		log.Printf("Failed to save %v: %v\n", SettingsLocation, err)
 	}
+
+	g.keepSavedSettings()
+	g.reloadXPTable()
 }
 
 func (g *GlobalState) CanBeDragged() bool {
@@ -190,9 +701,9 @@ func (g *GlobalState) SetWindowDrag(value bool) {
 }
 
 func (g *GlobalState) OpenFile(path string, watch bool, timeFrames []abstract.MarkerTimeFrame) bool {
-	if g.statisticsCollector != nil && g.statisticsCollector.IsAlive() {
-		g.statisticsCollector.Close()
-		g.statisticsCollector = nil
+	if current := g.StatisticsCollector(); current != nil && current.IsAlive() {
+		current.Close()
+		g.SetStatisticsCollector(nil)
 	}
 
 	stats, err := g.statisticsFactory(g, path, watch, timeFrames)
@@ -203,20 +714,30 @@ func (g *GlobalState) OpenFile(path string, watch bool, timeFrames []abstract.Ma
 	}
 
 	stats.Run()
-	g.statisticsCollector = stats
+	g.SetStatisticsCollector(stats)
 
 	return true
 }
 
+func (g *GlobalState) CreateStatisticsCollector(path string, timeFrames []abstract.MarkerTimeFrame) (abstract.StatisticsCollector, error) {
+	return g.statisticsFactory(g, path, false, timeFrames)
+}
+
 func (g *GlobalState) GorgonFolder() string {
 	return g.gorgonFolder
 }
 
 func (g *GlobalState) StatisticsCollector() abstract.StatisticsCollector {
+	g.statisticsLock.Lock()
+	defer g.statisticsLock.Unlock()
+
 	return g.statisticsCollector
 }
 
 func (g *GlobalState) SetStatisticsCollector(collector abstract.StatisticsCollector) {
+	g.statisticsLock.Lock()
+	defer g.statisticsLock.Unlock()
+
 	g.statisticsCollector = collector
 }
 
@@ -19,11 +19,15 @@ import (
 	"image"
 	"image/png"
 	"log"
+	"strings"
 )
 
 type StatisticsPage struct {
 	// Actual properties
 	currentCollector int
+	watch            bool
+	timeFrames       []abstract.MarkerTimeFrame
+	savedIdentity    abstract.FileIdentity
 
 	// UI garbage
 	filePath          string
@@ -39,7 +43,16 @@ type StatisticsPage struct {
 	unlockIcon        *widget.Icon
 	copyIcon          *widget.Icon
 	copyButton        *widget.Clickable
+	sendIcon          *widget.Icon
+	sendButton        *widget.Clickable
+	textIcon          *widget.Icon
+	textButton        *widget.Clickable
+	textDropdown      *components.Dropdown
+	identityIcon      *widget.Icon
+	identityButton    *widget.Clickable
 	collectorDropdown *components.Dropdown
+	characterDropdown *components.Dropdown
+	knownCharacters   int
 	collectorBody     *widget.List
 	windowedButton    *widget.Clickable
 	windowedIcon      *widget.Icon
@@ -54,6 +67,15 @@ func (c CollectorPageIndex) String() string {
 	return c.name
 }
 
+type characterChoice string
+
+func (c characterChoice) String() string {
+	if c == "" {
+		return "All characters"
+	}
+	return string(c)
+}
+
 func getFreshCollectorBody() *widget.List {
 	return &widget.List{
 		List: layout.List{
@@ -62,7 +84,7 @@ func getFreshCollectorBody() *widget.List {
 	}
 }
 
-func NewStatisticsPage(state abstract.GlobalState, filePath string) (*StatisticsPage, error) {
+func NewStatisticsPage(state abstract.GlobalState, filePath string, watch bool, timeFrames []abstract.MarkerTimeFrame) (*StatisticsPage, error) {
 	backIcon, err := widget.NewIcon(icons.NavigationArrowBack)
 
 	if err != nil {
@@ -105,6 +127,24 @@ func NewStatisticsPage(state abstract.GlobalState, filePath string) (*Statistics
 		return nil, err
 	}
 
+	sendIcon, err := widget.NewIcon(icons.ContentSend)
+
+	if err != nil {
+		return nil, err
+	}
+
+	textIcon, err := widget.NewIcon(icons.EditorShortText)
+
+	if err != nil {
+		return nil, err
+	}
+
+	identityIcon, err := widget.NewIcon(icons.ActionAccountCircle)
+
+	if err != nil {
+		return nil, err
+	}
+
 	collectorDropdown, err := components.NewDropdown("Page", CollectorPageIndex{})
 
 	if err != nil {
@@ -124,7 +164,24 @@ func NewStatisticsPage(state abstract.GlobalState, filePath string) (*Statistics
 	collectorDropdown.Value = options[0]
 	collectorDropdown.SetOptions(options)
 
+	textDropdown, err := components.NewDropdown("Copy as text", abstract.TextPlain, abstract.TextMarkdown, abstract.TextChatLine)
+
+	if err != nil {
+		return nil, err
+	}
+
+	characterDropdown, err := components.NewDropdown("Character", characterChoice(""))
+
+	if err != nil {
+		return nil, err
+	}
+
+	identity, _ := state.FindIdentity(filePath)
+
 	return &StatisticsPage{
+		watch:             watch,
+		timeFrames:        timeFrames,
+		savedIdentity:     identity,
 		filePath:          filePath,
 		modalLayer:        components.NewModalLayer(),
 		backIcon:          backIcon,
@@ -138,7 +195,15 @@ func NewStatisticsPage(state abstract.GlobalState, filePath string) (*Statistics
 		unlockIcon:        unlockIcon,
 		copyIcon:          copyIcon,
 		copyButton:        &widget.Clickable{},
+		sendIcon:          sendIcon,
+		sendButton:        &widget.Clickable{},
+		textIcon:          textIcon,
+		textButton:        &widget.Clickable{},
+		textDropdown:      textDropdown,
+		identityIcon:      identityIcon,
+		identityButton:    &widget.Clickable{},
 		collectorDropdown: collectorDropdown,
+		characterDropdown: characterDropdown,
 		collectorBody:     getFreshCollectorBody(),
 		windowedButton:    &widget.Clickable{},
 		windowedIcon:      windowedIcon,
@@ -194,6 +259,25 @@ func (s *StatisticsPage) goBack(state abstract.GlobalState) {
 	state.SwitchPage(NewMarkersPage(s.filePath, markers))
 }
 
+// correctIdentity Saves manually set character name for the file and collects everything again with it
+func (s *StatisticsPage) correctIdentity(state abstract.GlobalState, name string) {
+	identity := abstract.FileIdentity{
+		Path:   s.filePath,
+		Name:   name,
+		Source: abstract.IdentityManual,
+	}
+
+	state.SaveIdentity(identity)
+	s.savedIdentity = identity
+
+	if state.OpenFile(s.filePath, s.watch, s.timeFrames) {
+		s.knownCharacters = 0
+		s.characterDropdown.SetOptions([]fmt.Stringer{characterChoice("")})
+		s.characterDropdown.SelectItem(0)
+		s.collectorBody = getFreshCollectorBody()
+	}
+}
+
 func (s *StatisticsPage) switchCollectorTab(newIndex int) {
 	s.currentCollector = newIndex
 	s.collectorBody = getFreshCollectorBody()
@@ -226,6 +310,12 @@ func (s *StatisticsPage) navBar(state abstract.LayeredState) layout.Widget {
 						utils.FlexSpacerW(utils.CommonSpacing),
 						layout.Rigid(navIconButton(state, s.copyButton, s.copyIcon, "Copy").Layout),
 						utils.FlexSpacerW(utils.CommonSpacing),
+						layout.Rigid(navIconButton(state, s.textButton, s.textIcon, "Copy as text").Layout),
+						utils.FlexSpacerW(utils.CommonSpacing),
+						layout.Rigid(navIconButton(state, s.sendButton, s.sendIcon, "Send to webhooks").Layout),
+						utils.FlexSpacerW(utils.CommonSpacing),
+						layout.Rigid(navIconButton(state, s.identityButton, s.identityIcon, "Character").Layout),
+						utils.FlexSpacerW(utils.CommonSpacing),
 						layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
 							if s.collectorDropdown.Changed() {
 								value := s.collectorDropdown.Value.(CollectorPageIndex)
@@ -241,6 +331,33 @@ func (s *StatisticsPage) navBar(state abstract.LayeredState) layout.Widget {
 							return style.Layout(gtx)
 						}),
 						utils.FlexSpacerW(utils.CommonSpacing),
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							if s.knownCharacters < 2 {
+								return layout.Dimensions{}
+							}
+
+							if s.characterDropdown.Changed() {
+								if stats := state.StatisticsCollector(); stats != nil {
+									stats.SelectCharacter(string(s.characterDropdown.Value.(characterChoice)))
+									s.collectorBody = getFreshCollectorBody()
+								}
+							}
+
+							style := components.StyleDropdown(state.Theme(), state.ModalLayer(), s.characterDropdown)
+							style.NoLabel = true
+							style.Inset = layout.UniformInset(7)
+							style.TextSize = 12
+							style.DialogTextSize = 12
+							style.MaxWidth = 100
+
+							return layout.Flex{
+								Axis: layout.Horizontal,
+							}.Layout(
+								gtx,
+								layout.Rigid(style.Layout),
+								utils.FlexSpacerW(utils.CommonSpacing),
+							)
+						}),
 						layout.Rigid(navIconButton(state, s.windowedButton, s.windowedIcon, "Windowed").Layout),
 						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 							return layout.Stack{
@@ -272,11 +389,9 @@ func (s *StatisticsPage) navBar(state abstract.LayeredState) layout.Widget {
 	}
 }
 
-func (s *StatisticsPage) exportToClipboard(state abstract.LayeredState, collector abstract.Collector) {
+func copyImageToClipboard(img image.Image) {
 	buf := &bytes.Buffer{}
 
-	img := collector.Export(state)
-
 	err := png.Encode(buf, img)
 	if err != nil {
 		log.Println("Failed to export image", err)
@@ -286,6 +401,21 @@ func (s *StatisticsPage) exportToClipboard(state abstract.LayeredState, collecto
 	clipboard.Write(clipboard.FmtImage, buf.Bytes())
 }
 
+func (s *StatisticsPage) exportToClipboard(state abstract.LayeredState, collector abstract.Collector) {
+	copyImageToClipboard(collector.Export(state))
+}
+
+func (s *StatisticsPage) copyTextToClipboard(state abstract.LayeredState, collector abstract.Collector, format abstract.TextFormat) {
+	text, ok := abstract.ExportText(state, collector, format)
+	if !ok {
+		state.ShowToast(fmt.Sprintf("%v tab can't be copied as text", collector.TabName()))
+		return
+	}
+
+	clipboard.Write(clipboard.FmtText, []byte(text))
+	state.ShowToast(fmt.Sprintf("Copied as %v", strings.ToLower(format.String())))
+}
+
 func (s *StatisticsPage) body(state abstract.LayeredState, currentCollector abstract.Collector) layout.Widget {
 	return func(gtx layout.Context) layout.Dimensions {
 		top, body := currentCollector.UI(state)
@@ -341,6 +471,29 @@ func (s *StatisticsPage) Layout(ctx layout.Context, state abstract.GlobalState)
 		dialog.Open(s.modalLayer)
 	}
 
+	if s.identityButton.Clicked(ctx) {
+		var prompt string
+		if stats := state.StatisticsCollector(); stats != nil {
+			prompt = fmt.Sprintf("Currently: %v", stats.Identity())
+		}
+
+		dialog := components.NewInputDialog(
+			state.Theme(),
+			"Correct Character Name",
+			prompt,
+			"JohnDoe",
+			func(name string) {
+				if name == "" {
+					return
+				}
+
+				s.correctIdentity(state, name)
+			},
+		)
+		dialog.TextSize = 12
+		dialog.Open(s.modalLayer)
+	}
+
 	if s.resetButton.Clicked(ctx) {
 		if stats := state.StatisticsCollector(); stats != nil && stats.IsAlive() {
 			stats.Reset()
@@ -358,6 +511,22 @@ func (s *StatisticsPage) Layout(ctx layout.Context, state abstract.GlobalState)
 
 		collectors := stats.Collectors()
 
+		if identity := stats.Identity(); identity.BetterThan(s.savedIdentity) {
+			s.savedIdentity = identity
+			state.SaveIdentity(identity)
+		}
+
+		if characters := stats.Characters(); len(characters) != s.knownCharacters {
+			s.knownCharacters = len(characters)
+
+			options := make([]fmt.Stringer, 0, len(characters)+1)
+			options = append(options, characterChoice(""))
+			for _, character := range characters {
+				options = append(options, characterChoice(character))
+			}
+			s.characterDropdown.SetOptions(options)
+		}
+
 		if s.currentCollector < len(collectors) {
 			currentCollector := collectors[s.currentCollector]
 
@@ -365,6 +534,20 @@ func (s *StatisticsPage) Layout(ctx layout.Context, state abstract.GlobalState)
 				log.Println("trying to copy to clipboard")
 				s.exportToClipboard(layeredState, currentCollector)
 			}
+
+			if s.textButton.Clicked(gtx) {
+				style := components.StyleDropdown(state.Theme(), s.modalLayer, s.textDropdown)
+				style.DialogTextSize = 12
+				style.Open(s.modalLayer)
+			}
+
+			if s.textDropdown.Changed() {
+				s.copyTextToClipboard(layeredState, currentCollector, s.textDropdown.Value.(abstract.TextFormat))
+			}
+		}
+
+		if s.sendButton.Clicked(gtx) {
+			state.SendReport(abstract.ReportOnDemand, stats.SelectedCharacter(), s.filePath, collectors)
 		}
 
 		return layout.Flex{
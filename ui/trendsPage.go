@@ -0,0 +1,401 @@
+package ui
+
+import (
+	"PGCombatTracker/abstract"
+	"PGCombatTracker/ui/components"
+	"PGCombatTracker/utils"
+	"fmt"
+	"gioui.org/app"
+	"gioui.org/layout"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"golang.org/x/exp/shiny/materialdesign/icons"
+	"log"
+	"math"
+	"time"
+)
+
+// trendRebuildInterval How often charts are rebuilt while the library is still being scanned
+const trendRebuildInterval = 2 * time.Second
+
+type trendPoints uint8
+
+const (
+	TrendSessions trendPoints = iota
+	TrendWeeks
+)
+
+func (t trendPoints) String() string {
+	switch t {
+	case TrendSessions:
+		return "Every Session"
+	case TrendWeeks:
+		return "Weekly"
+	}
+
+	return ""
+}
+
+type trendMetric struct {
+	name  string
+	unit  string
+	value func(session abstract.SessionSummary) float64
+}
+
+var trendMetrics = []trendMetric{
+	{name: "DPS", unit: "DPS", value: abstract.SessionSummary.DPS},
+	{name: "Damage taken per hour", unit: "damage/h", value: abstract.SessionSummary.DamageTakenPerHour},
+	{name: "Deaths", unit: "deaths", value: func(session abstract.SessionSummary) float64 {
+		return float64(session.Deaths)
+	}},
+	{name: "XP per hour", unit: "XP/h", value: abstract.SessionSummary.XPPerHour},
+	{name: "Coins per hour", unit: "coins/h", value: abstract.SessionSummary.CoinsPerHour},
+}
+
+type trendDetail struct {
+	value int
+	unit  string
+}
+
+func (t trendDetail) StringCL(long bool) string {
+	if long {
+		return fmt.Sprintf("%d %v", t.value, t.unit)
+	}
+	return fmt.Sprintf("%v %v", utils.FormatNumber(t.value), t.unit)
+}
+
+func (t trendDetail) Interpolate(other utils.Interpolatable, interpolant float64) utils.Interpolatable {
+	otherDetail, ok := other.(trendDetail)
+	if !ok {
+		return other
+	}
+
+	return trendDetail{
+		value: utils.LerpInt(t.value, otherDetail.value, interpolant),
+		unit:  t.unit,
+	}
+}
+
+func (t trendDetail) InterpolateILF(other utils.InterpolatableLongFormatable, interpolant float64) utils.InterpolatableLongFormatable {
+	return t.Interpolate(other, interpolant).(utils.InterpolatableLongFormatable)
+}
+
+func weekStart(at time.Time) time.Time {
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, at.Location())
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// weeklySessions Merges sessions of every week into one that starts at monday
+func weeklySessions(sessions []abstract.SessionSummary) []abstract.SessionSummary {
+	var weeks []abstract.SessionSummary
+
+	for _, session := range sessions {
+		start := weekStart(session.From)
+
+		weeks = utils.CreateUpdate(
+			weeks,
+			func(week abstract.SessionSummary) bool {
+				return week.From.Equal(start)
+			},
+			func() abstract.SessionSummary {
+				week := session
+				week.From = start
+				week.To = start.Add(session.Duration())
+				return week
+			},
+			func(week abstract.SessionSummary) abstract.SessionSummary {
+				return week.Merge(session)
+			},
+		)
+	}
+
+	return weeks
+}
+
+type trendChart struct {
+	metric  trendMetric
+	chart   *components.TimeBasedChart
+	average float64
+	latest  float64
+}
+
+func newTrendChart(metric trendMetric, sessions []abstract.SessionSummary) trendChart {
+	trend := trendChart{
+		metric: metric,
+		chart:  components.NewTimeBasedChart(metric.name),
+	}
+
+	var frame components.TimeFrame
+	var valueRange components.DataRange
+	var total float64
+
+	for i, session := range sessions {
+		value := metric.value(session)
+		rounded := int(math.Round(value))
+		total += value
+		trend.latest = value
+
+		trend.chart.DataPoints = append(trend.chart.DataPoints, components.TimePoint{
+			Time:    session.From,
+			Value:   rounded,
+			Details: trendDetail{value: rounded, unit: metric.unit},
+		})
+
+		if i == 0 {
+			frame = components.TimeFrame{From: session.From, To: session.From}
+		} else {
+			frame = frame.Expand(session.From)
+		}
+		valueRange = valueRange.Expand(rounded)
+	}
+
+	if len(sessions) > 0 {
+		trend.average = total / float64(len(sessions))
+	}
+
+	// Single point would make a time frame without length
+	if frame.To.Sub(frame.From) < time.Hour {
+		frame.To = frame.From.Add(time.Hour)
+	}
+
+	trend.chart.DisplayTimeFrame = frame
+	trend.chart.DisplayValueRange = valueRange
+
+	return trend
+}
+
+func NewTrendsPage() *TrendsPage {
+	backIcon, err := widget.NewIcon(icons.NavigationArrowBack)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	refreshIcon, err := widget.NewIcon(icons.NavigationRefresh)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	characterDropdown, err := components.NewDropdown("Character", characterChoice(""))
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	pointsDropdown, err := components.NewDropdown("Points", TrendSessions, TrendWeeks)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	return &TrendsPage{
+		dirty:             true,
+		modalLayer:        components.NewModalLayer(),
+		backIcon:          backIcon,
+		backButton:        &widget.Clickable{},
+		refreshIcon:       refreshIcon,
+		refreshButton:     &widget.Clickable{},
+		characterDropdown: characterDropdown,
+		pointsDropdown:    pointsDropdown,
+		chartList: &widget.List{
+			List: layout.List{
+				Axis: layout.Vertical,
+			},
+		},
+	}
+}
+
+// TrendsPage Charts summaries from the session library over time
+type TrendsPage struct {
+	character   string
+	points      trendPoints
+	charts      []trendChart
+	sessions    []abstract.SessionSummary
+	dirty       bool
+	wasScanning bool
+	lastBuilt   time.Time
+
+	modalLayer        *components.ModalLayer
+	backIcon          *widget.Icon
+	backButton        *widget.Clickable
+	refreshIcon       *widget.Icon
+	refreshButton     *widget.Clickable
+	characterDropdown *components.Dropdown
+	pointsDropdown    *components.Dropdown
+	chartList         *widget.List
+}
+
+func (t *TrendsPage) rebuild(state abstract.GlobalState) {
+	t.dirty = false
+	t.lastBuilt = time.Now()
+
+	characters := state.LibraryCharacters()
+	options := make([]fmt.Stringer, 0, len(characters)+1)
+	options = append(options, characterChoice(""))
+	for _, character := range characters {
+		options = append(options, characterChoice(character))
+	}
+	t.characterDropdown.SetOptions(options)
+
+	t.sessions = state.LibrarySessions(t.character)
+
+	sessions := t.sessions
+	if t.points == TrendWeeks {
+		sessions = weeklySessions(sessions)
+	}
+
+	t.charts = make([]trendChart, len(trendMetrics))
+	for i, metric := range trendMetrics {
+		t.charts[i] = newTrendChart(metric, sessions)
+	}
+}
+
+func (t *TrendsPage) navBar(state abstract.LayeredState) layout.Widget {
+	return func(gtx layout.Context) layout.Dimensions {
+		if t.backButton.Clicked(gtx) {
+			state.SwitchPage(NewFileSelectionPage())
+		}
+
+		if t.refreshButton.Clicked(gtx) {
+			state.ScanLibrary()
+		}
+
+		if t.characterDropdown.Changed() {
+			t.character = string(t.characterDropdown.Value.(characterChoice))
+			t.dirty = true
+		}
+
+		if t.pointsDropdown.Changed() {
+			t.points = t.pointsDropdown.Value.(trendPoints)
+			t.dirty = true
+		}
+
+		return layout.Background{}.Layout(
+			gtx,
+			utils.MakeColoredBG(utils.SecondBG),
+			func(gtx layout.Context) layout.Dimensions {
+				return layout.UniformInset(utils.CommonSpacing).Layout(
+					gtx,
+					func(gtx layout.Context) layout.Dimensions {
+						return layout.Flex{
+							Axis:      layout.Horizontal,
+							Alignment: layout.Middle,
+						}.Layout(
+							gtx,
+							layout.Rigid(material.IconButton(state.Theme(), t.backButton, t.backIcon, "Back").Layout),
+							utils.FlexSpacerW(utils.CommonSpacing*2),
+							layout.Rigid(material.H4(state.Theme(), "Trends").Layout),
+							utils.FlexSpacerW(utils.CommonSpacing*2),
+							layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+								return components.HorizontalWrap{
+									Alignment:   layout.Middle,
+									Spacing:     utils.CommonSpacing,
+									LineSpacing: utils.CommonSpacing,
+								}.Layout(
+									gtx,
+									components.StyleDropdown(state.Theme(), state.ModalLayer(), t.characterDropdown).Layout,
+									components.StyleDropdown(state.Theme(), state.ModalLayer(), t.pointsDropdown).Layout,
+								)
+							}),
+							layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+								if state.LibraryScanning() {
+									return utils.WithColor(material.Body2(state.Theme(), "Scanning logs..."), utils.GrayText).Layout(gtx)
+								}
+
+								return material.IconButton(state.Theme(), t.refreshButton, t.refreshIcon, "Scan Logs").Layout(gtx)
+							}),
+						)
+					},
+				)
+			},
+		)
+	}
+}
+
+func (t *TrendsPage) body(state abstract.LayeredState) layout.Widget {
+	return func(gtx layout.Context) layout.Dimensions {
+		scanning := state.LibraryScanning()
+		if scanning != t.wasScanning || (scanning && time.Since(t.lastBuilt) > trendRebuildInterval) {
+			t.wasScanning = scanning
+			t.dirty = true
+		}
+
+		if t.dirty {
+			t.rebuild(state)
+		}
+
+		if len(t.sessions) == 0 {
+			return layout.UniformInset(utils.CommonSpacing*2).Layout(
+				gtx,
+				material.Body1(state.Theme(), "No sessions in the library yet, they are added as the logs folder gets scanned").Layout,
+			)
+		}
+
+		widgets := []layout.Widget{
+			utils.WithColor(material.Body2(state.Theme(), fmt.Sprintf(
+				"%d sessions from %v to %v",
+				len(t.sessions),
+				t.sessions[0].From.Format(time.DateOnly),
+				t.sessions[len(t.sessions)-1].To.Format(time.DateOnly),
+			)), utils.GrayText).Layout,
+		}
+
+		for _, trend := range t.charts {
+			widgets = append(
+				widgets,
+				material.Body1(state.Theme(), fmt.Sprintf(
+					"%v: average %v, latest %v",
+					trend.metric.name,
+					formatComparisonValue(math.Round(trend.average*10)/10),
+					formatComparisonValue(math.Round(trend.latest*10)/10),
+				)).Layout,
+				func(gtx layout.Context) layout.Dimensions {
+					style := components.StyleTimeBasedChart(state.Theme(), trend.chart)
+					style.Color = components.StringToColor(trend.metric.name)
+					style.MinHeight = 150
+
+					return style.Layout(gtx)
+				},
+			)
+		}
+
+		return layout.UniformInset(utils.CommonSpacing).Layout(
+			gtx,
+			func(gtx layout.Context) layout.Dimensions {
+				return material.List(state.Theme(), t.chartList).Layout(
+					gtx,
+					len(widgets),
+					func(gtx layout.Context, index int) layout.Dimensions {
+						return layout.Flex{
+							Axis: layout.Vertical,
+						}.Layout(
+							gtx,
+							layout.Rigid(widgets[index]),
+							utils.FlexSpacerH(utils.CommonSpacing),
+						)
+					},
+				)
+			},
+		)
+	}
+}
+
+func (t *TrendsPage) Layout(ctx layout.Context, state abstract.GlobalState) error {
+	layeredState := NewLayeredState(state, t.modalLayer)
+
+	t.modalLayer.Overlay(func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{
+			Axis: layout.Vertical,
+		}.Layout(
+			gtx,
+			layout.Rigid(t.navBar(layeredState)),
+			layout.Flexed(1, t.body(layeredState)),
+		)
+	})(ctx)
+
+	return nil
+}
+
+func (t *TrendsPage) SetupWindow(state abstract.GlobalState) {
+	state.Window().Option(
+		app.MinSize(800, 600),
+		app.Decorated(true),
+	)
+}
@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"PGCombatTracker/abstract"
+	"PGCombatTracker/ui/components"
+	"PGCombatTracker/utils"
+	"fmt"
+	"gioui.org/app"
+	"gioui.org/layout"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"golang.org/x/exp/shiny/materialdesign/icons"
+	"log"
+	"time"
+)
+
+func NewWebhooksPage() *WebhooksPage {
+	backIcon, err := widget.NewIcon(icons.NavigationArrowBack)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	return &WebhooksPage{
+		modalLayer: components.NewModalLayer(),
+		backIcon:   backIcon,
+		backButton: &widget.Clickable{},
+		deliveryList: &widget.List{
+			List: layout.List{
+				Axis: layout.Vertical,
+			},
+		},
+	}
+}
+
+// WebhooksPage Send log of reports posted to webhooks since the app started
+type WebhooksPage struct {
+	modalLayer   *components.ModalLayer
+	backIcon     *widget.Icon
+	backButton   *widget.Clickable
+	deliveryList *widget.List
+}
+
+func (w *WebhooksPage) navBar(state abstract.LayeredState) layout.Widget {
+	return func(gtx layout.Context) layout.Dimensions {
+		if w.backButton.Clicked(gtx) {
+			state.SwitchPage(NewFileSelectionPage())
+		}
+
+		return layout.Background{}.Layout(
+			gtx,
+			utils.MakeColoredBG(utils.SecondBG),
+			func(gtx layout.Context) layout.Dimensions {
+				return layout.UniformInset(utils.CommonSpacing).Layout(
+					gtx,
+					func(gtx layout.Context) layout.Dimensions {
+						return layout.Flex{
+							Axis:      layout.Horizontal,
+							Alignment: layout.Middle,
+						}.Layout(
+							gtx,
+							layout.Rigid(material.IconButton(state.Theme(), w.backButton, w.backIcon, "Back").Layout),
+							utils.FlexSpacerW(utils.CommonSpacing*2),
+							layout.Rigid(material.H4(state.Theme(), "Webhook Log").Layout),
+						)
+					},
+				)
+			},
+		)
+	}
+}
+
+func deliveryRow(state abstract.LayeredState, delivery abstract.WebhookDelivery) layout.Widget {
+	outcome := utils.WithColor(material.Body1(state.Theme(), "Sent"), utils.GreenText)
+	if !delivery.Succeeded() {
+		outcome = utils.WithColor(material.Body1(state.Theme(), "Failed"), utils.RedText)
+	}
+
+	details := fmt.Sprintf("%v, %d attempts", delivery.At.Format(time.DateTime), delivery.Attempts)
+	if delivery.Status != 0 {
+		details = fmt.Sprintf("%v, status %d", details, delivery.Status)
+	}
+
+	return func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{
+			Axis: layout.Vertical,
+		}.Layout(
+			gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{
+					Axis:      layout.Horizontal,
+					Alignment: layout.Baseline,
+				}.Layout(
+					gtx,
+					layout.Rigid(outcome.Layout),
+					utils.FlexSpacerW(utils.CommonSpacing*2),
+					layout.Flexed(1, material.Body1(state.Theme(), fmt.Sprintf("%v to %v", delivery.Trigger, delivery.Webhook)).Layout),
+					utils.FlexSpacerW(utils.CommonSpacing*2),
+					layout.Rigid(utils.WithColor(material.Body2(state.Theme(), details), utils.GrayText).Layout),
+				)
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if delivery.Succeeded() {
+					return layout.Dimensions{}
+				}
+
+				return utils.WithColor(material.Body2(state.Theme(), delivery.Error), utils.GrayText).Layout(gtx)
+			}),
+		)
+	}
+}
+
+func (w *WebhooksPage) body(state abstract.LayeredState) layout.Widget {
+	return func(gtx layout.Context) layout.Dimensions {
+		deliveries := state.WebhookDeliveries()
+
+		if len(deliveries) == 0 {
+			return layout.UniformInset(utils.CommonSpacing*2).Layout(
+				gtx,
+				material.Body1(state.Theme(), "Nothing was sent yet, reports are sent from the statistics page or when sessions and encounters end").Layout,
+			)
+		}
+
+		return layout.UniformInset(utils.CommonSpacing).Layout(
+			gtx,
+			func(gtx layout.Context) layout.Dimensions {
+				return material.List(state.Theme(), w.deliveryList).Layout(
+					gtx,
+					len(deliveries),
+					func(gtx layout.Context, index int) layout.Dimensions {
+						return layout.UniformInset(utils.CommonSpacing/2).Layout(gtx, deliveryRow(state, deliveries[index]))
+					},
+				)
+			},
+		)
+	}
+}
+
+func (w *WebhooksPage) Layout(ctx layout.Context, state abstract.GlobalState) error {
+	layeredState := NewLayeredState(state, w.modalLayer)
+
+	w.modalLayer.Overlay(func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{
+			Axis: layout.Vertical,
+		}.Layout(
+			gtx,
+			layout.Rigid(w.navBar(layeredState)),
+			layout.Flexed(1, w.body(layeredState)),
+		)
+	})(ctx)
+
+	return nil
+}
+
+func (w *WebhooksPage) SetupWindow(state abstract.GlobalState) {
+	state.Window().Option(
+		app.MinSize(800, 600),
+		app.Decorated(true),
+	)
+}
@@ -13,7 +13,10 @@ var HalfBG = color.NRGBA{R: 32, G: 32, B: 32, A: 255}
 var SecondBG = color.NRGBA{R: 40, G: 40, B: 40, A: 255}
 var GrayText = color.NRGBA{R: 140, G: 140, B: 140, A: 255}
 var RedText = color.NRGBA{R: 255, G: 50, B: 50, A: 255}
+var GreenText = color.NRGBA{R: 50, G: 220, B: 50, A: 255}
+var AlertBG = color.NRGBA{R: 110, G: 60, B: 20, A: 255}
 var ChartLineColor = color.NRGBA{R: 255, G: 255, B: 255, A: 20}
+var ChartMarkColor = color.NRGBA{R: 255, G: 255, B: 255, A: 160}
 var ChartSelectionColor = color.NRGBA{G: 255, B: 255, A: 50}
 
 var RandomColorBase = 60
@@ -16,6 +16,7 @@ func StyleAreaChart(chart *components.TimeBasedChart, color color.NRGBA) SingleA
 		Color:           color,
 		Alpha:           255,
 		BackgroundAlpha: 120,
+		MarkColor:       utils.ChartMarkColor,
 		MinHeight:       300,
 	}
 }
@@ -25,6 +26,7 @@ type SingleAreaChart struct {
 	Color           color.NRGBA
 	Alpha           uint8
 	BackgroundAlpha uint8
+	MarkColor       color.NRGBA
 	MinHeight       float64
 }
 
@@ -82,6 +84,19 @@ func (ac SingleAreaChart) Layout() Widget {
 					gg.Fill()
 				}
 
+				// Marks
+				gg.SetColor(ac.MarkColor)
+				gg.SetLineWidth(2)
+				for _, mark := range ac.Chart.Marks {
+					if !ac.Chart.DisplayTimeFrame.Within(mark) {
+						continue
+					}
+
+					markX := size.X * ac.Chart.DisplayTimeFrame.ProportionOfTarget(mark)
+					gg.DrawLine(markX, 0, markX, size.Y)
+					gg.Stroke()
+				}
+
 				gg.ResetClip()
 
 				gg.Pop()
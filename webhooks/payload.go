@@ -0,0 +1,133 @@
+package webhooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxContent Discord refuses messages longer than this
+	maxContent = 2000
+	// maxFiles Discord refuses more attachments than this
+	maxFiles = 10
+	// reportFile Name of the attachment with the whole text of reports that are too long for a message
+	reportFile = "report.txt"
+)
+
+type attachment struct {
+	ID       int    `json:"id"`
+	Filename string `json:"filename"`
+}
+
+type payloadJSON struct {
+	Content     string       `json:"content"`
+	Attachments []attachment `json:"attachments"`
+}
+
+func truncate(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+
+	runes := []rune(text)
+	return string(runes[:limit-1]) + "…"
+}
+
+func fileName(name string, index int) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		}
+		return '-'
+	}, name)
+
+	if name == "" {
+		name = fmt.Sprintf("export-%d", index)
+	}
+
+	return name + ".png"
+}
+
+// file Attachment of the message, sent as a "files[n]" part of the body
+type file struct {
+	name        string
+	contentType string
+	data        []byte
+}
+
+// payload Multipart body the way Discord webhooks take it, message in "payload_json" and images as "files[n]",
+// text that doesn't fit into a message is cut short there and comes along whole as a file
+func payload(content string, images []Image) ([]byte, string, error) {
+	long := utf8.RuneCountInString(content) > maxContent
+
+	imageLimit := maxFiles
+	if long {
+		imageLimit--
+	}
+	if len(images) > imageLimit {
+		images = images[:imageLimit]
+	}
+
+	files := make([]file, 0, len(images)+1)
+	for i, image := range images {
+		files = append(files, file{name: fileName(image.Name, i), contentType: "image/png", data: image.PNG})
+	}
+	if long {
+		files = append(files, file{name: reportFile, contentType: "text/plain; charset=utf-8", data: []byte(content)})
+	}
+
+	message := payloadJSON{
+		Content:     truncate(content, maxContent),
+		Attachments: make([]attachment, len(files)),
+	}
+	for i, file := range files {
+		message.Attachments[i] = attachment{ID: i, Filename: file.name}
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	encoded, err := json.Marshal(message)
+	if err != nil {
+		return nil, "", err
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="payload_json"`)
+	header.Set("Content-Type", "application/json")
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err = part.Write(encoded); err != nil {
+		return nil, "", err
+	}
+
+	for i, file := range files {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="files[%d]"; filename="%v"`, i, file.name))
+		header.Set("Content-Type", file.contentType)
+
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err = part.Write(file.data); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err = writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return body.Bytes(), writer.FormDataContentType(), nil
+}
@@ -0,0 +1,203 @@
+package webhooks
+
+import (
+	"PGCombatTracker/abstract"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	maxAttempts = 3
+	// retryDelay Delay before the second attempt, doubled after every failed one
+	retryDelay = 2 * time.Second
+	// maxRetryAfter Longer waits asked for by rate limits are not worth holding the queue for
+	maxRetryAfter = time.Minute
+	// keptDeliveries Size of the send log
+	keptDeliveries = 100
+	queueSize      = 16
+)
+
+type job struct {
+	webhook Webhook
+	report  Report
+}
+
+func NewSender(onDelivery func(delivery abstract.WebhookDelivery)) *Sender {
+	sender := &Sender{
+		OnDelivery: onDelivery,
+		client:     &http.Client{Timeout: 30 * time.Second},
+		retryDelay: retryDelay,
+		queue:      make(chan job, queueSize),
+		lock:       new(sync.Mutex),
+	}
+
+	go sender.run()
+
+	return sender
+}
+
+// Sender Posts reports one after another in background, retrying failed ones and keeping a log of how it went
+type Sender struct {
+	// OnDelivery Called from the sender's goroutine after every report is done, successful or not
+	OnDelivery func(delivery abstract.WebhookDelivery)
+
+	client     *http.Client
+	retryDelay time.Duration
+	queue      chan job
+	lock       *sync.Mutex
+	deliveries []abstract.WebhookDelivery
+}
+
+// Send Queues the report for every webhook, reports are dropped when too many are waiting
+func (s *Sender) Send(webhooks []Webhook, report Report) {
+	for _, webhook := range webhooks {
+		select {
+		case s.queue <- job{webhook: webhook, report: report}:
+		default:
+			s.finish(abstract.WebhookDelivery{
+				At:      time.Now(),
+				Webhook: webhook.Redacted(),
+				Trigger: report.Trigger,
+				Error:   "too many reports are waiting to be sent",
+			})
+		}
+	}
+}
+
+// Deliveries Send log, newest first
+func (s *Sender) Deliveries() []abstract.WebhookDelivery {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	deliveries := slices.Clone(s.deliveries)
+	slices.Reverse(deliveries)
+	return deliveries
+}
+
+func (s *Sender) finish(delivery abstract.WebhookDelivery) {
+	if delivery.Succeeded() {
+		log.Printf("Sent %v to %v\n", delivery.Trigger, delivery.Webhook)
+	} else {
+		log.Printf("Failed to send %v to %v: %v\n", delivery.Trigger, delivery.Webhook, delivery.Error)
+	}
+
+	s.lock.Lock()
+	s.deliveries = append(s.deliveries, delivery)
+	if len(s.deliveries) > keptDeliveries {
+		s.deliveries = slices.Delete(s.deliveries, 0, len(s.deliveries)-keptDeliveries)
+	}
+	s.lock.Unlock()
+
+	if s.OnDelivery != nil {
+		s.OnDelivery(delivery)
+	}
+}
+
+func (s *Sender) run() {
+	for next := range s.queue {
+		s.finish(s.deliver(next))
+	}
+}
+
+// retryAfter Wait asked for by a rate limited answer, zero if there isn't one
+func retryAfter(response *http.Response) time.Duration {
+	seconds, err := strconv.ParseFloat(response.Header.Get("Retry-After"), 64)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+
+	return min(time.Duration(seconds*float64(time.Second)), maxRetryAfter)
+}
+
+func (s *Sender) deliver(next job) abstract.WebhookDelivery {
+	delivery := abstract.WebhookDelivery{
+		At:      time.Now(),
+		Webhook: next.webhook.Redacted(),
+		Trigger: next.report.Trigger,
+	}
+
+	content, err := next.webhook.content(next.report)
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+
+	body, contentType, err := payload(content, next.report.Images)
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+
+	delay := s.retryDelay
+	for delivery.Attempts < maxAttempts {
+		if delivery.Attempts > 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+		delivery.Attempts++
+
+		var retry bool
+		delivery.Status, retry, err = s.post(next.webhook.URL, contentType, body, &delay)
+		if err == nil {
+			delivery.Error = ""
+			return delivery
+		}
+
+		delivery.Error = err.Error()
+		if !retry {
+			break
+		}
+	}
+
+	return delivery
+}
+
+// withoutAddress Errors about requests quote the whole address, which has the webhook's token in it
+func withoutAddress(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+
+	return err
+}
+
+// post Makes a single attempt, rate limits can make the next attempt wait longer
+func (s *Sender) post(address, contentType string, body []byte, delay *time.Duration) (status int, retry bool, err error) {
+	request, err := http.NewRequest(http.MethodPost, address, bytes.NewReader(body))
+	if err != nil {
+		return 0, false, withoutAddress(err)
+	}
+	request.Header.Set("Content-Type", contentType)
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return 0, true, withoutAddress(err)
+	}
+	defer response.Body.Close()
+
+	answer, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+
+	switch {
+	case response.StatusCode >= 200 && response.StatusCode < 300:
+		return response.StatusCode, false, nil
+	case response.StatusCode == http.StatusTooManyRequests:
+		if wait := retryAfter(response); wait > 0 {
+			*delay = wait
+		}
+		return response.StatusCode, true, fmt.Errorf("rate limited: %s", answer)
+	case response.StatusCode >= 500:
+		return response.StatusCode, true, fmt.Errorf("server error %d: %s", response.StatusCode, answer)
+	}
+
+	return response.StatusCode, false, fmt.Errorf("refused with %d: %s", response.StatusCode, answer)
+}
@@ -0,0 +1,269 @@
+package webhooks
+
+import (
+	"PGCombatTracker/abstract"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testToken = "secret-token"
+
+// testSender Sender that doesn't run in background and barely waits between attempts
+func testSender() *Sender {
+	return &Sender{
+		client:     &http.Client{Timeout: 5 * time.Second},
+		retryDelay: time.Millisecond,
+		lock:       new(sync.Mutex),
+	}
+}
+
+func testJob(t *testing.T, address string, images ...Image) job {
+	t.Helper()
+
+	webhooks := Parse([]string{address + "/api/webhooks/1/" + testToken + " | {{.Character}}: {{.Summary}}"})
+	if len(webhooks) != 1 {
+		t.Fatalf("expected the webhook to be parsed, got %d", len(webhooks))
+	}
+
+	return job{
+		webhook: webhooks[0],
+		report: Report{
+			Trigger:   abstract.ReportOnDemand,
+			Character: "Tester",
+			Summary:   "XP gained: 100",
+			Images:    images,
+		},
+	}
+}
+
+// answers Server that answers requests with the statuses in order, the last one is repeated
+func answers(t *testing.T, statuses ...int) (*httptest.Server, *[]time.Time) {
+	t.Helper()
+
+	var lock sync.Mutex
+	var requests []time.Time
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		requests = append(requests, time.Now())
+		status := statuses[min(len(requests), len(statuses))-1]
+		lock.Unlock()
+
+		if status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "0.2")
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestDeliverRetriesServerErrors(t *testing.T) {
+	server, requests := answers(t, http.StatusBadGateway, http.StatusInternalServerError, http.StatusNoContent)
+
+	delivery := testSender().deliver(testJob(t, server.URL))
+
+	if !delivery.Succeeded() {
+		t.Fatalf("expected the third attempt to succeed, got %q", delivery.Error)
+	}
+	if delivery.Attempts != 3 || len(*requests) != 3 {
+		t.Errorf("expected 3 attempts, got %d with %d requests", delivery.Attempts, len(*requests))
+	}
+	if delivery.Status != http.StatusNoContent {
+		t.Errorf("expected status %d, got %d", http.StatusNoContent, delivery.Status)
+	}
+}
+
+func TestDeliverGivesUpAfterServerErrors(t *testing.T) {
+	server, requests := answers(t, http.StatusServiceUnavailable)
+
+	delivery := testSender().deliver(testJob(t, server.URL))
+
+	if delivery.Succeeded() {
+		t.Fatal("expected the delivery to fail")
+	}
+	if delivery.Attempts != maxAttempts || len(*requests) != maxAttempts {
+		t.Errorf("expected %d attempts, got %d with %d requests", maxAttempts, delivery.Attempts, len(*requests))
+	}
+}
+
+func TestDeliverWaitsForRetryAfter(t *testing.T) {
+	server, requests := answers(t, http.StatusTooManyRequests, http.StatusOK)
+
+	delivery := testSender().deliver(testJob(t, server.URL))
+
+	if !delivery.Succeeded() {
+		t.Fatalf("expected the second attempt to succeed, got %q", delivery.Error)
+	}
+	if delivery.Attempts != 2 || len(*requests) != 2 {
+		t.Fatalf("expected 2 attempts, got %d with %d requests", delivery.Attempts, len(*requests))
+	}
+	if waited := (*requests)[1].Sub((*requests)[0]); waited < 200*time.Millisecond {
+		t.Errorf("expected to wait for Retry-After, waited %v", waited)
+	}
+}
+
+func TestDeliverDoesNotRetryClientErrors(t *testing.T) {
+	server, requests := answers(t, http.StatusBadRequest, http.StatusOK)
+
+	delivery := testSender().deliver(testJob(t, server.URL))
+
+	if delivery.Succeeded() {
+		t.Fatal("expected the delivery to fail")
+	}
+	if delivery.Attempts != 1 || len(*requests) != 1 {
+		t.Errorf("expected a single attempt, got %d with %d requests", delivery.Attempts, len(*requests))
+	}
+	if delivery.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, delivery.Status)
+	}
+}
+
+func TestDeliverErrorsLeaveOutToken(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	address := server.URL
+	server.Close()
+
+	delivery := testSender().deliver(testJob(t, address))
+
+	if delivery.Succeeded() {
+		t.Fatal("expected the delivery to fail")
+	}
+	if strings.Contains(delivery.Error, testToken) || strings.Contains(delivery.Webhook, testToken) {
+		t.Errorf("expected the token to be left out, got %q for %q", delivery.Error, delivery.Webhook)
+	}
+}
+
+func TestDeliverSendsMultipart(t *testing.T) {
+	type part struct {
+		fileName string
+		content  string
+	}
+	parts := make(map[string]part)
+	var message payloadJSON
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reader, err := r.MultipartReader()
+		if err != nil {
+			t.Errorf("expected a multipart body: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		for {
+			next, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Errorf("failed to read part: %v", err)
+				break
+			}
+
+			content, _ := io.ReadAll(next)
+			parts[next.FormName()] = part{fileName: next.FileName(), content: string(content)}
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	delivery := testSender().deliver(testJob(
+		t, server.URL,
+		Image{Name: "Damage Dealt", PNG: []byte("first")},
+		Image{Name: "XP Gained", PNG: []byte("second")},
+	))
+
+	if !delivery.Succeeded() {
+		t.Fatalf("expected the delivery to succeed, got %q", delivery.Error)
+	}
+
+	payloadPart, ok := parts["payload_json"]
+	if !ok {
+		t.Fatal("expected a payload_json part")
+	}
+	if err := json.Unmarshal([]byte(payloadPart.content), &message); err != nil {
+		t.Fatalf("expected payload_json to be JSON: %v", err)
+	}
+	if message.Content != "Tester: XP gained: 100" {
+		t.Errorf("expected content from the template, got %q", message.Content)
+	}
+	if len(message.Attachments) != 2 {
+		t.Fatalf("expected 2 attachments, got %d", len(message.Attachments))
+	}
+
+	expected := []part{
+		{fileName: "damage-dealt.png", content: "first"},
+		{fileName: "xp-gained.png", content: "second"},
+	}
+	for i, want := range expected {
+		name := fmt.Sprintf("files[%d]", i)
+		got, ok := parts[name]
+		if !ok {
+			t.Errorf("expected a %v part", name)
+			continue
+		}
+		if got != want {
+			t.Errorf("expected %v to be %+v, got %+v", name, want, got)
+		}
+		if message.Attachments[i].ID != i || message.Attachments[i].Filename != want.fileName {
+			t.Errorf("expected attachment %d to describe %v, got %+v", i, want.fileName, message.Attachments[i])
+		}
+	}
+}
+
+func TestDeliverAttachesLongContent(t *testing.T) {
+	parts := make(map[string]string)
+	var message payloadJSON
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reader, err := r.MultipartReader()
+		if err != nil {
+			t.Errorf("expected a multipart body: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		for {
+			next, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+
+			content, _ := io.ReadAll(next)
+			parts[next.FormName()] = string(content)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	next := testJob(t, server.URL, Image{Name: "Damage Dealt", PNG: []byte("first")})
+	next.report.Summary = strings.Repeat("Damage dealt: 12345\n", 200)
+
+	delivery := testSender().deliver(next)
+
+	if !delivery.Succeeded() {
+		t.Fatalf("expected the delivery to succeed, got %q", delivery.Error)
+	}
+	if err := json.Unmarshal([]byte(parts["payload_json"]), &message); err != nil {
+		t.Fatalf("expected payload_json to be JSON: %v", err)
+	}
+	if length := len([]rune(message.Content)); length > maxContent {
+		t.Errorf("expected content to fit into %d characters, got %d", maxContent, length)
+	}
+	if len(message.Attachments) != 2 || message.Attachments[1].Filename != reportFile {
+		t.Fatalf("expected the image and %v to be attached, got %+v", reportFile, message.Attachments)
+	}
+	if parts["files[1]"] != "Tester: "+next.report.Summary {
+		t.Errorf("expected %v to have the whole text, got %d bytes", reportFile, len(parts["files[1]"]))
+	}
+}
@@ -0,0 +1,94 @@
+package webhooks
+
+import (
+	"PGCombatTracker/abstract"
+	"bytes"
+	"log"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const defaultTemplate = "**{{.Trigger}}** for {{.Character}} in {{.File}}\n{{.Summary}}"
+
+// Image Exported tab encoded as PNG
+type Image struct {
+	Name string
+	PNG  []byte
+}
+
+// Report Everything that gets posted, images are exported before the report is queued
+type Report struct {
+	Trigger   abstract.ReportTrigger
+	Character string
+	File      string
+	At        time.Time
+	Summary   string
+	Images    []Image
+}
+
+type Webhook struct {
+	URL      string
+	template *template.Template
+}
+
+// Parse Webhooks look like "URL" or "URL | template", broken ones are logged and left out
+func Parse(rules []string) []Webhook {
+	var webhooks []Webhook
+
+	for _, rule := range rules {
+		address, text, found := strings.Cut(rule, "|")
+		address = strings.TrimSpace(address)
+		text = strings.TrimSpace(text)
+		if !found || text == "" {
+			text = defaultTemplate
+		}
+
+		parsed, err := url.Parse(address)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			log.Printf("Ignoring webhook '%v', expected an http or https address\n", Webhook{URL: address}.Redacted())
+			continue
+		}
+
+		// Templates are written in a single line of settings, so "\n" stands for a line break
+		tmpl, err := template.New("webhook").Parse(strings.ReplaceAll(text, `\n`, "\n"))
+		if err != nil {
+			log.Printf("Ignoring webhook '%v': %v\n", Webhook{URL: address}.Redacted(), err)
+			continue
+		}
+
+		webhooks = append(webhooks, Webhook{
+			URL:      address,
+			template: tmpl,
+		})
+	}
+
+	return webhooks
+}
+
+func (w Webhook) content(report Report) (string, error) {
+	var buffer bytes.Buffer
+
+	err := w.template.Execute(&buffer, report)
+	if err != nil {
+		return "", err
+	}
+
+	return buffer.String(), nil
+}
+
+// Redacted Address without the path after the first two parts, which is where webhook tokens live
+func (w Webhook) Redacted() string {
+	parsed, err := url.Parse(w.URL)
+	if err != nil {
+		return "webhook"
+	}
+
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(parts) > 2 {
+		parts = append(parts[:2], "...")
+	}
+
+	return parsed.Host + "/" + strings.Join(parts, "/")
+}